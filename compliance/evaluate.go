@@ -0,0 +1,246 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// Compliance status of a component
+const (
+	StatusCompliant    = "compliant"
+	StatusNonCompliant = "non-compliant"
+	// StatusUnknown is returned when the installed firmware version is unset or cannot be compared
+	StatusUnknown = "unknown"
+)
+
+// Result is the compliance of a Device component to the rule matching it
+type Result struct {
+	// Slug and Identity identify the component as in common.ComponentRef
+	Slug      string `json:"slug"`
+	Identity  string `json:"identity,omitempty"`
+	Vendor    string `json:"vendor,omitempty"`
+	Model     string `json:"model,omitempty"`
+	Installed string `json:"installed,omitempty"`
+	Status    string `json:"status"`
+	// Available is the version to upgrade to, set when it is greater than the installed version
+	Available string `json:"available,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Rule      *Rule  `json:"-"`
+
+	ref *common.ComponentRef
+}
+
+// Compliant returns true when all the results are compliant
+func Compliant(results []*Result) bool {
+	for _, r := range results {
+		if r.Status != StatusCompliant {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Evaluate returns the compliance of the Device components matched by a rule in the Policy,
+// components without a matching rule are not listed. The Device is not modified, a nil Device
+// has no results.
+func (p *Policy) Evaluate(d *common.Device) []*Result {
+	if d == nil {
+		return nil
+	}
+
+	results := []*Result{}
+
+	for _, ref := range d.Components() {
+		vendor, model := componentVendorModel(d, ref)
+
+		rule := p.match(ref, vendor, model)
+		if rule == nil {
+			continue
+		}
+
+		results = append(results, evaluate(ref, rule, vendor, model))
+	}
+
+	return results
+}
+
+// Apply evaluates the Device as Evaluate does and sets Firmware.Available on the components
+// with an upgrade available.
+func (p *Policy) Apply(d *common.Device) []*Result {
+	results := p.Evaluate(d)
+
+	for _, r := range results {
+		if r.Available == "" {
+			continue
+		}
+
+		if fw := r.ref.Firmware(); fw != nil {
+			fw.Available = r.Available
+		}
+	}
+
+	return results
+}
+
+// match returns the most specific rule matching the component, the first declared wins a tie
+func (p *Policy) match(ref *common.ComponentRef, vendor, model string) *Rule {
+	var matched *Rule
+
+	for _, rule := range p.Rules {
+		if rule == nil {
+			continue
+		}
+
+		// rules of a Policy literal are compiled on first use, an invalid rule is reported by evaluate
+		_ = rule.check()
+
+		if !rule.matches(ref, vendor, model) {
+			continue
+		}
+
+		if matched == nil || rule.specificity() > matched.specificity() {
+			matched = rule
+		}
+	}
+
+	return matched
+}
+
+func (r *Rule) matches(ref *common.ComponentRef, vendor, model string) bool {
+	slug := r.Component
+	if s, exists := pluralSlugs[slug]; exists {
+		slug = s
+	}
+
+	if slug != ref.Slug {
+		drive, ok := ref.Component.(*common.Drive)
+		if !ok || drive.Type != r.Component {
+			return false
+		}
+	}
+
+	if r.Vendor != "" && !strings.EqualFold(r.Vendor, vendor) {
+		return false
+	}
+
+	if r.modelMatch != nil && !r.modelMatch.MatchString(model) && !r.modelMatch.MatchString(common.FormatProductName(model)) {
+		return false
+	}
+
+	return true
+}
+
+// componentVendorModel returns the normalized vendor and model of the component, falling back to
+// the Device vendor and model for components which report none. Drives are made by a vendor other
+// than the Device vendor and have no fallback.
+func componentVendorModel(d *common.Device, ref *common.ComponentRef) (vendor, model string) {
+	vendor, model = ref.Common.Vendor, ref.Common.Model
+
+	if model == "" {
+		model = ref.Common.ProductName
+	}
+
+	if _, drive := ref.Component.(*common.Drive); !drive {
+		if vendor == "" {
+			vendor = d.Vendor
+		}
+
+		if model == "" {
+			model = d.Model
+		}
+	}
+
+	if vendor != "" {
+		vendor = common.FormatVendorName(vendor)
+	}
+
+	return vendor, common.FormatProductName(model)
+}
+
+func evaluate(ref *common.ComponentRef, rule *Rule, vendor, model string) *Result {
+	result := &Result{
+		Slug:     ref.Slug,
+		Identity: ref.Identity,
+		Vendor:   vendor,
+		Model:    model,
+		Rule:     rule,
+		Status:   StatusUnknown,
+		ref:      ref,
+	}
+
+	if fw := ref.Firmware(); fw != nil {
+		result.Installed = fw.Installed
+	}
+
+	if rule.err != nil {
+		result.Message = "invalid rule: " + rule.err.Error()
+
+		return result
+	}
+
+	installed, err := common.ParseFirmwareVersion(result.Installed)
+	if err != nil {
+		result.Message = "installed firmware version unknown"
+
+		return result
+	}
+
+	if ok, msg := rule.satisfied(installed); ok {
+		result.Status = StatusCompliant
+	} else {
+		result.Status = StatusNonCompliant
+		result.Message = msg
+	}
+
+	if rule.Target != "" {
+		if c, err := common.CompareFirmwareVersions(rule.Target, result.Installed); err == nil && c > 0 {
+			result.Available = rule.Target
+		}
+	}
+
+	return result
+}
+
+// satisfied returns true when the installed version meets the rule constraints,
+// otherwise a message describing the unmet constraint.
+func (r *Rule) satisfied(installed *common.FirmwareVersion) (bool, string) {
+	if r.version != nil {
+		c := installed.Compare(r.version)
+
+		var ok bool
+
+		switch r.operator {
+		case "==":
+			ok = c == 0
+		case "!=":
+			ok = c != 0
+		case ">=":
+			ok = c >= 0
+		case ">":
+			ok = c > 0
+		case "<=":
+			ok = c <= 0
+		case "<":
+			ok = c < 0
+		}
+
+		if !ok {
+			return false, fmt.Sprintf("installed version %s does not satisfy %s %s", installed, r.operator, r.version)
+		}
+	}
+
+	if len(r.Allowed) > 0 {
+		for _, allowed := range r.Allowed {
+			if c, err := common.CompareFirmwareVersions(allowed, installed.String()); err == nil && c == 0 {
+				return true, ""
+			}
+		}
+
+		return false, fmt.Sprintf("installed version %s is not in the allowed versions %s", installed, strings.Join(r.Allowed, ", "))
+	}
+
+	return true, ""
+}
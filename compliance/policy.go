@@ -0,0 +1,278 @@
+// Package compliance evaluates the firmware installed on a common.Device against a declared policy.
+//
+// A policy lists rules keyed by the vendor and model, normalized with common.FormatVendorName
+// and common.FormatProductName, and the component slug as listed in common.ComponentTypes.
+// Policies are loaded from YAML or JSON:
+//
+//	rules:
+//	  - vendor: dell
+//	    model: r640
+//	    component: BIOS
+//	    version: ">= 2.17.1"
+//	  - vendor: mellanox
+//	    model: "*connectx*"
+//	    component: NIC
+//	    version: "== 14.32.1010"
+//	  - vendor: micron
+//	    model: 5200MAX
+//	    component: Drive
+//	    allowed: [D1MU020, D1MU404]
+package compliance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/bmc-toolbox/common"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errUnknownComponent = errors.New("unknown component slug")
+	errInvalidVersion   = errors.New("invalid version constraint")
+	errNoConstraint     = errors.New("rule declares no version or allowed versions")
+	errNilRule          = errors.New("nil rule")
+)
+
+// Policy is the list of firmware rules evaluated against a Device
+type Policy struct {
+	Rules []*Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule declares the desired firmware of the components matching its vendor, model and component slug.
+type Rule struct {
+	// Vendor matches the component vendor, or the Device vendor for components which report none
+	// such as the BIOS, drives excepted. Empty matches any vendor.
+	Vendor string `json:"vendor,omitempty" yaml:"vendor,omitempty"`
+
+	// Model matches the component model, or the Device model for components which report none.
+	// The model may hold * wildcards and is matched case insensitively. Empty matches any model.
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+
+	// Component is the component slug as listed in common.ComponentTypes.
+	// Drives are also matched by their drive type slug, for example NVMe-PCIe-SSD.
+	Component string `json:"component" yaml:"component"`
+
+	// Version is the version constraint, an operator - one of ==, !=, >=, >, <=, < followed by the version.
+	// The == operator is assumed when none is given.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Allowed lists the versions accepted
+	Allowed []string `json:"allowed,omitempty" yaml:"allowed,omitempty"`
+
+	// Target is the version recommended for upgrades, it defaults to the version of an == or >= constraint,
+	// or the greatest allowed version.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	operator   string
+	version    *common.FirmwareVersion
+	modelMatch *regexp.Regexp
+
+	// once guards compile, which may run from concurrent evaluations, err holds its error
+	once sync.Once
+	err  error
+}
+
+// Load reads the policies from the given YAML or JSON files and returns them as a single Policy
+func Load(paths ...string) (*Policy, error) {
+	policy := &Policy{}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		policy.Rules = append(policy.Rules, p.Rules...)
+	}
+
+	return policy, nil
+}
+
+// Parse returns the Policy declared in the given YAML or JSON document
+func Parse(data []byte) (*Policy, error) {
+	policy := &Policy{}
+
+	// JSON documents are valid YAML
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, err
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Validate returns an error when a rule of the Policy is invalid. Rules of a Policy declared
+// as a literal are validated on first use by Evaluate, which reports the components matched
+// by an invalid rule with the StatusUnknown status.
+func (p *Policy) Validate() error {
+	for idx, rule := range p.Rules {
+		if rule == nil {
+			return fmt.Errorf("rule %d: %w", idx, errNilRule)
+		}
+
+		if err := rule.check(); err != nil {
+			return fmt.Errorf("rule %d: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// AddRule validates and adds the rule to the Policy
+func (p *Policy) AddRule(r *Rule) error {
+	if r == nil {
+		return errNilRule
+	}
+
+	if err := r.check(); err != nil {
+		return err
+	}
+
+	p.Rules = append(p.Rules, r)
+
+	return nil
+}
+
+var operators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// check compiles the rule on first use and returns the compile error
+func (r *Rule) check() error {
+	r.once.Do(func() {
+		r.err = r.compile()
+	})
+
+	return r.err
+}
+
+// compile normalizes the rule vendor and model and parses its constraints
+func (r *Rule) compile() error {
+	if !isComponentSlug(r.Component) {
+		return fmt.Errorf("%w : %s", errUnknownComponent, r.Component)
+	}
+
+	if r.Vendor != "" {
+		r.Vendor = common.FormatVendorName(r.Vendor)
+	}
+
+	if r.Model != "" {
+		r.Model = common.FormatProductName(r.Model)
+		r.modelMatch = globRegexp(r.Model)
+	}
+
+	if r.Version == "" && len(r.Allowed) == 0 {
+		return errNoConstraint
+	}
+
+	if r.Version != "" {
+		expr := strings.TrimSpace(r.Version)
+
+		r.operator = "=="
+
+		for _, op := range operators {
+			if strings.HasPrefix(expr, op) {
+				r.operator = op
+				expr = strings.TrimSpace(strings.TrimPrefix(expr, op))
+
+				break
+			}
+		}
+
+		v, err := common.ParseFirmwareVersion(expr)
+		if err != nil {
+			return fmt.Errorf("%w : %s: %s", errInvalidVersion, r.Version, err)
+		}
+
+		r.version = v
+	}
+
+	for _, allowed := range r.Allowed {
+		if _, err := common.ParseFirmwareVersion(allowed); err != nil {
+			return fmt.Errorf("%w : %s: %s", errInvalidVersion, allowed, err)
+		}
+	}
+
+	if r.Target == "" {
+		r.Target = r.defaultTarget()
+	}
+
+	return nil
+}
+
+func (r *Rule) defaultTarget() string {
+	if r.version != nil && (r.operator == "==" || r.operator == ">=") {
+		return r.version.String()
+	}
+
+	target := ""
+
+	for _, allowed := range r.Allowed {
+		if target == "" {
+			target = allowed
+			continue
+		}
+
+		if c, _ := common.CompareFirmwareVersions(allowed, target); c > 0 {
+			target = allowed
+		}
+	}
+
+	return target
+}
+
+// specificity returns the number of attributes the rule matches on, the most specific rule applies
+// when several match a component.
+func (r *Rule) specificity() int {
+	n := 0
+
+	if r.Vendor != "" {
+		n++
+	}
+
+	if r.Model != "" {
+		n++
+	}
+
+	return n
+}
+
+// pluralSlugs maps the plural component slugs to the slug of the component
+var pluralSlugs = map[string]string{
+	common.SlugDrives:             common.SlugDrive,
+	common.SlugNICs:               common.SlugNIC,
+	common.SlugNICPorts:           common.SlugNICPort,
+	common.SlugPSUs:               common.SlugPSU,
+	common.SlugStorageControllers: common.SlugStorageController,
+}
+
+func isComponentSlug(slug string) bool {
+	for _, s := range common.ComponentTypes() {
+		if s == slug {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globRegexp returns the case insensitive regexp for the pattern with * wildcards
+func globRegexp(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+
+	return regexp.MustCompile("(?i)^" + strings.Join(parts, ".*") + "$")
+}
@@ -0,0 +1,243 @@
+package compliance
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+)
+
+func testDevice() *common.Device {
+	fw := func(v string) *common.Firmware {
+		f := common.NewFirmwareObj()
+		f.Installed = v
+
+		return f
+	}
+
+	d := common.NewDevice()
+	d.Vendor = common.VendorDell
+	d.Model = "r640"
+	d.BIOS = &common.BIOS{Common: common.Common{Firmware: fw("2.10.2")}}
+	d.BMC = &common.BMC{Common: common.Common{Vendor: common.VendorDell, Model: "14G Monolithic", Firmware: fw("6.10.30.00")}}
+	d.CPLDs = []*common.CPLD{{Common: common.Common{Firmware: fw("1.0.6")}}}
+	d.NICs = []*common.NIC{
+		{ID: "NIC.Slot.3", Common: common.Common{Vendor: "Mellanox Technologies", Model: "MT27710 Family [ConnectX-4 Lx]", Firmware: fw("14.31.2006")}},
+		{ID: "NIC.Integrated.1", Common: common.Common{Vendor: common.VendorIntel, Model: "Ethernet 10G X710", Firmware: fw("0x800010a8")}},
+	}
+	d.Drives = []*common.Drive{
+		{Common: common.Common{Serial: "a", Vendor: common.VendorMicron, Model: "Micron_5200_MTFDDAK480TDN", Firmware: fw("D1MU020")}},
+		{Common: common.Common{Serial: "b", Vendor: common.VendorMicron, Model: "Micron_5200_MTFDDAK480TDN", Firmware: fw("D1MU001")}},
+		{Common: common.Common{Serial: "c", Vendor: common.VendorSamsung, Model: "SAMSUNG MZ1LB960HAJQ-00007"}, Type: common.SlugDriveTypePCIeNVMEeSSD},
+	}
+
+	return &d
+}
+
+func TestLoad(t *testing.T) {
+	p, err := Load("testdata/policy.yaml", "testdata/policy.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Rules) != 6 {
+		t.Fatalf("expected 6 rules, got %d", len(p.Rules))
+	}
+
+	// vendor and model names are normalized
+	if r := p.Rules[0]; r.Vendor != common.VendorDell || r.Model != "r640" || r.Target != "2.17.1" {
+		t.Errorf("unexpected rule: %+v", r)
+	}
+
+	if r := p.Rules[3]; r.Model != "5200MAX" || r.Target != "D1MU404" {
+		t.Errorf("unexpected rule: %+v", r)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testcases := []struct {
+		doc      string
+		expected error
+	}{
+		{`{"rules": [{"component": "Flux-Capacitor", "version": "1.0"}]}`, errUnknownComponent},
+		{`{"rules": [{"component": "BIOS"}]}`, errNoConstraint},
+		{`{"rules": [{"component": "BIOS", "version": ">= "}]}`, errInvalidVersion},
+		{`{"rules": [{"component": "BIOS", "allowed": ["unknown"]}]}`, errInvalidVersion},
+	}
+
+	for _, tc := range testcases {
+		if _, err := Parse([]byte(tc.doc)); !errors.Is(err, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.doc, tc.expected, err)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Load("testdata/policy.yaml", "testdata/policy.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := testDevice()
+
+	results := p.Evaluate(d)
+
+	expected := map[string]struct {
+		status    string
+		available string
+	}{
+		common.SlugBIOS:         {StatusNonCompliant, "2.17.1"},
+		common.SlugBMC:          {StatusCompliant, "6.10.80.00"},
+		common.SlugCPLD + "/0":  {StatusCompliant, ""},
+		common.SlugNIC + "/0":   {StatusNonCompliant, "14.32.1010"},
+		common.SlugDrive + "/0": {StatusCompliant, "D1MU404"},
+		common.SlugDrive + "/1": {StatusNonCompliant, "D1MU404"},
+		common.SlugDrive + "/2": {StatusUnknown, ""},
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+
+	seen := map[string]int{}
+
+	for _, r := range results {
+		key := r.Slug
+		if r.Slug != common.SlugBIOS && r.Slug != common.SlugBMC {
+			key = fmt.Sprintf("%s/%d", r.Slug, seen[r.Slug])
+			seen[r.Slug]++
+		}
+
+		e, exists := expected[key]
+		if !exists {
+			t.Errorf("unexpected result: %+v", r)
+			continue
+		}
+
+		if r.Status != e.status || r.Available != e.available {
+			t.Errorf("%s: expected %s available %q, got %s available %q (%s)", key, e.status, e.available, r.Status, r.Available, r.Message)
+		}
+	}
+
+	if Compliant(results) {
+		t.Error("expected the device not to be compliant")
+	}
+
+	// Evaluate does not modify the device
+	if d.BIOS.Firmware.Available != "" {
+		t.Errorf("expected BIOS available firmware unset, got %s", d.BIOS.Firmware.Available)
+	}
+}
+
+func TestApply(t *testing.T) {
+	p, err := Load("testdata/policy.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := testDevice()
+	p.Apply(d)
+
+	if d.BIOS.Firmware.Available != "2.17.1" || !d.BIOS.Firmware.UpdateAvailable() {
+		t.Errorf("unexpected BIOS firmware: %+v", d.BIOS.Firmware)
+	}
+
+	if d.NICs[1].Firmware.Available != "" {
+		t.Errorf("expected no available firmware for a NIC without a rule, got %s", d.NICs[1].Firmware.Available)
+	}
+}
+
+func TestMostSpecificRule(t *testing.T) {
+	p := &Policy{}
+
+	for _, r := range []*Rule{
+		{Component: common.SlugBIOS, Version: ">= 1.0"},
+		{Vendor: "dell", Model: "r640", Component: common.SlugBIOS, Version: ">= 2.17.1"},
+		{Vendor: "dell", Component: common.SlugBIOS, Version: ">= 2.0"},
+	} {
+		if err := p.AddRule(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := p.Evaluate(testDevice())
+	if len(results) != 1 || results[0].Rule != p.Rules[1] {
+		t.Errorf("expected the vendor and model rule to apply, got %+v", results)
+	}
+}
+
+func TestPolicyLiteral(t *testing.T) {
+	p := &Policy{Rules: []*Rule{
+		{Vendor: "Dell Inc.", Model: "PowerEdge R640", Component: common.SlugBIOS, Version: ">= 2.17.1"},
+		{Vendor: "dell", Component: common.SlugBMC, Version: ">= "},
+	}}
+
+	results := p.Evaluate(testDevice())
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	if results[0].Status != StatusNonCompliant || results[0].Available != "2.17.1" {
+		t.Errorf("expected the BIOS version constraint to apply, got %+v", results[0])
+	}
+
+	if results[1].Status != StatusUnknown || results[1].Message == "" {
+		t.Errorf("expected the invalid BMC rule to be reported, got %+v", results[1])
+	}
+
+	if err := p.Validate(); !errors.Is(err, errInvalidVersion) {
+		t.Errorf("expected errInvalidVersion, got %v", err)
+	}
+}
+
+func TestDriveNoDeviceFallback(t *testing.T) {
+	p := &Policy{}
+	if err := p.AddRule(&Rule{Vendor: "dell", Component: common.SlugDrive, Version: ">= 1.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := testDevice()
+	d.Drives = append(d.Drives, &common.Drive{Common: common.Common{Serial: "d", Firmware: &common.Firmware{Installed: "1.0"}}})
+
+	if results := p.Evaluate(d); len(results) != 0 {
+		t.Errorf("expected drives not to match the device vendor, got %+v", results[0])
+	}
+}
+
+func TestEvaluateNilDevice(t *testing.T) {
+	p := &Policy{Rules: []*Rule{{Component: common.SlugBIOS, Version: ">= 2.17.1"}}}
+
+	if results := p.Evaluate(nil); results != nil {
+		t.Errorf("expected no results for a nil device, got %+v", results)
+	}
+
+	if results := p.Apply(nil); results != nil {
+		t.Errorf("expected no results for a nil device, got %+v", results)
+	}
+}
+
+// TestEvaluateConcurrent evaluates a Policy literal from concurrent goroutines, run with -race
+func TestEvaluateConcurrent(t *testing.T) {
+	p := &Policy{Rules: []*Rule{
+		{Vendor: "Dell Inc.", Model: "PowerEdge R640", Component: common.SlugBIOS, Version: ">= 2.17.1"},
+		{Vendor: "mellanox", Model: "*connectx*", Component: common.SlugNIC, Version: "== 14.32.1010"},
+	}}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if results := p.Evaluate(testDevice()); len(results) != 2 {
+				t.Errorf("expected 2 results, got %d", len(results))
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,153 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ComponentRef points to a component of a Device
+type ComponentRef struct {
+	// Slug identifies the component type
+	Slug string
+	// Identity is the stable identity of the component as used by Diff - serial, slot, ID, bus info or list position.
+	Identity string
+	// Component is the pointer to the component, for example *CPU
+	Component interface{}
+	// Common is the pointer to the attributes embedded in the component
+	Common *Common
+}
+
+// Firmware returns the firmware of the referenced component
+func (r *ComponentRef) Firmware() *Firmware {
+	// Enclosure declares its own Firmware field
+	if e, ok := r.Component.(*Enclosure); ok {
+		return e.Firmware
+	}
+
+	return r.Common.Firmware
+}
+
+// SetFirmware sets the firmware of the referenced component
+func (r *ComponentRef) SetFirmware(f *Firmware) {
+	if e, ok := r.Component.(*Enclosure); ok {
+		e.Firmware = f
+		return
+	}
+
+	r.Common.Firmware = f
+}
+
+// Components returns references to the components of the Device, NICPorts are listed after their NIC
+// and the BMC NICPorts after the BMC.
+//
+// nil components are skipped, components sharing an identity are disambiguated by occurrence as in Diff.
+func (d *Device) Components() []*ComponentRef {
+	refs := []*ComponentRef{}
+	seen := map[string]int{}
+
+	add := func(slug, identity string, component interface{}, c *Common) string {
+		seen[slug+"/"+identity]++
+		if n := seen[slug+"/"+identity]; n > 1 {
+			identity = fmt.Sprintf("%s#%d", identity, n)
+		}
+
+		refs = append(refs, &ComponentRef{Slug: slug, Identity: identity, Component: component, Common: c})
+
+		return identity
+	}
+
+	if d.BIOS != nil {
+		add(SlugBIOS, "", d.BIOS, &d.BIOS.Common)
+	}
+
+	if d.BMC != nil {
+		add(SlugBMC, "", d.BMC, &d.BMC.Common)
+
+		// the BMC NIC ports are identified under the BMC, as by the MAC address rule
+		if d.BMC.NIC != nil {
+			for j, p := range d.BMC.NIC.NICPorts {
+				if p != nil {
+					add(SlugNICPort, SlugBMC+"/"+componentIdentity(p, j), p, &p.Common)
+				}
+			}
+		}
+	}
+
+	if d.Mainboard != nil {
+		add(SlugMainboard, "", d.Mainboard, &d.Mainboard.Common)
+	}
+
+	for i, c := range d.CPLDs {
+		if c != nil {
+			add(SlugCPLD, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	for i, c := range d.TPMs {
+		if c != nil {
+			add(SlugTPM, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	for i, c := range d.GPUs {
+		if c != nil {
+			add(SlugGPU, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	for i, c := range d.CPUs {
+		if c != nil {
+			add(SlugCPU, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	for i, c := range d.Memory {
+		if c != nil {
+			add(SlugPhysicalMem, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	for i, c := range d.NICs {
+		if c == nil {
+			continue
+		}
+
+		nicIdentity := add(SlugNIC, componentIdentity(c, i), c, &c.Common)
+
+		for j, p := range c.NICPorts {
+			if p != nil {
+				add(SlugNICPort, nicIdentity+"/"+componentIdentity(p, j), p, &p.Common)
+			}
+		}
+	}
+
+	for i, c := range d.Drives {
+		if c != nil {
+			add(SlugDrive, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	for i, c := range d.StorageControllers {
+		if c != nil {
+			add(SlugStorageController, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	for i, c := range d.PSUs {
+		if c != nil {
+			add(SlugPSU, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	for i, c := range d.Enclosures {
+		if c != nil {
+			add(SlugEnclosure, componentIdentity(c, i), c, &c.Common)
+		}
+	}
+
+	return refs
+}
+
+func componentIdentity(component interface{}, position int) string {
+	return identityKey(reflect.ValueOf(component), position)
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestComponentsIdentity(t *testing.T) {
+	d := NewDevice()
+	d.Memory = []*Memory{
+		{Slot: "DIMM_A1", Common: Common{Serial: "00000000"}},
+		{Slot: "DIMM_A2", Common: Common{Serial: "00000000"}},
+		{Slot: "DIMM_B1", Common: Common{Serial: "0E5B3A21"}},
+	}
+	d.NICs = []*NIC{
+		{ID: "NIC.Slot.1", NICPorts: []*NICPort{{ID: "port-1"}}},
+		{ID: "NIC.Slot.1", NICPorts: []*NICPort{{ID: "port-1"}}},
+	}
+	d.BMC = &BMC{NIC: &NIC{NICPorts: []*NICPort{{ID: "port-1"}}}}
+
+	identities := []string{}
+
+	for _, ref := range d.Components() {
+		if ref.Identity != "" {
+			identities = append(identities, ref.Slug+" "+ref.Identity)
+		}
+	}
+
+	expected := []string{
+		SlugNICPort + " " + SlugBMC + "/id=port-1",
+		SlugPhysicalMem + " serial=00000000",
+		SlugPhysicalMem + " serial=00000000#2",
+		SlugPhysicalMem + " serial=0E5B3A21",
+		SlugNIC + " id=NIC.Slot.1",
+		SlugNICPort + " id=NIC.Slot.1/id=port-1",
+		SlugNIC + " id=NIC.Slot.1#2",
+		SlugNICPort + " id=NIC.Slot.1#2/id=port-1",
+	}
+
+	if len(identities) != len(expected) {
+		t.Fatalf("expected %d components, got: %v", len(expected), identities)
+	}
+
+	for i := range expected {
+		if identities[i] != expected[i] {
+			t.Errorf("expected component %d identity %q, got: %q", i, expected[i], identities[i])
+		}
+	}
+}
@@ -1,44 +1,56 @@
 package config
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"fmt"
 	"strings"
+
+	"github.com/bmc-toolbox/common"
 )
 
+// asrockrackDefaultMenu is the menu holding settings set with Raw without a menu path
+const asrockrackDefaultMenu = "Advanced"
+
 type asrockrackVendorConfig struct {
 	ConfigFormat string
 	ConfigData   *asrockrackConfig
+
+	// includeSecrets includes the passwords in the Marshal and StandardConfig output
+	includeSecrets bool
+	// errs are the validation errors of the Raw settings, returned by Marshal
+	errs []error
 }
 
 type asrockrackConfig struct {
-	BiosCfg *asrockrackBiosCfg `xml:"BiosCfg"`
+	BiosCfg *asrockrackBiosCfg `xml:"BiosCfg" json:"BiosCfg"`
 }
 
 type asrockrackBiosCfg struct {
-	XMLName xml.Name                 `xml:"BiosCfg"`
-	Menus   []*asrockrackBiosCfgMenu `xml:"Menu"`
+	XMLName xml.Name                 `xml:"BiosCfg" json:"-"`
+	Menus   []*asrockrackBiosCfgMenu `xml:"Menu" json:"Menus,omitempty"`
 }
 
 type asrockrackBiosCfgMenu struct {
-	XMLName  xml.Name                    `xml:"Menu"`
-	Name     string                      `xml:"name,attr"`
-	Settings []*asrockrackBiosCfgSetting `xml:"Setting"`
-	Menus    []*asrockrackBiosCfgMenu    `xml:"Menu"`
+	XMLName  xml.Name                    `xml:"Menu" json:"-"`
+	Name     string                      `xml:"name,attr" json:"Name"`
+	Settings []*asrockrackBiosCfgSetting `xml:"Setting" json:"Settings,omitempty"`
+	Menus    []*asrockrackBiosCfgMenu    `xml:"Menu" json:"Menus,omitempty"`
 }
 
 type asrockrackBiosCfgSetting struct {
-	XMLName        xml.Name `xml:"Setting"`
-	Name           string   `xml:"Name,attr"`
-	Order          string   `xml:"order,attr"`
-	SelectedOption string   `xml:"selectedOption,attr"`
-	Type           string   `xml:"type,attr"`
+	XMLName        xml.Name `xml:"Setting" json:"-"`
+	Name           string   `xml:"Name,attr" json:"Name"`
+	Order          string   `xml:"order,attr,omitempty" json:"Order,omitempty"`
+	SelectedOption string   `xml:"selectedOption,attr" json:"SelectedOption"`
+	Type           string   `xml:"type,attr,omitempty" json:"Type,omitempty"`
 }
 
 func NewAsrockrackVendorConfigManager(configFormat string, vendorOptions map[string]string) (VendorConfigManager, error) {
 	asrr := &asrockrackVendorConfig{}
 
 	switch strings.ToLower(configFormat) {
-	case "json":
+	case "xml", "json":
 		asrr.ConfigFormat = strings.ToLower(configFormat)
 	default:
 		return nil, UnknownConfigFormatError(strings.ToLower(configFormat))
@@ -48,53 +60,133 @@ func NewAsrockrackVendorConfigManager(configFormat string, vendorOptions map[str
 		BiosCfg: &asrockrackBiosCfg{},
 	}
 
+	asrr.includeSecrets = includeSecrets(vendorOptions)
+
 	return asrr, nil
 }
 
-// FindMenu locates an existing asrockrackBiosCfgMenu if one exists in the ConfigData, if not
+// FindMenu locates an existing top level asrockrackBiosCfgMenu if one exists in the ConfigData, if not
 // it creates one and returns a pointer to that.
-func (cm *asrockrackVendorConfig) FindMenu(menuName string) (m *asrockrackBiosCfgMenu) {
-	if cm.ConfigData.BiosCfg.Menus == nil {
-		return
+func (cm *asrockrackVendorConfig) FindMenu(menuName string) *asrockrackBiosCfgMenu {
+	return findOrCreateAsrockrackMenu(&cm.ConfigData.BiosCfg.Menus, menuName)
+}
+
+// FindMenuPath locates the nested asrockrackBiosCfgMenu at the given path of menu names,
+// creating the menus missing along the path.
+func (cm *asrockrackVendorConfig) FindMenuPath(menuPath []string) (m *asrockrackBiosCfgMenu) {
+	menus := &cm.ConfigData.BiosCfg.Menus
+
+	for _, name := range menuPath {
+		m = findOrCreateAsrockrackMenu(menus, name)
+		menus = &m.Menus
 	}
 
-	for _, m = range cm.ConfigData.BiosCfg.Menus {
-		if m.Name == menuName {
-			return
+	return m
+}
+
+func findOrCreateAsrockrackMenu(menus *[]*asrockrackBiosCfgMenu, name string) *asrockrackBiosCfgMenu {
+	for _, m := range *menus {
+		if m.Name == name {
+			return m
 		}
 	}
 
-	m.Name = menuName
+	m := &asrockrackBiosCfgMenu{Name: name}
+	*menus = append(*menus, m)
 
-	cm.ConfigData.BiosCfg.Menus = append(cm.ConfigData.BiosCfg.Menus, m)
-
-	return
+	return m
 }
 
 // FindMenuSetting locates an existing asrockrackBiosCfgSetting if one exists in the
-// ConfigData, if not it creates one and returns a pointer to that.
-func (cm *asrockrackVendorConfig) FindMenuSetting(m *asrockrackBiosCfgMenu, name string) (s *asrockrackBiosCfgSetting) {
-	for _, s = range m.Settings {
+// menu, if not it creates one and returns a pointer to that.
+func (cm *asrockrackVendorConfig) FindMenuSetting(m *asrockrackBiosCfgMenu, name string) *asrockrackBiosCfgSetting {
+	for _, s := range m.Settings {
 		if s.Name == name {
-			return
+			return s
 		}
 	}
 
-	s.Name = name
-
+	s := &asrockrackBiosCfgSetting{Name: name, Type: "Option"}
 	m.Settings = append(m.Settings, s)
 
-	return
+	return s
 }
 
-// TODO(jwb) How do we handle the random nature of sub menus here..   we could make the user pass the explicit pointer to a menu struct, or..
+// findAsrockrackSetting returns the first setting with the given name in the menus or their sub menus
+func findAsrockrackSetting(menus []*asrockrackBiosCfgMenu, name string) *asrockrackBiosCfgSetting {
+	for _, m := range menus {
+		for _, s := range m.Settings {
+			if s.Name == name {
+				return s
+			}
+		}
+
+		if s := findAsrockrackSetting(m.Menus, name); s != nil {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// Raw sets the setting in the menu at the given path, for example
+// []string{"Advanced", "CPU Configuration"}. Without a menu path an existing
+// setting is updated wherever it is, or created in its catalog menu, the Advanced
+// menu for settings missing from the catalog. The values of the settings listed in
+// the catalog are validated, validation errors are returned by Marshal.
 func (cm *asrockrackVendorConfig) Raw(name, value string, menuPath []string) {
+	if err := validateCatalogSetting(common.VendorAsrockrack, name, value); err != nil {
+		cm.errs = append(cm.errs, err)
+		return
+	}
+
+	var s *asrockrackBiosCfgSetting
+
+	if len(menuPath) == 0 {
+		s = findAsrockrackSetting(cm.ConfigData.BiosCfg.Menus, name)
+		if s == nil {
+			path := catalogMenuPath(common.VendorAsrockrack, name)
+			if len(path) == 0 {
+				path = []string{asrockrackDefaultMenu}
+			}
+
+			s = cm.FindMenuSetting(cm.FindMenuPath(path), name)
+		}
+	} else {
+		s = cm.FindMenuSetting(cm.FindMenuPath(menuPath), name)
+	}
+
+	// passwords created as an Option would be marshalled as set
+	if c := catalogSetting(common.VendorAsrockrack, name); c != nil && c.Type == "Password" {
+		s.Type = "Password"
+	}
+
+	s.SelectedOption = value
 }
 
+// Marshal renders the BiosCfg, the set Password settings require the secrets to be included.
 func (cm *asrockrackVendorConfig) Marshal() (string, error) {
+	if len(cm.errs) > 0 {
+		return "", cm.errs[0]
+	}
+
+	menus, err := redactAsrockrackMenus(cm.ConfigData.BiosCfg.Menus, cm.includeSecrets)
+	if err != nil {
+		return "", err
+	}
+
+	cfg := &asrockrackBiosCfg{Menus: menus}
+
 	switch strings.ToLower(cm.ConfigFormat) {
 	case "xml":
-		x, err := xml.Marshal(cm.ConfigData)
+		x, err := xml.Marshal(cfg)
+		if err != nil {
+			return "", err
+		}
+
+		return string(x), nil
+	case "json":
+		x, err := json.Marshal(cfg)
 		if err != nil {
 			return "", err
 		}
@@ -105,55 +197,221 @@ func (cm *asrockrackVendorConfig) Marshal() (string, error) {
 	}
 }
 
+// redactAsrockrackMenus returns a copy of the menus without the redacted Password settings, set Password
+// settings are an error unless included.
+func redactAsrockrackMenus(menus []*asrockrackBiosCfgMenu, include bool) ([]*asrockrackBiosCfgMenu, error) {
+	if menus == nil {
+		return nil, nil
+	}
+
+	redacted := make([]*asrockrackBiosCfgMenu, 0, len(menus))
+
+	for _, m := range menus {
+		rm := *m
+		rm.Settings = nil
+
+		for _, s := range m.Settings {
+			if s.Type == "Password" {
+				v, ok, err := marshalSecret(s.Name, s.SelectedOption, include)
+				if err != nil {
+					return nil, err
+				}
+
+				if !ok {
+					continue
+				}
+
+				rs := *s
+				rs.SelectedOption = v
+				s = &rs
+			}
+
+			rm.Settings = append(rm.Settings, s)
+		}
+
+		sub, err := redactAsrockrackMenus(m.Menus, include)
+		if err != nil {
+			return nil, err
+		}
+
+		rm.Menus = sub
+		redacted = append(redacted, &rm)
+	}
+
+	return redacted, nil
+}
+
 func (cm *asrockrackVendorConfig) Unmarshal(cfgData string) error {
-	return xml.Unmarshal([]byte(cfgData), cm.ConfigData)
+	switch strings.ToLower(cm.ConfigFormat) {
+	case "xml":
+		return xml.Unmarshal([]byte(cfgData), cm.ConfigData.BiosCfg)
+	case "json":
+		return json.Unmarshal([]byte(cfgData), cm.ConfigData.BiosCfg)
+	default:
+		return UnknownConfigFormatError(strings.ToLower(cm.ConfigFormat))
+	}
 }
 
 func (cm *asrockrackVendorConfig) StandardConfig() (biosConfig map[string]string, err error) {
+	biosConfig = make(map[string]string)
+
+	err = normalizeAsrockrackMenus(cm.ConfigData.BiosCfg.Menus, biosConfig, cm.includeSecrets)
+
 	return biosConfig, err
 }
 
+func normalizeAsrockrackMenus(menus []*asrockrackBiosCfgMenu, biosConfig map[string]string, includeSecrets bool) error {
+	for _, menu := range menus {
+		for _, s := range menu.Settings {
+			switch s.Type {
+			case "Password":
+				// unset passwords are dropped
+				if v, ok := redactSecret(s.SelectedOption, includeSecrets); ok && v != "" {
+					biosConfig[normalizeName(common.VendorAsrockrack, s.Name)] = v
+				}
+			case "", "Option", "Numeric", "String":
+				k, v := normalizeCatalogSetting(common.VendorAsrockrack, s.Name, s.SelectedOption)
+				biosConfig[k] = v
+			default:
+				return UnknownSettingType(s.Type)
+			}
+		}
+
+		if err := normalizeAsrockrackMenus(menu.Menus, biosConfig, includeSecrets); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cm *asrockrackVendorConfig) menuPaths() map[string][]string {
+	paths := map[string][]string{}
+
+	var walk func(menus []*asrockrackBiosCfgMenu, parent []string)
+
+	walk = func(menus []*asrockrackBiosCfgMenu, parent []string) {
+		for _, menu := range menus {
+			path := append(append([]string{}, parent...), menu.Name)
+
+			for _, s := range menu.Settings {
+				paths[normalizeName(common.VendorAsrockrack, s.Name)] = path
+			}
+
+			walk(menu.Menus, path)
+		}
+	}
+
+	walk(cm.ConfigData.BiosCfg.Menus, nil)
+
+	return paths
+}
+
+func (cm *asrockrackVendorConfig) ApplyStandardConfig(biosConfig map[string]string) error {
+	return applyStandardConfig(cm, common.VendorAsrockrack, biosConfig)
+}
+
+func (cm *asrockrackVendorConfig) SetPassword(kind, password string) error {
+	return UnsupportedSetting(kind + "_password")
+}
+
 // Generic config options
 
 func (cm *asrockrackVendorConfig) BootOrder(mode string) error {
-	// Unimplemented
+	// There are 8 boot options, the first options are explicitly defined
+	// and the remainder populated as Disabled.
+	var options []string
+
+	switch strings.ToUpper(mode) {
+	case "LEGACY":
+		options = []string{"Hard Disk", "Network"}
+	case "UEFI":
+		options = []string{"UEFI Hard Disk", "UEFI Network"}
+	case "DUAL":
+		options = []string{"UEFI Hard Disk", "Hard Disk", "UEFI Network", "Network"}
+	default:
+		return InvalidBootModeOption(strings.ToUpper(mode))
+	}
+
+	for i := 1; i <= 8; i++ {
+		option := "Disabled"
+		if i <= len(options) {
+			option = options[i-1]
+		}
+
+		cm.Raw("Boot Option #"+fmt.Sprint(i), option, []string{"Boot"})
+	}
+
 	return nil
 }
 
 func (cm *asrockrackVendorConfig) BootMode(mode string) error {
-	// Unimplemented
+	switch strings.ToUpper(mode) {
+	case "LEGACY", "UEFI", "DUAL":
+		cm.Raw("Boot Mode Select", strings.ToUpper(mode), []string{"Boot"})
+	default:
+		return InvalidBootModeOption(strings.ToUpper(mode))
+	}
+
 	return nil
 }
 
 func (cm *asrockrackVendorConfig) IntelSGX(mode string) error {
-	// Unimplemented
+	switch mode {
+	case "Disabled", "Enabled", "Software Controlled":
+		cm.Raw("Software Guard Extensions (SGX)", mode, []string{"Advanced", "CPU Configuration"})
+	default:
+		return InvalidSGXOption(mode)
+	}
+
 	return nil
 }
 
 func (cm *asrockrackVendorConfig) SecureBoot(enable bool) error {
-	// Unimplemented
+	if enable {
+		cm.Raw("Secure Boot", "Enabled", []string{"Security", "Secure Boot"})
+	} else {
+		cm.Raw("Secure Boot", "Disabled", []string{"Security", "Secure Boot"})
+	}
+
 	return nil
 }
 
 func (cm *asrockrackVendorConfig) TPM(enable bool) error {
-	// Unimplemented
+	if enable {
+		// Note, this is actually 'Enable' not 'Enabled' like everything else.
+		cm.Raw("Security Device Support", "Enable", []string{"Advanced", "Trusted Computing"})
+	} else {
+		cm.Raw("Security Device Support", "Disable", []string{"Advanced", "Trusted Computing"})
+	}
+
 	return nil
 }
 
 func (cm *asrockrackVendorConfig) SMT(enable bool) error {
-	// Unimplemented
+	if enable {
+		cm.Raw("Hyper-Threading", "Enabled", []string{"Advanced", "CPU Configuration"})
+	} else {
+		cm.Raw("Hyper-Threading", "Disabled", []string{"Advanced", "CPU Configuration"})
+	}
+
 	return nil
 }
 
 func (cm *asrockrackVendorConfig) SRIOV(enable bool) error {
-	// Unimplemented
+	if enable {
+		cm.Raw("SR-IOV Support", "Enabled", []string{"Advanced", "PCI Subsystem Settings"})
+	} else {
+		cm.Raw("SR-IOV Support", "Disabled", []string{"Advanced", "PCI Subsystem Settings"})
+	}
+
 	return nil
 }
 
 func (cm *asrockrackVendorConfig) EnableTPM() {
-	// Unimplemented
+	_ = cm.TPM(true)
 }
 
 func (cm *asrockrackVendorConfig) EnableSRIOV() {
-	// Unimplemented
+	_ = cm.SRIOV(true)
 }
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestAsrockrackVendorConfig_StandardConfig(t *testing.T) {
+	data, err := os.ReadFile("testdata/asrockrack_e3c246d4i.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := NewAsrockrackVendorConfigManager("xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.Unmarshal(string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cm.StandardConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"raw:System Language":                 "English",
+		"smt":                                 enabledValue,
+		"raw:Intel Virtualization Technology": enabledValue,
+		"intel_sgx":                           "Software Controlled",
+		"raw:PRMRR Size":                      "32MB",
+		"tpm":                                 enabledValue,
+		"raw:SHA-1 PCR Bank":                  enabledValue,
+		"sr_iov":                              disabledValue,
+		"raw:Above 4G Decoding":               enabledValue,
+		"raw:Restore on AC/Power Loss":        "Power On",
+		"secure_boot":                         disabledValue,
+		"raw:Secure Boot Mode":                "Custom",
+		"raw:Bootup NumLock State":            enabledValue,
+		"boot_mode":                           "UEFI",
+		"raw:Boot Option #1":                  "UEFI Hard Disk",
+		"raw:Boot Option #2":                  "UEFI Network",
+		"raw:Setup Prompt Timeout":            "1",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected config: %v, got: %v", expected, got)
+	}
+}
+
+func TestAsrockrackVendorConfig_Raw(t *testing.T) {
+	cm := &asrockrackVendorConfig{ConfigData: &asrockrackConfig{BiosCfg: &asrockrackBiosCfg{}}}
+
+	cm.Raw("Hyper-Threading", "Disabled", []string{"Advanced", "CPU Configuration"})
+	cm.Raw("Hyper-Threading", "Enabled", nil)
+	cm.Raw("Above 4G Decoding", "Enabled", nil)
+
+	if len(cm.ConfigData.BiosCfg.Menus) != 1 {
+		t.Fatalf("Expected 1 menu, got: %d", len(cm.ConfigData.BiosCfg.Menus))
+	}
+
+	advanced := cm.ConfigData.BiosCfg.Menus[0]
+	if advanced.Name != "Advanced" || len(advanced.Menus) != 1 || len(advanced.Settings) != 1 {
+		t.Fatalf("Unexpected menu: %+v", advanced)
+	}
+
+	// the setting was updated in the nested menu, not created again
+	if s := advanced.Menus[0].Settings; len(s) != 1 || s[0].SelectedOption != enabledValue {
+		t.Errorf("Unexpected settings: %+v", s)
+	}
+}
+
+func TestAsrockrackVendorConfig_GenericOptions(t *testing.T) {
+	for _, format := range []string{"xml", "json"} {
+		cm, err := NewVendorConfigManager(format, "ASRockRack", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, err := range []error{
+			cm.BootMode("uefi"),
+			cm.BootOrder("UEFI"),
+			cm.IntelSGX("Disabled"),
+			cm.SecureBoot(true),
+			cm.TPM(false),
+			cm.SMT(false),
+			cm.SRIOV(true),
+		} {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		data, err := cm.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		imported, _ := NewAsrockrackVendorConfigManager(format, nil)
+		if err := imported.Unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := imported.StandardConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := map[string]string{
+			"boot_mode":   "UEFI",
+			"intel_sgx":   disabledValue,
+			"secure_boot": enabledValue,
+			"tpm":         disabledValue,
+			"smt":         disabledValue,
+			"sr_iov":      enabledValue,
+		}
+
+		for k, v := range expected {
+			if got[k] != v {
+				t.Errorf("%s: Expected %s = %s, got: %s", format, k, v, got[k])
+			}
+		}
+
+		if got["raw:Boot Option #1"] != "UEFI Hard Disk" || got["raw:Boot Option #8"] != disabledValue {
+			t.Errorf("%s: Unexpected boot order: %v", format, got)
+		}
+	}
+
+	cm, _ := NewAsrockrackVendorConfigManager("xml", nil)
+
+	if err := cm.BootMode("bogus"); !errors.Is(err, errInvalidBootModeOption) {
+		t.Errorf("Expected errInvalidBootModeOption, got: %v", err)
+	}
+
+	if err := cm.BootOrder("bogus"); !errors.Is(err, errInvalidBootModeOption) {
+		t.Errorf("Expected errInvalidBootModeOption, got: %v", err)
+	}
+
+	if err := cm.IntelSGX("On"); !errors.Is(err, errInvalidSGXOption) {
+		t.Errorf("Expected errInvalidSGXOption, got: %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+package config
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// CatalogSetting is a catalog entry describing a vendor BIOS setting normalized by StandardConfig
+// and validated by Raw.
+type CatalogSetting struct {
+	// Key is the normalized setting name, for example smt
+	Key string `json:"key"`
+	// Names are the vendor-native setting names across the firmware generations
+	Names []string `json:"names"`
+	// MenuPath is the menu holding the setting, the FQDD of the component on Dell.
+	// Raw creates the setting in this menu when no menu path is given.
+	MenuPath []string `json:"menu_path,omitempty"`
+	// Type is the setting value type, Enumeration, Integer, Boolean, String or Password
+	Type string `json:"type"`
+	// Values maps the normalized values of an Enumeration onto the vendor-native values,
+	// the native values are the values allowed by Raw.
+	Values map[string][]string `json:"values,omitempty"`
+	// LowerBound and UpperBound are the bounds of an Integer
+	LowerBound *int64 `json:"lower_bound,omitempty"`
+	UpperBound *int64 `json:"upper_bound,omitempty"`
+}
+
+// vendorCatalog is the setting catalog of a vendor
+type vendorCatalog struct {
+	settings []*CatalogSetting
+	byName   map[string]*CatalogSetting
+	byKey    map[string]*CatalogSetting
+}
+
+//go:embed catalog/*.json
+var builtinCatalogs embed.FS
+
+var (
+	builtinCatalogsOnce sync.Once
+	catalogsMu          sync.RWMutex
+	catalogs            = map[string]*vendorCatalog{}
+)
+
+// loadBuiltinCatalogs registers the builtin catalogs, catalog/<vendor>.json, on first use
+func loadBuiltinCatalogs() {
+	builtinCatalogsOnce.Do(func() {
+		files, err := builtinCatalogs.ReadDir("catalog")
+		if err != nil {
+			panic(err)
+		}
+
+		for _, f := range files {
+			data, err := builtinCatalogs.ReadFile(path.Join("catalog", f.Name()))
+			if err != nil {
+				panic(err)
+			}
+
+			list := []CatalogSetting{}
+			if err := json.Unmarshal(data, &list); err != nil {
+				panic(fmt.Errorf("%s: %w", f.Name(), err))
+			}
+
+			vendor := strings.TrimSuffix(f.Name(), path.Ext(f.Name()))
+
+			for idx := range list {
+				if err := registerCatalogSetting(vendor, list[idx]); err != nil {
+					panic(fmt.Errorf("%s: %w", f.Name(), err))
+				}
+			}
+		}
+	})
+}
+
+// LoadCatalog registers the vendor settings listed in the given JSON file, see ParseCatalog
+func LoadCatalog(vendor, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := ParseCatalog(vendor, f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ParseCatalog registers the vendor settings in the given JSON document, a list of CatalogSetting
+// entries in the format of the builtin catalogs.
+func ParseCatalog(vendor string, r io.Reader) error {
+	list := []CatalogSetting{}
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return err
+	}
+
+	for idx := range list {
+		if err := RegisterCatalogSetting(vendor, list[idx]); err != nil {
+			return fmt.Errorf("setting %d: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// RegisterCatalogSetting adds the setting to the catalog of the vendor. An error is returned if the
+// key or one of the names of the setting is already registered for the vendor.
+func RegisterCatalogSetting(vendor string, s CatalogSetting) error {
+	loadBuiltinCatalogs()
+
+	return registerCatalogSetting(vendor, s)
+}
+
+func registerCatalogSetting(vendor string, s CatalogSetting) error {
+	vendor = common.FormatVendorName(strings.ToLower(strings.TrimSpace(vendor)))
+	s.Key = strings.TrimSpace(s.Key)
+
+	if s.Key == "" || strings.HasPrefix(s.Key, rawPrefix) || len(s.Names) == 0 {
+		return InvalidCatalogSetting(s.Key, "a key and names are required")
+	}
+
+	switch s.Type {
+	case "Enumeration":
+		if len(s.Values) == 0 {
+			return InvalidCatalogSetting(s.Key, "an Enumeration requires values")
+		}
+	case "Integer", "Boolean", "String", "Password":
+	default:
+		return UnknownSettingType(s.Type)
+	}
+
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	c, exists := catalogs[vendor]
+	if !exists {
+		c = &vendorCatalog{byName: map[string]*CatalogSetting{}, byKey: map[string]*CatalogSetting{}}
+		catalogs[vendor] = c
+	}
+
+	if _, exists := c.byKey[s.Key]; exists {
+		return CatalogSettingExists(vendor, s.Key)
+	}
+
+	for _, name := range s.Names {
+		if _, exists := c.byName[name]; exists {
+			return CatalogSettingExists(vendor, name)
+		}
+	}
+
+	entry := &s
+	c.settings = append(c.settings, entry)
+	c.byKey[s.Key] = entry
+
+	for _, name := range s.Names {
+		c.byName[name] = entry
+	}
+
+	return nil
+}
+
+// CatalogSettings returns the catalog settings of the vendor sorted by key
+func CatalogSettings(vendor string) []CatalogSetting {
+	loadBuiltinCatalogs()
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	c, exists := catalogs[common.FormatVendorName(strings.ToLower(vendor))]
+	if !exists {
+		return nil
+	}
+
+	list := make([]CatalogSetting, 0, len(c.settings))
+	for _, s := range c.settings {
+		list = append(list, *s)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+
+	return list
+}
+
+// catalogSetting returns the catalog setting of the vendor with the given native name, nil if it is not listed
+func catalogSetting(vendor, name string) *CatalogSetting {
+	loadBuiltinCatalogs()
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	if c, exists := catalogs[vendor]; exists {
+		return c.byName[name]
+	}
+
+	return nil
+}
+
+// catalogSettingByKey returns the catalog setting of the vendor with the given normalized key, nil if it is not listed
+func catalogSettingByKey(vendor, key string) *CatalogSetting {
+	loadBuiltinCatalogs()
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	if c, exists := catalogs[vendor]; exists {
+		return c.byKey[key]
+	}
+
+	return nil
+}
+
+// catalogKeyExists returns true when the normalized key is listed in any of the vendor catalogs
+func catalogKeyExists(key string) bool {
+	loadBuiltinCatalogs()
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for _, c := range catalogs {
+		if _, exists := c.byKey[key]; exists {
+			return true
+		}
+	}
+
+	return false
+}
+
+// catalogPassword returns true when the normalized key, or the native name of a raw: key, is
+// a Password setting in any of the vendor catalogs.
+func catalogPassword(key string) bool {
+	loadBuiltinCatalogs()
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for _, c := range catalogs {
+		s, exists := c.byKey[key]
+		if !exists {
+			s, exists = c.byName[strings.TrimPrefix(key, rawPrefix)]
+		}
+
+		if exists && s.Type == "Password" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeCatalogSetting returns the normalized key and value of the vendor setting, settings missing
+// from the vendor catalog are normalized with normalizeName and normalizeValue.
+func normalizeCatalogSetting(vendor, name, value string) (k, v string) {
+	s := catalogSetting(vendor, name)
+	if s == nil {
+		k = normalizeName(vendor, name)
+		return k, normalizeValue(k, value)
+	}
+
+	return s.Key, s.normalizeValue(value)
+}
+
+// validateCatalogSetting returns an error when the value is not allowed by the vendor catalog,
+// settings missing from the catalog are not validated.
+func validateCatalogSetting(vendor, name, value string) error {
+	s := catalogSetting(vendor, name)
+	if s == nil {
+		return nil
+	}
+
+	return s.validate(name, value)
+}
+
+// catalogMenuPath returns the catalog menu path of the vendor setting, nil if it is not listed
+func catalogMenuPath(vendor, name string) []string {
+	if s := catalogSetting(vendor, name); s != nil {
+		return s.MenuPath
+	}
+
+	return nil
+}
+
+// normalizeValue returns the normalized value mapped to the native value, values not
+// listed are normalized with normalizeValue.
+func (s *CatalogSetting) normalizeValue(value string) string {
+	normalized := make([]string, 0, len(s.Values))
+	for n := range s.Values {
+		normalized = append(normalized, n)
+	}
+
+	sort.Strings(normalized)
+
+	// native values are matched exactly, then case insensitively
+	for _, fold := range []bool{false, true} {
+		for _, n := range normalized {
+			for _, v := range s.Values[n] {
+				if v == value || fold && strings.EqualFold(v, value) {
+					return n
+				}
+			}
+		}
+	}
+
+	return normalizeValue(s.Key, value)
+}
+
+// nativeValue returns the first native value mapped to the normalized value, or the value as is
+// when it is a native value. Other than Enumeration values are returned as is, Boolean values
+// normalized to Enabled or Disabled as true or false.
+func (s *CatalogSetting) nativeValue(value string) (string, error) {
+	switch s.Type {
+	case "Enumeration":
+		if values := s.Values[normalizeValue(s.Key, value)]; len(values) > 0 {
+			return values[0], nil
+		}
+
+		if err := s.validate(s.Key, value); err != nil {
+			return "", err
+		}
+	case "Boolean":
+		switch normalizeValue(s.Key, value) {
+		case enabledValue:
+			return "true", nil
+		case disabledValue:
+			return "false", nil
+		}
+	}
+
+	return value, nil
+}
+
+func (s *CatalogSetting) validate(name, value string) error {
+	switch s.Type {
+	case "Enumeration":
+		for _, values := range s.Values {
+			for _, v := range values {
+				if v == value {
+					return nil
+				}
+			}
+		}
+
+		return InvalidAttributeValue(name, value)
+	case "Integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || (s.LowerBound != nil && n < *s.LowerBound) || (s.UpperBound != nil && n > *s.UpperBound) {
+			return InvalidAttributeValue(name, value)
+		}
+	case "Boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return InvalidAttributeValue(name, value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCatalogSettings(t *testing.T) {
+	for _, vendor := range []string{"dell", "supermicro", "asrockrack", "hpe"} {
+		keys := []string{}
+		for _, s := range CatalogSettings(vendor) {
+			keys = append(keys, s.Key)
+		}
+
+		for _, want := range []string{"boot_mode", "smt", "tpm", "sr_iov", "admin_password"} {
+			found := false
+			for _, k := range keys {
+				found = found || k == want
+			}
+
+			if !found {
+				t.Errorf("%s: expected %s in the catalog keys %v", vendor, want, keys)
+			}
+		}
+	}
+
+	if s := CatalogSettings("unknown"); s != nil {
+		t.Errorf("expected no catalog, got: %v", s)
+	}
+}
+
+func TestCatalogNormalization(t *testing.T) {
+	testcases := []struct {
+		board    string
+		expected map[string]string
+	}{
+		{"x11dph-t", map[string]string{"smt": "Enabled", "tpm": "Enabled", "secure_boot": "Disabled", "boot_mode": "DUAL"}},
+		{"x12spo-ntf", map[string]string{"smt": "Enabled", "tpm": "Enabled", "intel_sgx": "Enabled", "sr_iov": "Enabled", "boot_mode": "UEFI"}},
+		{"h12ssl-i", map[string]string{"smt": "Auto", "amd_sev": "1", "sr_iov": "Disabled", "boot_mode": "BIOS"}},
+	}
+
+	for _, tc := range testcases {
+		data, err := os.ReadFile(filepath.Join("testdata", "supermicro", tc.board+".xml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cm, _ := NewSupermicroVendorConfigManager("xml", nil)
+		if err := cm.Unmarshal(string(data)); err != nil {
+			t.Fatal(err)
+		}
+
+		biosConfig, err := cm.StandardConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := map[string]string{}
+		for k := range tc.expected {
+			got[k] = biosConfig[k]
+		}
+
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %v, got: %v", tc.board, tc.expected, got)
+		}
+	}
+}
+
+func TestCatalogValidation(t *testing.T) {
+	testcases := []struct {
+		vendor   string
+		name     string
+		value    string
+		menuPath []string
+		valid    bool
+	}{
+		{"supermicro", "Hyper-Threading [ALL]", "Enable", nil, true},
+		{"supermicro", "Hyper-Threading [ALL]", "On", nil, false},
+		{"supermicro", "CpuMinSevAsid", "253", nil, true},
+		{"supermicro", "CpuMinSevAsid", "600", nil, false},
+		{"supermicro", "CpuMinSevAsid", "auto", nil, false},
+		// settings missing from the catalog are not validated
+		{"supermicro", "Quiet Boot", "Maybe", nil, true},
+		{"dell", "BootMode", "Uefi", nil, true},
+		{"dell", "BootMode", "Legacy", nil, false},
+		{"dell", "TpmSecurity", "OnPbm", nil, true},
+		// attributes of components other than the BIOS are not validated
+		{"dell", "BootMode", "Legacy", []string{"NIC.Slot.3-1-1"}, true},
+		{"asrockrack", "Security Device Support", "Enable", nil, true},
+		{"asrockrack", "Security Device Support", "Enabled", nil, false},
+	}
+
+	for _, tc := range testcases {
+		cm, err := NewVendorConfigManager("xml", tc.vendor, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cm.Raw(tc.name, tc.value, tc.menuPath)
+
+		_, err = cm.Marshal()
+		if tc.valid && err != nil {
+			t.Errorf("%s %s=%s: expected no error, got: %v", tc.vendor, tc.name, tc.value, err)
+		}
+
+		if !tc.valid && !errors.Is(err, errInvalidAttributeValue) {
+			t.Errorf("%s %s=%s: expected errInvalidAttributeValue, got: %v", tc.vendor, tc.name, tc.value, err)
+		}
+	}
+}
+
+func TestCatalogMenuPath(t *testing.T) {
+	cm, _ := NewAsrockrackVendorConfigManager("xml", nil)
+	cm.Raw("SR-IOV Support", "Enabled", nil)
+
+	x, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<Menu name="Advanced"><Menu name="PCI Subsystem Settings"><Setting Name="SR-IOV Support" selectedOption="Enabled" type="Option"></Setting></Menu></Menu>`
+	if !strings.Contains(x, want) {
+		t.Errorf("expected the setting in its catalog menu, got: %s", x)
+	}
+}
+
+func TestCatalogGenericOptionsMenuPath(t *testing.T) {
+	cm, _ := NewSupermicroVendorConfigManager("xml", nil)
+	sm := cm.(*supermicroVendorConfig)
+
+	if err := cm.IntelSGX("Enabled"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.SecureBoot(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.TPM(true); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"intel_sgx":           {"Advanced", "Processor Configuration"},
+		"secure_boot":         {"Security", "Secure Boot"},
+		"tpm":                 {"Advanced", "Trusted Computing"},
+		"raw: SHA-1 PCR Bank": {"Advanced", "Trusted Computing"},
+	}
+
+	if paths := sm.menuPaths(); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected the settings in their catalog menus %v, got: %v", expected, paths)
+	}
+
+	// a setting present in the config is updated in its menu
+	data, err := os.ReadFile(filepath.Join("testdata", "supermicro", "x11dph-t.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.Unmarshal(string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	before := sm.menuPaths()["smt"]
+
+	if err := cm.SMT(false); err != nil {
+		t.Fatal(err)
+	}
+
+	if paths := sm.menuPaths(); !reflect.DeepEqual(paths["smt"], before) {
+		t.Errorf("expected smt in %v, got: %v", before, paths["smt"])
+	}
+
+	biosConfig, err := cm.StandardConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if biosConfig["smt"] != disabledValue {
+		t.Errorf("expected smt Disabled, got: %s", biosConfig["smt"])
+	}
+}
+
+func TestNormalizeNamePerVendor(t *testing.T) {
+	testcases := []struct {
+		vendor   string
+		name     string
+		expected string
+	}{
+		{"supermicro", "Hyper-Threading", "smt"},
+		{"asrockrack", "Hyper-Threading", "smt"},
+		{"dell", "LogicalProc", "smt"},
+		// the native names of a vendor are not normalized for another vendor
+		{"dell", "Hyper-Threading", "raw:Hyper-Threading"},
+		{"supermicro", "ProcVirtualization", "raw:ProcVirtualization"},
+		{"hpe", "SR-IOV Support", "raw:SR-IOV Support"},
+	}
+
+	for _, tc := range testcases {
+		if k := normalizeName(tc.vendor, tc.name); k != tc.expected {
+			t.Errorf("%s %s: expected %s, got: %s", tc.vendor, tc.name, tc.expected, k)
+		}
+	}
+}
+
+// TestNormalizeNameSupermicroLegacy pins the names normalized before the vendor catalogs
+func TestNormalizeNameSupermicroLegacy(t *testing.T) {
+	for name, expected := range map[string]string{
+		"CpuMinSevAsid":                   "amd_sev",
+		"BootMode":                        "boot_mode",
+		"Boot mode select":                "boot_mode",
+		"IntelTxt":                        "intel_txt",
+		"Software Guard Extensions (SGX)": "intel_sgx",
+		"SecureBoot":                      "secure_boot",
+		"Secure Boot":                     "secure_boot",
+		"Hyper-Threading":                 "smt",
+		"Hyper-Threading [ALL]":           "smt",
+		"LogicalProc":                     "smt",
+		"SriovGlobalEnable":               "sr_iov",
+		"TpmSecurity":                     "tpm",
+		"Security Device Support":         "tpm",
+	} {
+		if k := normalizeName("supermicro", name); k != expected {
+			t.Errorf("%s: expected %s, got: %s", name, expected, k)
+		}
+	}
+}
+
+func TestRegisterCatalogSetting(t *testing.T) {
+	// a new normalized key is a data change
+	catalog := `[
+  {
+    "key": "sgx_prmrr_size",
+    "names": ["PRMRR Size"],
+    "menu_path": ["Advanced", "Processor Configuration"],
+    "type": "Enumeration",
+    "values": {"2G": ["2G"], "64G": ["64G", "64 GB"]}
+  }
+]`
+
+	if err := ParseCatalog("supermicro", strings.NewReader(catalog)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", "supermicro", "x12spo-ntf.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm, _ := NewSupermicroVendorConfigManager("xml", nil)
+	if err := cm.Unmarshal(string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	biosConfig, _ := cm.StandardConfig()
+	if biosConfig["sgx_prmrr_size"] != "64G" {
+		t.Errorf("expected sgx_prmrr_size 64G, got: %v", biosConfig)
+	}
+
+	if _, exists := biosConfig["raw:PRMRR Size"]; exists {
+		t.Error("expected PRMRR Size to be normalized")
+	}
+
+	cm.Raw("PRMRR Size", "8G", nil)
+
+	if _, err := cm.Marshal(); !errors.Is(err, errInvalidAttributeValue) {
+		t.Errorf("expected errInvalidAttributeValue, got: %v", err)
+	}
+
+	if err := RegisterCatalogSetting("supermicro", CatalogSetting{Key: "sgx_prmrr_size", Names: []string{"PRMRR"}, Type: "String"}); !errors.Is(err, errCatalogSettingExists) {
+		t.Errorf("expected errCatalogSettingExists, got: %v", err)
+	}
+
+	if err := RegisterCatalogSetting("supermicro", CatalogSetting{Key: "prmrr", Names: []string{"PRMRR Size"}, Type: "String"}); !errors.Is(err, errCatalogSettingExists) {
+		t.Errorf("expected errCatalogSettingExists, got: %v", err)
+	}
+
+	if err := RegisterCatalogSetting("supermicro", CatalogSetting{Key: "prmrr", Names: []string{"PRMRR"}, Type: "Float"}); !errors.Is(err, errUnknownSettingType) {
+		t.Errorf("expected errUnknownSettingType, got: %v", err)
+	}
+
+	if err := RegisterCatalogSetting("supermicro", CatalogSetting{Key: "prmrr", Type: "String"}); !errors.Is(err, errInvalidCatalogSetting) {
+		t.Errorf("expected errInvalidCatalogSetting, got: %v", err)
+	}
+}
@@ -4,11 +4,21 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"strings"
+
+	"github.com/bmc-toolbox/common"
 )
 
+// dellBiosFQDD is the FQDD of the component holding the BIOS settings
+const dellBiosFQDD = "BIOS.Setup.1-1"
+
 type dellVendorConfig struct {
 	ConfigFormat string
 	ConfigData   *dellConfig
+
+	// includeSecrets includes the password attributes in the Marshal and StandardConfig output
+	includeSecrets bool
+	// errs are the validation errors of the Raw settings, returned by Marshal
+	errs []error
 }
 
 type dellConfig struct {
@@ -53,6 +63,7 @@ func NewDellVendorConfigManager(configFormat string, vendorOptions map[string]st
 	}
 
 	dell.setSystemConfiguration(vendorOptions["model"], vendorOptions["servicetag"])
+	dell.includeSecrets = includeSecrets(vendorOptions)
 
 	return dell, nil
 }
@@ -102,23 +113,65 @@ func (cm *dellVendorConfig) FindComponentAttribute(c *dellComponent, name string
 	return
 }
 
+// Raw sets the attribute of the component with the FQDD given as the menuPath. When none is given
+// the attribute is set in the catalog component, the BIOS.Setup.1-1 component for the attributes
+// not listed. The values of the BIOS attributes listed in the catalog are validated, validation
+// errors are returned by Marshal.
 func (cm *dellVendorConfig) Raw(name, value string, menuPath []string) {
-	c := cm.FindComponent(menuPath[0])
+	if len(menuPath) == 0 {
+		menuPath = catalogMenuPath(common.VendorDell, name)
+	}
+
+	fqdd := dellBiosFQDD
+	if len(menuPath) > 0 {
+		fqdd = menuPath[0]
+	}
+
+	if fqdd == dellBiosFQDD {
+		if err := validateCatalogSetting(common.VendorDell, name, value); err != nil {
+			cm.errs = append(cm.errs, err)
+			return
+		}
+	}
+
+	c := cm.FindComponent(fqdd)
 	attr := cm.FindComponentAttribute(c, name)
 	attr.Value = value
 }
 
+// dellSecretAttribute returns true for the attributes holding passwords, the BIOS passwords
+// and the iDRAC user passwords such as Users.2#Password.
+func dellSecretAttribute(name string) bool {
+	switch name {
+	case "SetupPassword", "SysPassword", "OldSetupPassword", "OldSysPassword":
+		return true
+	default:
+		return strings.HasSuffix(name, "#Password")
+	}
+}
+
+// Marshal renders the SystemConfiguration, set password attributes are an error unless
+// secrets are included.
 func (cm *dellVendorConfig) Marshal() (string, error) {
+	if len(cm.errs) > 0 {
+		return "", cm.errs[0]
+	}
+
+	sc, err := cm.redactedSystemConfiguration()
+	if err != nil {
+		return "", err
+	}
+
 	switch strings.ToLower(cm.ConfigFormat) {
 	case "xml":
-		x, err := xml.Marshal(cm.ConfigData.SystemConfiguration)
+		x, err := xml.Marshal(sc)
 		if err != nil {
 			return "", err
 		}
 
 		return string(x), nil
 	case "json":
-		x, err := json.Marshal(cm.ConfigData.SystemConfiguration)
+		x, err := json.Marshal(sc)
 		if err != nil {
 			return "", err
 		}
@@ -129,53 +182,238 @@ func (cm *dellVendorConfig) Marshal() (string, error) {
 	}
 }
 
+// redactedSystemConfiguration returns a copy of the SystemConfiguration without the set password attributes,
+// unless secrets are included.
+func (cm *dellVendorConfig) redactedSystemConfiguration() (*dellSystemConfiguration, error) {
+	sc := *cm.ConfigData.SystemConfiguration
+	sc.Components = make([]*dellComponent, 0, len(cm.ConfigData.SystemConfiguration.Components))
+
+	for _, c := range cm.ConfigData.SystemConfiguration.Components {
+		rc := *c
+		rc.Attributes = make([]*dellComponentAttribute, 0, len(c.Attributes))
+
+		for _, a := range c.Attributes {
+			if dellSecretAttribute(a.Name) {
+				v, ok, err := marshalSecret(a.Name, a.Value, cm.includeSecrets)
+				if err != nil {
+					return nil, err
+				}
+
+				if !ok {
+					continue
+				}
+
+				ra := *a
+				ra.Value = v
+				a = &ra
+			}
+
+			rc.Attributes = append(rc.Attributes, a)
+		}
+
+		sc.Components = append(sc.Components, &rc)
+	}
+
+	return &sc, nil
+}
+
 func (cm *dellVendorConfig) Unmarshal(cfgData string) error {
-	return xml.Unmarshal([]byte(cfgData), cm.ConfigData)
+	// Marshal renders the SystemConfiguration as the document root
+	switch strings.ToLower(cm.ConfigFormat) {
+	case "json":
+		return json.Unmarshal([]byte(cfgData), cm.ConfigData.SystemConfiguration)
+	default:
+		return xml.Unmarshal([]byte(cfgData), cm.ConfigData.SystemConfiguration)
+	}
 }
 
+// StandardConfig returns the normalized BIOS settings of the BIOS.Setup.1-1 component,
+// the settings of other components are not included. Set passwords are redacted unless
+// secrets are included.
 func (cm *dellVendorConfig) StandardConfig() (biosConfig map[string]string, err error) {
+	biosConfig = make(map[string]string)
+
+	for _, c := range cm.ConfigData.SystemConfiguration.Components {
+		if c.FQDD != dellBiosFQDD {
+			continue
+		}
+
+		for _, a := range c.Attributes {
+			if !dellSecretAttribute(a.Name) {
+				k, v := normalizeCatalogSetting(common.VendorDell, a.Name, a.Value)
+				biosConfig[k] = v
+
+				continue
+			}
+
+			// unset passwords are dropped
+			if v, ok := redactSecret(a.Value, cm.includeSecrets); ok && v != "" {
+				biosConfig[normalizeName(common.VendorDell, a.Name)] = v
+			}
+		}
+	}
+
 	return biosConfig, err
 }
 
+func (cm *dellVendorConfig) menuPaths() map[string][]string {
+	paths := map[string][]string{}
+
+	for _, c := range cm.ConfigData.SystemConfiguration.Components {
+		if c.FQDD != dellBiosFQDD {
+			continue
+		}
+
+		for _, a := range c.Attributes {
+			paths[normalizeName(common.VendorDell, a.Name)] = []string{c.FQDD}
+		}
+	}
+
+	return paths
+}
+
+func (cm *dellVendorConfig) ApplyStandardConfig(biosConfig map[string]string) error {
+	return applyStandardConfig(cm, common.VendorDell, biosConfig)
+}
+
+// SetPassword sets the BIOS setup (admin) or system (user) password, changing a password already
+// set requires the current password as the OldSetupPassword or OldSysPassword attribute.
+func (cm *dellVendorConfig) SetPassword(kind, password string) error {
+	switch kind {
+	case PasswordAdmin:
+		cm.Raw("SetupPassword", password, []string{dellBiosFQDD})
+	case PasswordUser:
+		cm.Raw("SysPassword", password, []string{dellBiosFQDD})
+	default:
+		return InvalidPasswordKind(kind)
+	}
+
+	return nil
+}
+
 // Generic config options
 
+// dellBootDevicePrefixes are the FQDD prefixes of the boot devices ordered first by BootOrder, the disks
+// and then the network devices.
+var dellBootDevicePrefixes = [][]string{
+	{"HardDisk.", "Disk.", "RAID.", "NVMe.", "AHCI."},
+	{"NIC."},
+}
+
+// BootOrder sets the boot devices of the current configuration in the order disks, network devices and
+// then the remaining devices. The devices are read from the UefiBootSeq or BootSeq attribute for the
+// mode, or SetBootOrderEn, of the configuration read with Unmarshal.
 func (cm *dellVendorConfig) BootOrder(mode string) error {
-	// Unimplemented
+	var sequence string
+
+	switch strings.ToUpper(mode) {
+	case "LEGACY":
+		sequence = "BootSeq"
+	case "UEFI":
+		sequence = "UefiBootSeq"
+	default:
+		// Dell does not support a DUAL boot mode
+		return InvalidBootModeOption(strings.ToUpper(mode))
+	}
+
+	devices := cm.attributeList(dellBiosFQDD, sequence)
+	if len(devices) == 0 {
+		devices = cm.attributeList(dellBiosFQDD, "SetBootOrderEn")
+	}
+
+	if len(devices) == 0 {
+		return UnknownBootDevices(strings.ToUpper(mode))
+	}
+
+	ordered := make([]string, 0, len(devices))
+	added := map[string]bool{}
+
+	for _, prefixes := range dellBootDevicePrefixes {
+		for _, d := range devices {
+			for _, prefix := range prefixes {
+				if !added[d] && strings.HasPrefix(d, prefix) {
+					ordered = append(ordered, d)
+					added[d] = true
+				}
+			}
+		}
+	}
+
+	for _, d := range devices {
+		if !added[d] {
+			ordered = append(ordered, d)
+			added[d] = true
+		}
+	}
+
+	cm.Raw("SetBootOrderEn", strings.Join(ordered, ","), []string{dellBiosFQDD})
+
 	return nil
 }
 
+// attributeList returns the comma separated values of the component attribute, nil if it is not set
+func (cm *dellVendorConfig) attributeList(fqdd, name string) []string {
+	var list []string
+
+	for _, c := range cm.ConfigData.SystemConfiguration.Components {
+		if c.FQDD != fqdd {
+			continue
+		}
+
+		for _, a := range c.Attributes {
+			if a.Name != name {
+				continue
+			}
+
+			for _, v := range strings.Split(a.Value, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					list = append(list, v)
+				}
+			}
+		}
+	}
+
+	return list
+}
+
 func (cm *dellVendorConfig) BootMode(mode string) error {
-	// Unimplemented
-	return nil
+	switch strings.ToUpper(mode) {
+	case "LEGACY", "UEFI":
+		return setCatalogSetting(cm, common.VendorDell, "boot_mode", strings.ToUpper(mode))
+	default:
+		// Dell does not support a DUAL boot mode
+		return InvalidBootModeOption(strings.ToUpper(mode))
+	}
 }
 
 func (cm *dellVendorConfig) IntelSGX(mode string) error {
-	// Unimplemented
-	return nil
+	switch mode {
+	case "Disabled", "Enabled":
+		return setCatalogSetting(cm, common.VendorDell, "intel_sgx", mode)
+	default:
+		// SGX cannot be software controlled on Dell
+		return InvalidSGXOption(mode)
+	}
 }
 
 func (cm *dellVendorConfig) SecureBoot(enable bool) error {
-	// Unimplemented
-	return nil
+	return setCatalogEnabled(cm, common.VendorDell, "secure_boot", enable)
 }
 
 func (cm *dellVendorConfig) TPM(enable bool) error {
-	// Unimplemented
-	return nil
+	return setCatalogEnabled(cm, common.VendorDell, "tpm", enable)
 }
 
 func (cm *dellVendorConfig) SMT(enable bool) error {
-	// Unimplemented
-	return nil
+	return setCatalogEnabled(cm, common.VendorDell, "smt", enable)
 }
 
 func (cm *dellVendorConfig) SRIOV(enable bool) error {
-	// Unimplemented
-	return nil
+	return setCatalogEnabled(cm, common.VendorDell, "sr_iov", enable)
 }
 
 func (cm *dellVendorConfig) EnableTPM() {
-	cm.Raw("EnableTPM", "Enabled", []string{"BIOS.Setup.1-1"})
+	cm.Raw("EnableTPM", "Enabled", []string{dellBiosFQDD})
 }
 
 func (cm *dellVendorConfig) EnableSRIOV() {
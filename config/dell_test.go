@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDellVendorConfig_GenericOptions(t *testing.T) {
+	cm, err := NewDellVendorConfigManager("xml", map[string]string{"model": "PowerEdge R640", "servicetag": "ABC1234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the boot devices are those of the current configuration
+	current := `<SystemConfiguration Model="PowerEdge R640" ServiceTag="ABC1234">
+  <Component FQDD="BIOS.Setup.1-1">
+    <Attribute Name="UefiBootSeq">NIC.PxeDevice.1-1,Optical.SATAEmbedded.J-1,Disk.SATAEmbedded.A-1</Attribute>
+  </Component>
+</SystemConfiguration>`
+	if err := cm.Unmarshal(current); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, err := range []error{
+		cm.BootMode("uefi"),
+		cm.BootOrder("UEFI"),
+		cm.IntelSGX("Enabled"),
+		cm.SecureBoot(true),
+		cm.TPM(true),
+		cm.SMT(false),
+		cm.SRIOV(true),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	component := cm.(*dellVendorConfig).FindComponent(dellBiosFQDD)
+
+	got := map[string]string{}
+	for _, a := range component.Attributes {
+		got[a.Name] = a.Value
+	}
+
+	expected := map[string]string{
+		"BootMode":          "Uefi",
+		"UefiBootSeq":       "NIC.PxeDevice.1-1,Optical.SATAEmbedded.J-1,Disk.SATAEmbedded.A-1",
+		"SetBootOrderEn":    "Disk.SATAEmbedded.A-1,NIC.PxeDevice.1-1,Optical.SATAEmbedded.J-1",
+		"IntelSgx":          "On",
+		"SecureBoot":        "Enabled",
+		"TpmSecurity":       "On",
+		"LogicalProc":       "Disabled",
+		"SriovGlobalEnable": "Enabled",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected attributes: %v, got: %v", expected, got)
+	}
+
+	if err := cm.BootMode("DUAL"); !errors.Is(err, errInvalidBootModeOption) {
+		t.Errorf("Expected errInvalidBootModeOption, got: %v", err)
+	}
+
+	if err := cm.BootOrder("bogus"); !errors.Is(err, errInvalidBootModeOption) {
+		t.Errorf("Expected errInvalidBootModeOption, got: %v", err)
+	}
+
+	// without a BootSeq the devices are read from SetBootOrderEn
+	if err := cm.BootOrder("LEGACY"); err != nil {
+		t.Fatal(err)
+	}
+
+	empty, _ := NewDellVendorConfigManager("xml", nil)
+	if err := empty.BootOrder("UEFI"); !errors.Is(err, errUnknownBootDevices) {
+		t.Errorf("Expected errUnknownBootDevices, got: %v", err)
+	}
+
+	if err := cm.IntelSGX("Software Controlled"); !errors.Is(err, errInvalidSGXOption) {
+		t.Errorf("Expected errInvalidSGXOption, got: %v", err)
+	}
+}
+
+func TestDellVendorConfig_StandardConfig(t *testing.T) {
+	for _, format := range []string{"xml", "json"} {
+		cm, err := NewDellVendorConfigManager(format, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = cm.BootMode("LEGACY")
+		_ = cm.SMT(true)
+		_ = cm.TPM(false)
+		_ = cm.SRIOV(false)
+		cm.Raw("ProcVirtualization", "Enabled", []string{dellBiosFQDD})
+		cm.Raw("VirtualizationMode", "SRIOV", []string{"NIC.Slot.3-1-1"})
+
+		data, err := cm.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// read the marshalled configuration back as exported from the iDRAC
+		imported, _ := NewDellVendorConfigManager(format, nil)
+		if err := imported.Unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := imported.StandardConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := map[string]string{
+			"boot_mode":              "BIOS",
+			"smt":                    "Enabled",
+			"tpm":                    "Disabled",
+			"sr_iov":                 "Disabled",
+			"raw:ProcVirtualization": "Enabled",
+		}
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: Expected config: %v, got: %v", format, expected, got)
+		}
+	}
+}
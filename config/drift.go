@@ -0,0 +1,127 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kinds of BIOS setting drift
+const (
+	// DriftMissing is a desired setting absent from the actual config
+	DriftMissing = "missing"
+	// DriftExtra is an actual setting absent from the desired config
+	DriftExtra = "extra"
+	// DriftChanged is a setting with a value differing from the desired value
+	DriftChanged = "changed"
+)
+
+// Drift is the difference of a normalized BIOS setting between the desired and actual config
+type Drift struct {
+	// Key is the normalized setting name as returned by StandardConfig
+	Key  string `json:"key"`
+	Kind string `json:"kind"`
+	// Desired and Actual are the normalized values, RedactedValue for secret settings
+	Desired string `json:"desired,omitempty"`
+	Actual  string `json:"actual,omitempty"`
+	// MenuPath is the vendor-native menu path of the setting, when known. The FQDD on Dell.
+	MenuPath []string `json:"menu_path,omitempty"`
+}
+
+// menuPather is implemented by the VendorConfigManagers which organize their settings in menus
+type menuPather interface {
+	// menuPaths returns the vendor-native menu path of the settings by their normalized key
+	menuPaths() map[string][]string
+}
+
+// Diff returns the drift of the actual config from the desired config, ordered by key.
+//
+// The settings are compared as returned by StandardConfig, the menu paths are looked up
+// in the actual config, or the desired config for missing settings.
+func Diff(desired, actual VendorConfigManager) ([]*Drift, error) {
+	desiredConfig, err := desired.StandardConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	actualConfig, err := actual.StandardConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	drift := DiffStandardConfig(desiredConfig, actualConfig)
+
+	desiredPaths, actualPaths := menuPaths(desired), menuPaths(actual)
+
+	for _, d := range drift {
+		if path, exists := actualPaths[d.Key]; exists {
+			d.MenuPath = path
+		} else {
+			d.MenuPath = desiredPaths[d.Key]
+		}
+	}
+
+	return drift, nil
+}
+
+// DiffStandardConfig returns the drift of the actual normalized config from the desired config, ordered by key.
+//
+// Values are normalized before comparing so Enable, Enabled and On do not drift. The values of
+// secret settings such as the BIOS passwords are compared, and reported as RedactedValue.
+func DiffStandardConfig(desired, actual map[string]string) []*Drift {
+	drift := []*Drift{}
+
+	for k, v := range desired {
+		want := normalizeDriftValue(k, v)
+
+		got, exists := actual[k]
+		if !exists {
+			drift = append(drift, redactDrift(&Drift{Key: k, Kind: DriftMissing, Desired: want}))
+			continue
+		}
+
+		if have := normalizeDriftValue(k, got); have != want {
+			drift = append(drift, redactDrift(&Drift{Key: k, Kind: DriftChanged, Desired: want, Actual: have}))
+		}
+	}
+
+	for k, v := range actual {
+		if _, exists := desired[k]; !exists {
+			drift = append(drift, redactDrift(&Drift{Key: k, Kind: DriftExtra, Actual: normalizeDriftValue(k, v)}))
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Key < drift[j].Key
+	})
+
+	return drift
+}
+
+// redactDrift replaces the set Desired and Actual values of a secret setting with RedactedValue
+func redactDrift(d *Drift) *Drift {
+	if !secretKey(d.Key) {
+		return d
+	}
+
+	if d.Desired != "" {
+		d.Desired = RedactedValue
+	}
+
+	if d.Actual != "" {
+		d.Actual = RedactedValue
+	}
+
+	return d
+}
+
+func normalizeDriftValue(k, v string) string {
+	return normalizeValue(k, strings.TrimSpace(v))
+}
+
+func menuPaths(cm VendorConfigManager) map[string][]string {
+	if p, ok := cm.(menuPather); ok {
+		return p.menuPaths()
+	}
+
+	return map[string][]string{}
+}
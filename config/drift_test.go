@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDiffStandardConfig(t *testing.T) {
+	desired := map[string]string{
+		"boot_mode": "uefi",
+		"smt":       "Enable",
+		"tpm":       "On",
+		"sr_iov":    "Enabled",
+		"intel_sgx": "Disabled",
+	}
+
+	actual := map[string]string{
+		"boot_mode":     "UEFI",
+		"smt":           "Enabled",
+		"tpm":           "Off",
+		"intel_sgx":     " Disabled",
+		"raw:PRMRRSize": "32MB",
+	}
+
+	expected := []*Drift{
+		{Key: "raw:PRMRRSize", Kind: DriftExtra, Actual: "32MB"},
+		{Key: "sr_iov", Kind: DriftMissing, Desired: enabledValue},
+		{Key: "tpm", Kind: DriftChanged, Desired: enabledValue, Actual: disabledValue},
+	}
+
+	if got := DiffStandardConfig(desired, actual); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected drift: %+v, got: %+v", expected, got)
+	}
+}
+
+func TestDiffStandardConfigRedactsSecrets(t *testing.T) {
+	desired := map[string]string{
+		adminPasswordKey:             "s3cret",
+		userPasswordKey:              "user",
+		"raw:Users.2#Password":       "calvin",
+		"raw:Administrator Password": "admin",
+		"raw:ProcVirtualization":     enabledValue,
+	}
+
+	actual := map[string]string{
+		adminPasswordKey:         "other",
+		"raw:OldSetupPassword":   "old",
+		"raw:ProcVirtualization": disabledValue,
+	}
+
+	expected := []*Drift{
+		{Key: adminPasswordKey, Kind: DriftChanged, Desired: RedactedValue, Actual: RedactedValue},
+		{Key: "raw:Administrator Password", Kind: DriftMissing, Desired: RedactedValue},
+		{Key: "raw:OldSetupPassword", Kind: DriftExtra, Actual: RedactedValue},
+		{Key: "raw:ProcVirtualization", Kind: DriftChanged, Desired: enabledValue, Actual: disabledValue},
+		{Key: "raw:Users.2#Password", Kind: DriftMissing, Desired: RedactedValue},
+		{Key: userPasswordKey, Kind: DriftMissing, Desired: RedactedValue},
+	}
+
+	if got := DiffStandardConfig(desired, actual); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected drift: %+v, got: %+v", expected, got)
+	}
+
+	// equal secrets do not drift
+	if got := DiffStandardConfig(map[string]string{adminPasswordKey: "s3cret"}, map[string]string{adminPasswordKey: "s3cret"}); len(got) != 0 {
+		t.Errorf("Expected no drift, got: %+v", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	data, err := os.ReadFile("testdata/asrockrack_e3c246d4i.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, _ := NewAsrockrackVendorConfigManager("xml", nil)
+	if err := actual.Unmarshal(string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the golden profile is the current config with changes applied
+	desired, _ := NewAsrockrackVendorConfigManager("xml", nil)
+	if err := desired.Unmarshal(string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = desired.SMT(false)
+	_ = desired.SRIOV(true)
+
+	drift, err := Diff(desired, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*Drift{
+		{Key: "smt", Kind: DriftChanged, Desired: disabledValue, Actual: enabledValue, MenuPath: []string{"Advanced", "CPU Configuration"}},
+		{Key: "sr_iov", Kind: DriftChanged, Desired: enabledValue, Actual: disabledValue, MenuPath: []string{"Advanced", "PCI Subsystem Settings"}},
+	}
+
+	if !reflect.DeepEqual(drift, expected) {
+		t.Errorf("Expected drift: %+v, got: %+v", expected, drift)
+	}
+
+	// drift across vendors is reported on the normalized keys, with the native menu path of the actual config
+	dell, _ := NewDellVendorConfigManager("xml", nil)
+	_ = dell.SMT(true)
+	_ = dell.BootMode("UEFI")
+
+	drift, err = Diff(dell, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range drift {
+		if d.Kind != DriftExtra {
+			t.Errorf("Unexpected drift: %+v", d)
+		}
+	}
+
+	_ = dell.SMT(false)
+
+	drift, err = Diff(dell, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+
+	for _, d := range drift {
+		if d.Key != "smt" {
+			continue
+		}
+
+		found = true
+
+		if d.Kind != DriftChanged || !reflect.DeepEqual(d.MenuPath, []string{"Advanced", "CPU Configuration"}) {
+			t.Errorf("Unexpected drift: %+v", d)
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected smt drift, got: %+v", drift)
+	}
+}
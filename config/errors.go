@@ -11,6 +11,7 @@ var errUnknownSettingType = errors.New("unknown setting type")
 
 var errInvalidBootModeOption = errors.New("invalid BootMode option <LEGACY|UEFI|DUAL>")
 var errInvalidSGXOption = errors.New("invalid SGX option <Enabled|Disabled|Software Controlled>")
+var errUnknownBootDevices = errors.New("boot devices unknown, the current configuration is required")
 
 func UnknownConfigFormatError(format string) error {
 	return fmt.Errorf("unknown config format %w : %s", errUnknownConfigFormat, format)
@@ -31,3 +32,60 @@ func InvalidBootModeOption(mode string) error {
 func InvalidSGXOption(mode string) error {
 	return fmt.Errorf("%w : %s", errInvalidSGXOption, mode)
 }
+
+func UnknownBootDevices(mode string) error {
+	return fmt.Errorf("%w : %s", errUnknownBootDevices, mode)
+}
+
+var errInvalidAttribute = errors.New("invalid BIOS attribute")
+var errInvalidAttributeValue = errors.New("invalid BIOS attribute value")
+var errUnsupportedSetting = errors.New("setting not supported")
+
+func InvalidAttribute(name, reason string) error {
+	return fmt.Errorf("%w : %s: %s", errInvalidAttribute, name, reason)
+}
+
+func InvalidAttributeValue(name, value string) error {
+	return fmt.Errorf("%w : %s: %s", errInvalidAttributeValue, name, value)
+}
+
+func UnsupportedSetting(setting string) error {
+	return fmt.Errorf("%w : %s", errUnsupportedSetting, setting)
+}
+
+var errInvalidPasswordKind = errors.New("invalid password kind <admin|user>")
+
+func InvalidPasswordKind(kind string) error {
+	return fmt.Errorf("%w : %s", errInvalidPasswordKind, kind)
+}
+
+var errSecretOmitted = errors.New("secret setting omitted, set the include_secrets vendor option to marshal it")
+
+func SecretOmitted(name string) error {
+	return fmt.Errorf("%w : %s", errSecretOmitted, name)
+}
+
+var errInvalidCatalogSetting = errors.New("invalid catalog setting")
+var errCatalogSettingExists = errors.New("a catalog setting is already registered with the name")
+
+func InvalidCatalogSetting(key, reason string) error {
+	return fmt.Errorf("%w : %s: %s", errInvalidCatalogSetting, key, reason)
+}
+
+func CatalogSettingExists(vendor, name string) error {
+	return fmt.Errorf("%w : %s: %s", errCatalogSettingExists, vendor, name)
+}
+
+var errInvalidProfile = errors.New("invalid BIOS profile")
+
+func InvalidProfile(reason string) error {
+	return fmt.Errorf("%w : %s", errInvalidProfile, reason)
+}
+
+var errNilDevice = errors.New("a device is required to resolve a BIOS profile")
+
+var errMissingAttributeRegistry = errors.New("a Redfish attribute registry is required, see RedfishAttributeRegistryOption")
+
+func MissingAttributeRegistry(vendor string) error {
+	return fmt.Errorf("%w : %s", errMissingAttributeRegistry, vendor)
+}
@@ -0,0 +1,299 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// hpeVendorConfig manages the iLO RESTful BIOS settings.
+//
+// The Attributes are PATCHed to /redfish/v1/Systems/1/Bios/Settings, the boot order and
+// secure boot state are not BIOS attributes on iLO and are PATCHed to the Bios/Boot/Settings
+// and SecureBoot resources respectively.
+type hpeVendorConfig struct {
+	ConfigFormat string
+	ConfigData   *hpeConfig
+
+	// includeSecrets includes the passwords in the Marshal and StandardConfig output
+	includeSecrets bool
+
+	// errs are the validation errors of the Raw settings, returned by Marshal
+	errs []error
+}
+
+// hpeBootOrderKey is the StandardConfig key of the boot order, a comma separated list of the
+// DefaultBootOrder devices.
+const hpeBootOrderKey = "boot_order"
+
+type hpeConfig struct {
+	Attributes map[string]interface{} `json:"Attributes"`
+	Boot       *hpeBootSettings       `json:"Boot,omitempty"`
+	SecureBoot *hpeSecureBoot         `json:"SecureBoot,omitempty"`
+}
+
+type hpeBootSettings struct {
+	DefaultBootOrder []string `json:"DefaultBootOrder"`
+}
+
+type hpeSecureBoot struct {
+	SecureBootEnable bool `json:"SecureBootEnable"`
+}
+
+func NewHPEVendorConfigManager(configFormat string, vendorOptions map[string]string) (VendorConfigManager, error) {
+	hpe := &hpeVendorConfig{}
+
+	switch strings.ToLower(configFormat) {
+	case "json":
+		hpe.ConfigFormat = strings.ToLower(configFormat)
+	default:
+		return nil, UnknownConfigFormatError(strings.ToLower(configFormat))
+	}
+
+	hpe.ConfigData = &hpeConfig{
+		Attributes: map[string]interface{}{},
+	}
+
+	hpe.includeSecrets = includeSecrets(vendorOptions)
+
+	return hpe, nil
+}
+
+// Raw sets the BIOS attribute, the iLO BIOS attributes are not organized in menus
+// and the menuPath is ignored.
+func (cm *hpeVendorConfig) Raw(name, value string, menuPath []string) {
+	if err := validateCatalogSetting(common.VendorHPE, name, value); err != nil {
+		cm.errs = append(cm.errs, err)
+		return
+	}
+
+	cm.ConfigData.Attributes[name] = value
+}
+
+// hpeSecretAttribute returns true for the attributes holding passwords
+func hpeSecretAttribute(name string) bool {
+	switch name {
+	case "AdminPassword", "PowerOnPassword", "OldAdminPassword", "OldPowerOnPassword":
+		return true
+	default:
+		return false
+	}
+}
+
+// Marshal renders the BIOS settings, a set password attribute is an error unless secrets are included.
+func (cm *hpeVendorConfig) Marshal() (string, error) {
+	if len(cm.errs) > 0 {
+		return "", cm.errs[0]
+	}
+
+	cfg := *cm.ConfigData
+	cfg.Attributes = make(map[string]interface{}, len(cm.ConfigData.Attributes))
+
+	for name, value := range cm.ConfigData.Attributes {
+		if s, isString := value.(string); isString && hpeSecretAttribute(name) {
+			v, ok, err := marshalSecret(name, s, cm.includeSecrets)
+			if err != nil {
+				return "", err
+			}
+
+			if !ok {
+				continue
+			}
+
+			value = v
+		}
+
+		cfg.Attributes[name] = value
+	}
+
+	switch strings.ToLower(cm.ConfigFormat) {
+	case "json":
+		x, err := json.Marshal(&cfg)
+		if err != nil {
+			return "", err
+		}
+
+		return string(x), nil
+	default:
+		return "", UnknownConfigFormatError(strings.ToLower(cm.ConfigFormat))
+	}
+}
+
+// Unmarshal reads the BIOS settings in the format written by Marshal, or the Bios resource
+// as returned by the iLO.
+func (cm *hpeVendorConfig) Unmarshal(cfgData string) error {
+	if err := json.Unmarshal([]byte(cfgData), cm.ConfigData); err != nil {
+		return err
+	}
+
+	if cm.ConfigData.Attributes == nil {
+		cm.ConfigData.Attributes = map[string]interface{}{}
+	}
+
+	return nil
+}
+
+func (cm *hpeVendorConfig) StandardConfig() (biosConfig map[string]string, err error) {
+	biosConfig = make(map[string]string)
+
+	for name, value := range cm.ConfigData.Attributes {
+		if !hpeSecretAttribute(name) {
+			if s, isString := value.(string); isString {
+				k, v := normalizeCatalogSetting(common.VendorHPE, name, s)
+				biosConfig[k] = v
+
+				continue
+			}
+
+			k := normalizeName(common.VendorHPE, name)
+			biosConfig[k] = normalizeAttributeValue(k, value)
+
+			continue
+		}
+
+		// unset passwords are dropped
+		if s, isString := value.(string); isString {
+			if v, ok := redactSecret(s, cm.includeSecrets); ok && v != "" {
+				biosConfig[normalizeName(common.VendorHPE, name)] = v
+			}
+		}
+	}
+
+	if cm.ConfigData.Boot != nil {
+		biosConfig[hpeBootOrderKey] = strings.Join(cm.ConfigData.Boot.DefaultBootOrder, ",")
+	}
+
+	if cm.ConfigData.SecureBoot != nil {
+		k := normalizeName(common.VendorHPE, "SecureBootEnable")
+		biosConfig[k] = normalizeAttributeValue(k, cm.ConfigData.SecureBoot.SecureBootEnable)
+	}
+
+	return biosConfig, err
+}
+
+// normalizeAttributeValue normalizes the JSON attribute value, Redfish and the iLO report some
+// attributes as booleans or numbers.
+func normalizeAttributeValue(k string, v interface{}) string {
+	switch value := v.(type) {
+	case bool:
+		if value {
+			return enabledValue
+		}
+
+		return disabledValue
+	case string:
+		return normalizeValue(k, value)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func (cm *hpeVendorConfig) ApplyStandardConfig(biosConfig map[string]string) error {
+	return applyStandardConfig(cm, common.VendorHPE, biosConfig)
+}
+
+// setStandard sets the boot order, which is not a BIOS attribute on iLO, the other keys are set
+// as listed in the vendor catalog.
+func (cm *hpeVendorConfig) setStandard(key, value string) error {
+	if key != hpeBootOrderKey {
+		return setCatalogSetting(cm, common.VendorHPE, key, value)
+	}
+
+	cm.ConfigData.Boot = &hpeBootSettings{DefaultBootOrder: []string{}}
+
+	if value != "" {
+		cm.ConfigData.Boot.DefaultBootOrder = strings.Split(value, ",")
+	}
+
+	return nil
+}
+
+func (cm *hpeVendorConfig) SetPassword(kind, password string) error {
+	return UnsupportedSetting(kind + "_password")
+}
+
+// Generic config options
+
+func (cm *hpeVendorConfig) BootOrder(mode string) error {
+	switch strings.ToUpper(mode) {
+	case "LEGACY":
+		cm.ConfigData.Boot = &hpeBootSettings{
+			DefaultBootOrder: []string{"EmbeddedStorage", "PcieSlotStorage", "EmbeddedFlexLOM", "PcieSlotNic", "Cd", "Usb"},
+		}
+	case "UEFI":
+		cm.ConfigData.Boot = &hpeBootSettings{
+			DefaultBootOrder: []string{"EmbeddedStorage", "PcieSlotStorage", "EmbeddedFlexLOM", "PcieSlotNic", "Cd", "Usb", "UefiShell"},
+		}
+	default:
+		// iLO does not support a DUAL boot mode
+		return InvalidBootModeOption(strings.ToUpper(mode))
+	}
+
+	return nil
+}
+
+func (cm *hpeVendorConfig) BootMode(mode string) error {
+	switch strings.ToUpper(mode) {
+	case "LEGACY":
+		cm.Raw("BootMode", "LegacyBios", nil)
+	case "UEFI":
+		cm.Raw("BootMode", "Uefi", nil)
+	default:
+		// iLO does not support a DUAL boot mode
+		return InvalidBootModeOption(strings.ToUpper(mode))
+	}
+
+	return nil
+}
+
+func (cm *hpeVendorConfig) IntelSGX(mode string) error {
+	switch mode {
+	case "Disabled", "Enabled":
+		cm.Raw("IntelSgx", mode, nil)
+	default:
+		// SGX cannot be software controlled on HPE
+		return InvalidSGXOption(mode)
+	}
+
+	return nil
+}
+
+func (cm *hpeVendorConfig) SecureBoot(enable bool) error {
+	cm.ConfigData.SecureBoot = &hpeSecureBoot{SecureBootEnable: enable}
+
+	return nil
+}
+
+func (cm *hpeVendorConfig) TPM(enable bool) error {
+	if enable {
+		cm.Raw("TpmVisibility", "Visible", nil)
+	} else {
+		cm.Raw("TpmVisibility", "Hidden", nil)
+	}
+
+	return nil
+}
+
+func (cm *hpeVendorConfig) SMT(enable bool) error {
+	if enable {
+		cm.Raw("ProcHyperthreading", "Enabled", nil)
+	} else {
+		cm.Raw("ProcHyperthreading", "Disabled", nil)
+	}
+
+	return nil
+}
+
+func (cm *hpeVendorConfig) SRIOV(enable bool) error {
+	if enable {
+		cm.Raw("Sriov", "Enabled", nil)
+	} else {
+		cm.Raw("Sriov", "Disabled", nil)
+	}
+
+	return nil
+}
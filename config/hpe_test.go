@@ -0,0 +1,174 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestHPEVendorConfig_StandardConfig(t *testing.T) {
+	data, err := os.ReadFile("testdata/hpe_dl360_gen10_bios.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := NewVendorConfigManager("json", "HPE", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.Unmarshal(string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cm.StandardConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"boot_mode":               "UEFI",
+		"raw:EmbeddedSata":        "Ahci",
+		"raw:IntelProcVtd":        enabledValue,
+		"smt":                     enabledValue,
+		"raw:ProcVirtualization":  enabledValue,
+		"sr_iov":                  enabledValue,
+		"tpm":                     enabledValue,
+		"raw:WorkloadProfile":     "GeneralPowerEfficientCompute",
+		"raw:PowerOnDelay":        "NoDelay",
+		"raw:ThermalConfig":       "OptimalCooling",
+		"raw:MinProcIdlePkgState": "C6Retention",
+		"raw:ProcX2Apic":          enabledValue,
+		"raw:UefiOptimizedBoot":   enabledValue,
+		"raw:ServerAssetTag":      "",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected config: %v, got: %v", expected, got)
+	}
+}
+
+func TestHPEVendorConfig_GenericOptions(t *testing.T) {
+	cm, err := NewHPEVendorConfigManager("json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, err := range []error{
+		cm.BootMode("LEGACY"),
+		cm.BootOrder("LEGACY"),
+		cm.IntelSGX("Enabled"),
+		cm.SecureBoot(false),
+		cm.TPM(false),
+		cm.SMT(false),
+		cm.SRIOV(false),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported, _ := NewHPEVendorConfigManager("json", nil)
+	if err := imported.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := imported.StandardConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"boot_mode":   "BIOS",
+		"boot_order":  "EmbeddedStorage,PcieSlotStorage,EmbeddedFlexLOM,PcieSlotNic,Cd,Usb",
+		"intel_sgx":   enabledValue,
+		"secure_boot": disabledValue,
+		"tpm":         disabledValue,
+		"smt":         disabledValue,
+		"sr_iov":      disabledValue,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected config: %v, got: %v", expected, got)
+	}
+
+	if err := cm.BootMode("DUAL"); !errors.Is(err, errInvalidBootModeOption) {
+		t.Errorf("Expected errInvalidBootModeOption, got: %v", err)
+	}
+
+	if err := cm.BootOrder("DUAL"); !errors.Is(err, errInvalidBootModeOption) {
+		t.Errorf("Expected errInvalidBootModeOption, got: %v", err)
+	}
+
+	if err := cm.IntelSGX("Software Controlled"); !errors.Is(err, errInvalidSGXOption) {
+		t.Errorf("Expected errInvalidSGXOption, got: %v", err)
+	}
+
+	if _, err := NewHPEVendorConfigManager("xml", nil); !errors.Is(err, errUnknownConfigFormat) {
+		t.Errorf("Expected errUnknownConfigFormat, got: %v", err)
+	}
+}
+
+func TestHPEVendorConfig_ApplyStandardConfig(t *testing.T) {
+	cm, err := NewHPEVendorConfigManager("json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"boot_order": "EmbeddedStorage,PcieSlotNic,UefiShell",
+		"tpm":        disabledValue,
+		"smt":        enabledValue,
+	}
+
+	if err := cm.ApplyStandardConfig(expected); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported, _ := NewHPEVendorConfigManager("json", nil)
+	if err := imported.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+
+	hpe := imported.(*hpeVendorConfig)
+	if _, exists := hpe.ConfigData.Attributes["DefaultBootOrder"]; exists {
+		t.Errorf("Expected the boot order in Boot, got the DefaultBootOrder attribute")
+	}
+
+	if hpe.ConfigData.Attributes["TpmVisibility"] != "Hidden" {
+		t.Errorf("Expected TpmVisibility Hidden, got: %v", hpe.ConfigData.Attributes["TpmVisibility"])
+	}
+
+	got, err := imported.StandardConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected config: %v, got: %v", expected, got)
+	}
+}
+
+func TestHPEVendorConfig_RawValidation(t *testing.T) {
+	cm, err := NewHPEVendorConfigManager("json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm.Raw("TpmVisibility", "Enabled", nil)
+
+	if _, err := cm.Marshal(); !errors.Is(err, errInvalidAttributeValue) {
+		t.Errorf("Expected errInvalidAttributeValue, got: %v", err)
+	}
+}
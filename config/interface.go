@@ -11,6 +11,13 @@ type VendorConfigManager interface {
 	Marshal() (string, error)
 	Unmarshal(cfgData string) (err error)
 	StandardConfig() (biosConfig map[string]string, err error)
+	// ApplyStandardConfig sets the normalized settings, as returned by StandardConfig,
+	// keys prefixed with raw: are passed to Raw.
+	ApplyStandardConfig(biosConfig map[string]string) error
+	// SetPassword sets the BIOS password of the given kind, PasswordAdmin or PasswordUser.
+	// Passwords are redacted in the StandardConfig output unless IncludeSecretsOption is set,
+	// Marshal returns an error rather than omit a set password.
+	SetPassword(kind, password string) error
 
 	BootMode(mode string) error
 	BootOrder(mode string) error
@@ -29,6 +36,15 @@ func NewVendorConfigManager(configFormat, vendorName string, vendorOptions map[s
 		return NewSupermicroVendorConfigManager(configFormat, vendorOptions)
 	case common.VendorAsrockrack:
 		return NewAsrockrackVendorConfigManager(configFormat, vendorOptions)
+	case common.VendorHPE, "hpe":
+		return NewHPEVendorConfigManager(configFormat, vendorOptions)
+	case common.VendorGigabyte, common.VendorQuanta:
+		// the AMI attribute names are opaque, the registry maps them onto the settings
+		if vendorOptions[RedfishAttributeRegistryOption] == "" {
+			return nil, MissingAttributeRegistry(strings.ToLower(vendorName))
+		}
+
+		return NewRedfishVendorConfigManager(configFormat, vendorOptions)
 	default:
 		return nil, UnknownVendorError(strings.ToLower(vendorName))
 	}
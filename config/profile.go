@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a declarative BIOS configuration applied to the devices of any vendor, loaded from YAML or JSON:
+//
+//	settings:
+//	  boot_mode: UEFI
+//	  smt: Enabled
+//	  sr_iov: Enabled
+//	vendors:
+//	  dell:
+//	    settings:
+//	      tpm: Enabled
+//	    raw:
+//	      - name: ProcVirtualization
+//	        value: Enabled
+//	models:
+//	  x11dph-t:
+//	    settings:
+//	      boot_mode: DUAL
+//	    raw:
+//	      - name: Restore on AC Power Loss
+//	        value: Power On
+//	        menu_path: [Advanced, Boot Feature]
+//
+// The settings are normalized settings as returned by StandardConfig, their keys are raw: prefixed,
+// set with a generic config option or listed in a vendor catalog. The vendor overrides replace
+// the base settings and the model overrides replace the vendor overrides. The raw settings are set
+// with Raw after the normalized settings, the vendor raw settings first.
+type Profile struct {
+	Name     string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Settings map[string]string `json:"settings,omitempty" yaml:"settings,omitempty"`
+	// Vendors are the overrides by vendor, keyed by the vendor name normalized with common.FormatVendorName
+	Vendors map[string]*ProfileOverride `json:"vendors,omitempty" yaml:"vendors,omitempty"`
+	// Models are the overrides by model, keyed by the catalog model of the product name, see common.ModelByProductName
+	Models map[string]*ProfileOverride `json:"models,omitempty" yaml:"models,omitempty"`
+}
+
+// ProfileOverride are the settings of a Profile selected by vendor or model
+type ProfileOverride struct {
+	Settings map[string]string `json:"settings,omitempty" yaml:"settings,omitempty"`
+	// Raw are the vendor-native settings, set with Raw
+	Raw []*ProfileRawSetting `json:"raw,omitempty" yaml:"raw,omitempty"`
+}
+
+// ProfileRawSetting is a vendor-native setting of a Profile, see VendorConfigManager.Raw
+type ProfileRawSetting struct {
+	Name     string   `json:"name" yaml:"name"`
+	Value    string   `json:"value" yaml:"value"`
+	MenuPath []string `json:"menu_path,omitempty" yaml:"menu_path,omitempty"`
+}
+
+// profileConfigFormats are the config formats of the managers created by Resolve when none is given
+var profileConfigFormats = map[string]string{
+	common.VendorDell:       "xml",
+	common.VendorSupermicro: "xml",
+	common.VendorAsrockrack: "xml",
+	common.VendorHPE:        "json",
+	common.VendorGigabyte:   "json",
+	common.VendorQuanta:     "json",
+}
+
+// LoadProfile reads the Profile from the given YAML or JSON file
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := ParseProfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// ParseProfile returns the Profile declared in the given YAML or JSON document
+func ParseProfile(data []byte) (*Profile, error) {
+	p := &Profile{}
+
+	// JSON documents are valid YAML
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+
+	if err := p.normalize(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// normalize keys the overrides by the normalized vendor and model names
+func (p *Profile) normalize() error {
+	if err := validateProfileSettings(p.Settings); err != nil {
+		return err
+	}
+
+	vendors := make(map[string]*ProfileOverride, len(p.Vendors))
+
+	for name, o := range p.Vendors {
+		if err := o.validate(); err != nil {
+			return fmt.Errorf("vendor %s: %w", name, err)
+		}
+
+		vendors[profileVendorKey(name)] = o
+	}
+
+	models := make(map[string]*ProfileOverride, len(p.Models))
+
+	for name, o := range p.Models {
+		if err := o.validate(); err != nil {
+			return fmt.Errorf("model %s: %w", name, err)
+		}
+
+		models[profileModelKey(name)] = o
+	}
+
+	p.Vendors, p.Models = vendors, models
+
+	return nil
+}
+
+func (o *ProfileOverride) validate() error {
+	if o == nil {
+		return InvalidProfile("empty override")
+	}
+
+	if err := validateProfileSettings(o.Settings); err != nil {
+		return err
+	}
+
+	for idx, r := range o.Raw {
+		if r == nil || r.Name == "" {
+			return InvalidProfile(fmt.Sprintf("raw setting %d has no name", idx))
+		}
+	}
+
+	return nil
+}
+
+// validateProfileSettings returns an error for the settings keys which are neither raw: prefixed,
+// set with a generic config option, nor listed in a vendor catalog.
+func validateProfileSettings(settings map[string]string) error {
+	for k := range settings {
+		if strings.HasPrefix(k, rawPrefix) || catalogKeyExists(k) {
+			continue
+		}
+
+		known := false
+		for _, key := range standardKeys {
+			known = known || k == key
+		}
+
+		if !known {
+			return InvalidProfile("unknown setting " + k)
+		}
+	}
+
+	return nil
+}
+
+func profileVendorKey(vendor string) string {
+	return common.FormatVendorName(strings.ToLower(strings.TrimSpace(vendor)))
+}
+
+func profileModelKey(model string) string {
+	model = strings.TrimSpace(model)
+
+	// product names are matched case insensitively, unlike common.FormatProductName
+	if m, ok := common.ModelByProductName(model); ok {
+		model = m.Model
+	}
+
+	return strings.ToLower(model)
+}
+
+// SettingsFor returns the normalized settings and the raw settings of the profile for the vendor and model
+func (p *Profile) SettingsFor(vendor, model string) (settings map[string]string, raw []*ProfileRawSetting) {
+	settings = make(map[string]string, len(p.Settings))
+	for k, v := range p.Settings {
+		settings[k] = v
+	}
+
+	for _, o := range []*ProfileOverride{p.Vendors[profileVendorKey(vendor)], p.Models[profileModelKey(model)]} {
+		if o == nil {
+			continue
+		}
+
+		for k, v := range o.Settings {
+			settings[k] = v
+		}
+
+		raw = append(raw, o.Raw...)
+	}
+
+	return settings, raw
+}
+
+// Resolve returns the vendor config manager of the device with the profile settings applied, ready to Marshal.
+//
+// The device model is matched as the catalog model of the product name, or the device product name
+// when no override matches the model. The configFormat defaults to the format read by the vendor tooling,
+// xml for Dell, Supermicro and ASRockRack and json for the Redfish based vendors.
+// The vendorOptions are passed to NewVendorConfigManager, see IncludeSecretsOption, the Redfish based
+// vendors require the RedfishAttributeRegistryOption.
+func (p *Profile) Resolve(d *common.Device, configFormat string, vendorOptions map[string]string) (VendorConfigManager, error) {
+	if d == nil {
+		return nil, errNilDevice
+	}
+
+	vendor := profileVendorKey(d.Vendor)
+
+	model := d.Model
+	if _, exists := p.Models[profileModelKey(model)]; !exists && d.ProductName != "" {
+		model = d.ProductName
+	}
+
+	if configFormat == "" {
+		configFormat = profileConfigFormats[vendor]
+	}
+
+	options := map[string]string{}
+	for k, v := range vendorOptions {
+		options[k] = v
+	}
+
+	// the Dell SystemConfiguration identifies the device
+	if vendor == common.VendorDell {
+		if options["model"] == "" {
+			options["model"] = d.ProductName
+		}
+
+		if options["servicetag"] == "" {
+			options["servicetag"] = d.Serial
+		}
+	}
+
+	cm, err := NewVendorConfigManager(configFormat, vendor, options)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, raw := p.SettingsFor(vendor, model)
+
+	if err := cm.ApplyStandardConfig(settings); err != nil {
+		return nil, err
+	}
+
+	for _, r := range raw {
+		cm.Raw(r.Name, r.Value, r.MenuPath)
+	}
+
+	return cm, nil
+}
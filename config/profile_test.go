@@ -0,0 +1,219 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+)
+
+func TestLoadProfile(t *testing.T) {
+	fromYAML, err := LoadProfile(filepath.Join("testdata", "profile.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromJSON, err := LoadProfile(filepath.Join("testdata", "profile.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("expected the YAML and JSON profiles to be equal, got:\n%+v\n%+v", fromYAML, fromJSON)
+	}
+
+	// the overrides are keyed by the normalized vendor and model
+	if _, exists := fromYAML.Vendors[common.VendorHPE]; !exists {
+		t.Errorf("expected the hpe override keyed as %s", common.VendorHPE)
+	}
+
+	if _, exists := fromYAML.Models["x11dph-t"]; !exists {
+		t.Error("expected the SSG-6029P-E1CR12L override keyed as x11dph-t")
+	}
+}
+
+func TestProfileSettingsFor(t *testing.T) {
+	p, err := LoadProfile(filepath.Join("testdata", "profile.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings, raw := p.SettingsFor("Dell Inc.", "PowerEdge R6515")
+
+	expected := map[string]string{
+		"boot_mode":   "UEFI",
+		"smt":         "Disabled",
+		"secure_boot": "Enabled",
+		"sr_iov":      "Enabled",
+		"tpm":         "Enabled",
+	}
+
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("expected %v, got: %v", expected, settings)
+	}
+
+	if len(raw) != 2 || raw[0].Name != "ProcVirtualization" || raw[1].Name != "NumaNodesPerSocket" {
+		t.Errorf("expected the vendor raw settings followed by the model raw settings, got: %+v", raw)
+	}
+
+	// the base settings are not modified by the overrides
+	if p.Settings["smt"] != "Enabled" {
+		t.Errorf("expected the base smt setting to be Enabled, got: %s", p.Settings["smt"])
+	}
+}
+
+func TestProfileResolve(t *testing.T) {
+	p, err := LoadProfile(filepath.Join("testdata", "profile.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name     string
+		vendor   string
+		model    string
+		product  string
+		expected []string
+	}{
+		{
+			"dell model override",
+			"Dell Inc.", "r6515", "PowerEdge R6515",
+			[]string{
+				`Model="PowerEdge R6515" ServiceTag="ABC1234"`,
+				`<Attribute Name="BootMode">Uefi</Attribute>`,
+				`<Attribute Name="LogicalProc">Disabled</Attribute>`,
+				`<Attribute Name="TpmSecurity">On</Attribute>`,
+				`<Attribute Name="ProcVirtualization">Enabled</Attribute>`,
+				`<Attribute Name="NumaNodesPerSocket">2</Attribute>`,
+			},
+		},
+		{
+			"dell vendor override",
+			"Dell Inc.", "r640", "PowerEdge R640",
+			[]string{
+				`<Attribute Name="LogicalProc">Enabled</Attribute>`,
+				`<Attribute Name="ProcVirtualization">Enabled</Attribute>`,
+			},
+		},
+		{
+			// the model is matched by the product name
+			"supermicro product name override",
+			"Supermicro", "", "SSG-6029P-E1CR12L",
+			[]string{
+				`<Setting name="Boot mode select" selectedOption="DUAL" type="Option">`,
+				`<Menu name="Boot Feature">`,
+				`<Setting name="Restore on AC Power Loss" selectedOption="Power On" type="Option">`,
+				`<Setting name="Quiet Boot" selectedOption="Disabled" type="Option">`,
+			},
+		},
+		{
+			"hpe vendor override",
+			"HPE", "ProLiant DL360 Gen10", "",
+			[]string{
+				`"BootMode":"Uefi"`,
+				`"ProcHyperthreading":"Enabled"`,
+				`"SecureBoot":{"SecureBootEnable":false}`,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		d := common.NewDevice()
+		d.Vendor = tc.vendor
+		d.Model = tc.model
+		d.ProductName = tc.product
+		d.Serial = "ABC1234"
+
+		cm, err := p.Resolve(&d, "", nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		x, err := cm.Marshal()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		for _, want := range tc.expected {
+			if !strings.Contains(x, want) {
+				t.Errorf("%s: expected %s in:\n%s", tc.name, want, x)
+			}
+		}
+	}
+
+	// the models are matched without the vendor override of another vendor
+	d := common.NewDevice()
+	d.Vendor = "Supermicro"
+	d.Model = "x11dph-t"
+
+	cm, err := p.Resolve(&d, "xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if x, _ := cm.Marshal(); strings.Contains(x, "ProcVirtualization") {
+		t.Errorf("expected the dell raw settings to be ignored, got:\n%s", x)
+	}
+}
+
+func TestProfileResolveErrors(t *testing.T) {
+	// amd_sev is not listed in the Dell catalog
+	p, err := ParseProfile([]byte("settings:\n  amd_sev: \"100\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := common.NewDevice()
+	d.Vendor = "Dell Inc."
+
+	if _, err := p.Resolve(&d, "", nil); !errors.Is(err, errUnsupportedSetting) {
+		t.Errorf("expected errUnsupportedSetting, got: %v", err)
+	}
+
+	d.Vendor = "Lenovo"
+
+	if _, err := p.Resolve(&d, "json", nil); !errors.Is(err, errUnknownVendor) {
+		t.Errorf("expected errUnknownVendor, got: %v", err)
+	}
+
+	if _, err := ParseProfile([]byte("vendors:\n  dell:\n    raw:\n      - value: Enabled\n")); !errors.Is(err, errInvalidProfile) {
+		t.Errorf("expected errInvalidProfile, got: %v", err)
+	}
+
+	if _, err := ParseProfile([]byte("models:\n  r640:\n")); !errors.Is(err, errInvalidProfile) {
+		t.Errorf("expected errInvalidProfile, got: %v", err)
+	}
+
+	if _, err := p.Resolve(nil, "", nil); !errors.Is(err, errNilDevice) {
+		t.Errorf("expected errNilDevice, got: %v", err)
+	}
+}
+
+func TestParseProfileSettingsKeys(t *testing.T) {
+	testcases := []struct {
+		profile string
+		valid   bool
+	}{
+		{"settings:\n  smt: Enabled\n", true},
+		{"settings:\n  raw:LogicalProc: Enabled\n", true},
+		// listed in the Dell catalog
+		{"settings:\n  intel_txt: Enabled\n", true},
+		{"settings:\n  hyperthreading: Enabled\n", false},
+		{"vendors:\n  dell:\n    settings:\n      LogicalProc: Enabled\n", false},
+		{"models:\n  r640:\n    settings:\n      sriov: Enabled\n", false},
+	}
+
+	for _, tc := range testcases {
+		_, err := ParseProfile([]byte(tc.profile))
+		if tc.valid && err != nil {
+			t.Errorf("%q: %v", tc.profile, err)
+		}
+
+		if !tc.valid && !errors.Is(err, errInvalidProfile) {
+			t.Errorf("%q: expected errInvalidProfile, got: %v", tc.profile, err)
+		}
+	}
+}
@@ -0,0 +1,492 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// RedfishAttributeRegistryOption is the vendorOptions key holding the Redfish AttributeRegistry JSON document
+// used to validate the BIOS attributes.
+const RedfishAttributeRegistryOption = "attribute_registry"
+
+// redfishCatalogVendor is the vendor of the catalog, catalog/ami.json, normalizing the attribute names
+// and the registry display names of the AMI boards exposing their BIOS settings over Redfish.
+const redfishCatalogVendor = common.VendorAmericanMegatrends
+
+// redfishVendorConfig manages the BIOS settings of boards exposing them as Redfish Bios.Attributes.
+//
+// Unmarshal reads the current attributes from the Bios resource, Raw and the generic options
+// stage changes which Marshal renders as the body of the PATCH to the Bios/Settings resource.
+type redfishVendorConfig struct {
+	ConfigFormat string
+	ConfigData   *redfishBiosConfig
+
+	registry *redfishAttributeRegistry
+	// errs are the validation errors of the Raw settings, returned by Marshal
+	errs []error
+	// includeSecrets includes the Password attributes in the Marshal and StandardConfig output
+	includeSecrets bool
+}
+
+type redfishBiosConfig struct {
+	// Attributes are the current attributes read from the Bios resource
+	Attributes map[string]interface{} `json:"Attributes"`
+	// Pending are the attributes to PATCH
+	Pending map[string]interface{} `json:"-"`
+}
+
+type redfishAttributeRegistry struct {
+	RegistryEntries struct {
+		Attributes []*redfishAttribute `json:"Attributes"`
+	} `json:"RegistryEntries"`
+}
+
+type redfishAttribute struct {
+	AttributeName string                   `json:"AttributeName"`
+	DisplayName   string                   `json:"DisplayName"`
+	Type          string                   `json:"Type"`
+	MenuPath      string                   `json:"MenuPath"`
+	ReadOnly      bool                     `json:"ReadOnly"`
+	LowerBound    *int64                   `json:"LowerBound,omitempty"`
+	UpperBound    *int64                   `json:"UpperBound,omitempty"`
+	Value         []*redfishAttributeValue `json:"Value,omitempty"`
+}
+
+type redfishAttributeValue struct {
+	ValueName        string `json:"ValueName"`
+	ValueDisplayName string `json:"ValueDisplayName"`
+}
+
+// NewRedfishVendorConfigManager returns the config manager of the Redfish Bios.Attributes, the attribute
+// registry is set from the RedfishAttributeRegistryOption. Without a registry the attributes set with Raw
+// are not validated and the generic options return an UnsupportedSetting error, as the attribute names
+// are opaque on AMI boards. NewVendorConfigManager requires the registry.
+func NewRedfishVendorConfigManager(configFormat string, vendorOptions map[string]string) (VendorConfigManager, error) {
+	rf := &redfishVendorConfig{}
+
+	switch strings.ToLower(configFormat) {
+	case "json":
+		rf.ConfigFormat = strings.ToLower(configFormat)
+	default:
+		return nil, UnknownConfigFormatError(strings.ToLower(configFormat))
+	}
+
+	rf.ConfigData = &redfishBiosConfig{
+		Attributes: map[string]interface{}{},
+		Pending:    map[string]interface{}{},
+	}
+
+	rf.includeSecrets = includeSecrets(vendorOptions)
+
+	if registry := vendorOptions[RedfishAttributeRegistryOption]; registry != "" {
+		if err := rf.SetAttributeRegistry(registry); err != nil {
+			return nil, err
+		}
+	}
+
+	return rf, nil
+}
+
+// SetAttributeRegistry sets the AttributeRegistry JSON document used to validate the attributes,
+// without a registry the attributes set with Raw are not validated.
+func (cm *redfishVendorConfig) SetAttributeRegistry(registry string) error {
+	r := &redfishAttributeRegistry{}
+	if err := json.Unmarshal([]byte(registry), r); err != nil {
+		return err
+	}
+
+	cm.registry = r
+
+	return nil
+}
+
+// attribute returns the registry entry for the attribute
+func (cm *redfishVendorConfig) attribute(name string) *redfishAttribute {
+	if cm.registry == nil {
+		return nil
+	}
+
+	for _, a := range cm.registry.RegistryEntries.Attributes {
+		if a.AttributeName == name {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// Raw stages the attribute value. With an attribute registry set the attribute must be listed,
+// writable and in the menu at the given path when one is given, and the value must be
+// one of the allowed values for enumerations or within bounds for integers.
+// Validation errors are returned by Marshal.
+func (cm *redfishVendorConfig) Raw(name, value string, menuPath []string) {
+	v, err := cm.validate(name, value, menuPath)
+	if err != nil {
+		cm.errs = append(cm.errs, err)
+		return
+	}
+
+	cm.ConfigData.Pending[name] = v
+}
+
+func (cm *redfishVendorConfig) validate(name, value string, menuPath []string) (interface{}, error) {
+	if cm.registry == nil {
+		return value, nil
+	}
+
+	a := cm.attribute(name)
+	if a == nil {
+		return nil, InvalidAttribute(name, "not listed in the attribute registry")
+	}
+
+	if a.ReadOnly {
+		return nil, InvalidAttribute(name, "read only")
+	}
+
+	if len(menuPath) > 0 && strings.Trim(a.MenuPath, "./") != strings.Join(menuPath, "/") {
+		return nil, InvalidAttribute(name, "not in menu "+strings.Join(menuPath, "/"))
+	}
+
+	switch a.Type {
+	case "Enumeration":
+		for _, allowed := range a.Value {
+			if allowed.ValueName == value {
+				return value, nil
+			}
+		}
+
+		return nil, InvalidAttributeValue(name, value)
+	case "Integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || (a.LowerBound != nil && n < *a.LowerBound) || (a.UpperBound != nil && n > *a.UpperBound) {
+			return nil, InvalidAttributeValue(name, value)
+		}
+
+		return n, nil
+	case "Boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, InvalidAttributeValue(name, value)
+		}
+
+		return b, nil
+	case "String", "Password":
+		return value, nil
+	default:
+		return nil, UnknownSettingType(a.Type)
+	}
+}
+
+// secret returns true for the attributes of the registry Password type
+func (cm *redfishVendorConfig) secret(name string) bool {
+	a := cm.attribute(name)
+
+	return a != nil && a.Type == "Password"
+}
+
+// Marshal renders the staged attributes as the Bios/Settings PATCH body, staged Password
+// attributes require the secrets to be included.
+func (cm *redfishVendorConfig) Marshal() (string, error) {
+	if len(cm.errs) > 0 {
+		return "", cm.errs[0]
+	}
+
+	pending := make(map[string]interface{}, len(cm.ConfigData.Pending))
+
+	for name, value := range cm.ConfigData.Pending {
+		if s, isString := value.(string); isString && cm.secret(name) {
+			v, ok, err := marshalSecret(name, s, cm.includeSecrets)
+			if err != nil {
+				return "", err
+			}
+
+			if !ok {
+				continue
+			}
+
+			value = v
+		}
+
+		pending[name] = value
+	}
+
+	switch strings.ToLower(cm.ConfigFormat) {
+	case "json":
+		x, err := json.Marshal(map[string]interface{}{"Attributes": pending})
+		if err != nil {
+			return "", err
+		}
+
+		return string(x), nil
+	default:
+		return "", UnknownConfigFormatError(strings.ToLower(cm.ConfigFormat))
+	}
+}
+
+// Unmarshal reads the current attributes from the Bios resource, or the staged attributes from a
+// Bios/Settings resource or a PATCH body as rendered by Marshal. Attributes staged before are discarded.
+func (cm *redfishVendorConfig) Unmarshal(cfgData string) error {
+	doc := struct {
+		ODataID    string                 `json:"@odata.id"`
+		ODataType  string                 `json:"@odata.type"`
+		Attributes map[string]interface{} `json:"Attributes"`
+	}{}
+
+	if err := json.Unmarshal([]byte(cfgData), &doc); err != nil {
+		return err
+	}
+
+	if doc.Attributes == nil {
+		doc.Attributes = map[string]interface{}{}
+	}
+
+	cm.errs = nil
+	cm.ConfigData.Pending = map[string]interface{}{}
+
+	// a PATCH body is not a resource and has no @odata.id
+	if (doc.ODataID == "" && doc.ODataType == "") || strings.HasSuffix(strings.TrimSuffix(doc.ODataID, "/"), "/Settings") {
+		cm.ConfigData.Pending = doc.Attributes
+		return nil
+	}
+
+	cm.ConfigData.Attributes = doc.Attributes
+
+	return nil
+}
+
+// StandardConfig returns the normalized current attributes with the staged attributes applied.
+// Attributes are normalized by their name, or their registry display name.
+func (cm *redfishVendorConfig) StandardConfig() (biosConfig map[string]string, err error) {
+	biosConfig = make(map[string]string)
+
+	attributes := make(map[string]interface{}, len(cm.ConfigData.Attributes)+len(cm.ConfigData.Pending))
+	for name, value := range cm.ConfigData.Attributes {
+		attributes[name] = value
+	}
+
+	for name, value := range cm.ConfigData.Pending {
+		attributes[name] = value
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+
+	// attributes normalized to the same key are resolved in name order
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := attributes[name]
+
+		k := cm.standardName(name)
+
+		if cm.secret(name) {
+			// unset passwords are dropped
+			if s, isString := value.(string); isString {
+				if v, ok := redactSecret(s, cm.includeSecrets); ok && v != "" {
+					biosConfig[k] = v
+				}
+			}
+
+			continue
+		}
+
+		a := cm.attribute(name)
+
+		// enumeration values may be opaque, their display name is normalized
+		if s, ok := value.(string); ok && a != nil {
+			for _, allowed := range a.Value {
+				if allowed.ValueName == s && allowed.ValueDisplayName != "" {
+					value = allowed.ValueDisplayName
+				}
+			}
+		}
+
+		biosConfig[k] = normalizeAttributeValue(k, value)
+	}
+
+	return biosConfig, err
+}
+
+// standardName returns the normalized attribute name, AMI boards expose opaque attribute
+// names and are normalized on the registry display name.
+func (cm *redfishVendorConfig) standardName(name string) string {
+	k := normalizeName(redfishCatalogVendor, name)
+	if !strings.HasPrefix(k, rawPrefix) {
+		return k
+	}
+
+	if a := cm.attribute(name); a != nil && a.DisplayName != "" {
+		if dk := normalizeName(redfishCatalogVendor, strings.TrimSpace(a.DisplayName)); !strings.HasPrefix(dk, rawPrefix) {
+			return dk
+		}
+	}
+
+	return k
+}
+
+// set stages the registry attribute normalized to the given key, with the allowed value normalized
+// to the given value.
+func (cm *redfishVendorConfig) set(key, value string) error {
+	if cm.registry == nil {
+		return UnsupportedSetting(key)
+	}
+
+	for _, a := range cm.registry.RegistryEntries.Attributes {
+		if a.ReadOnly || cm.standardName(a.AttributeName) != key {
+			continue
+		}
+
+		switch a.Type {
+		case "Boolean":
+			cm.ConfigData.Pending[a.AttributeName] = value == enabledValue
+			return nil
+		case "Enumeration":
+			for _, allowed := range a.Value {
+				if normalizeValue(key, allowed.ValueName) == value || normalizeValue(key, allowed.ValueDisplayName) == value {
+					cm.ConfigData.Pending[a.AttributeName] = allowed.ValueName
+					return nil
+				}
+			}
+
+			return InvalidAttributeValue(a.AttributeName, value)
+		}
+	}
+
+	return UnsupportedSetting(key)
+}
+
+func (cm *redfishVendorConfig) setEnabled(key string, enable bool) error {
+	if enable {
+		return cm.set(key, enabledValue)
+	}
+
+	return cm.set(key, disabledValue)
+}
+
+func (cm *redfishVendorConfig) menuPaths() map[string][]string {
+	paths := map[string][]string{}
+
+	if cm.registry == nil {
+		return paths
+	}
+
+	for _, a := range cm.registry.RegistryEntries.Attributes {
+		if path := strings.Trim(a.MenuPath, "./"); path != "" {
+			paths[cm.standardName(a.AttributeName)] = strings.Split(path, "/")
+		}
+	}
+
+	return paths
+}
+
+func (cm *redfishVendorConfig) ApplyStandardConfig(biosConfig map[string]string) error {
+	return applyStandardConfig(cm, "", biosConfig)
+}
+
+// setStandard stages the registry attribute normalized to the key, the attribute names are opaque and
+// the vendor catalogs do not apply.
+func (cm *redfishVendorConfig) setStandard(key, value string) error {
+	return cm.set(key, normalizeValue(key, value))
+}
+
+func (cm *redfishVendorConfig) SetPassword(kind, password string) error {
+	return UnsupportedSetting(kind + "_password")
+}
+
+// Generic config options
+
+func (cm *redfishVendorConfig) BootOrder(mode string) error {
+	var devices []string
+
+	switch strings.ToUpper(mode) {
+	case "LEGACY":
+		devices = []string{"Hard Disk", "Network"}
+	case "UEFI":
+		devices = []string{"UEFI Hard Disk", "UEFI Network"}
+	default:
+		return InvalidBootModeOption(strings.ToUpper(mode))
+	}
+
+	if cm.registry == nil {
+		return UnsupportedSetting("boot_order")
+	}
+
+	// the boot options are listed as "Boot Option #1", "Boot Option #2".. in the registry
+	options := map[string]*redfishAttribute{}
+
+	for _, a := range cm.registry.RegistryEntries.Attributes {
+		if !a.ReadOnly && a.Type == "Enumeration" && strings.HasPrefix(a.DisplayName, "Boot Option #") {
+			options[a.DisplayName] = a
+		}
+	}
+
+	for i, device := range devices {
+		a, exists := options["Boot Option #"+fmt.Sprint(i+1)]
+		if !exists {
+			return UnsupportedSetting("boot_order")
+		}
+
+		value := ""
+
+		for _, allowed := range a.Value {
+			display := allowed.ValueDisplayName
+			if display == "" {
+				display = allowed.ValueName
+			}
+
+			if strings.HasPrefix(display, device) {
+				value = allowed.ValueName
+				break
+			}
+		}
+
+		if value == "" {
+			return InvalidAttributeValue(a.AttributeName, device)
+		}
+
+		cm.ConfigData.Pending[a.AttributeName] = value
+	}
+
+	return nil
+}
+
+func (cm *redfishVendorConfig) BootMode(mode string) error {
+	switch strings.ToUpper(mode) {
+	case "LEGACY", "UEFI", "DUAL":
+		return cm.set("boot_mode", normalizeBootMode(mode))
+	default:
+		return InvalidBootModeOption(strings.ToUpper(mode))
+	}
+}
+
+func (cm *redfishVendorConfig) IntelSGX(mode string) error {
+	switch mode {
+	case "Disabled", "Enabled", "Software Controlled":
+		return cm.set("intel_sgx", mode)
+	default:
+		return InvalidSGXOption(mode)
+	}
+}
+
+func (cm *redfishVendorConfig) SecureBoot(enable bool) error {
+	return cm.setEnabled("secure_boot", enable)
+}
+
+func (cm *redfishVendorConfig) TPM(enable bool) error {
+	return cm.setEnabled("tpm", enable)
+}
+
+func (cm *redfishVendorConfig) SMT(enable bool) error {
+	return cm.setEnabled("smt", enable)
+}
+
+func (cm *redfishVendorConfig) SRIOV(enable bool) error {
+	return cm.setEnabled("sr_iov", enable)
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func newTestRedfishVendorConfig(t *testing.T) VendorConfigManager {
+	t.Helper()
+
+	registry, err := os.ReadFile("testdata/gigabyte_mz72_registry.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := NewVendorConfigManager("json", "Gigabyte", map[string]string{RedfishAttributeRegistryOption: string(registry)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bios, err := os.ReadFile("testdata/gigabyte_mz72_bios.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.Unmarshal(string(bios)); err != nil {
+		t.Fatal(err)
+	}
+
+	return cm
+}
+
+func TestRedfishVendorConfig_StandardConfig(t *testing.T) {
+	got, err := newTestRedfishVendorConfig(t).StandardConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"smt":                "Auto",
+		"sr_iov":             enabledValue,
+		"tpm":                enabledValue,
+		"boot_mode":          "UEFI",
+		"raw:SETUP007":       "UEFI Hard Disk",
+		"raw:SETUP008":       "UEFI Network",
+		"raw:SETUP002":       "1",
+		"secure_boot":        disabledValue,
+		"raw:SecureBootMode": "Standard",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected config: %v, got: %v", expected, got)
+	}
+}
+
+func TestRedfishVendorConfig_GenericOptions(t *testing.T) {
+	cm := newTestRedfishVendorConfig(t)
+
+	for _, err := range []error{
+		cm.BootMode("LEGACY"),
+		cm.BootOrder("LEGACY"),
+		cm.SecureBoot(true),
+		cm.TPM(false),
+		cm.SMT(true),
+		cm.SRIOV(false),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cm.Raw("SETUP002", "5", []string{"Boot"})
+
+	got, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"Attributes":{"CbsCmnCpuSmtCtrl":"CbsCmnCpuSmtCtrl_1","PCIS007":"PCIS007_0","SETUP002":5,` +
+		`"SETUP006":"SETUP006_0","SETUP007":"SETUP007_0","SETUP008":"SETUP008_2","SecureBootEnable":true,"TCG001":"TCG001_0"}}`
+
+	if got != expected {
+		t.Errorf("Expected PATCH body: %s, got: %s", expected, got)
+	}
+
+	if err := cm.IntelSGX("Enabled"); !errors.Is(err, errUnsupportedSetting) {
+		t.Errorf("Expected errUnsupportedSetting, got: %v", err)
+	}
+
+	if err := cm.BootOrder("DUAL"); !errors.Is(err, errInvalidBootModeOption) {
+		t.Errorf("Expected errInvalidBootModeOption, got: %v", err)
+	}
+}
+
+func TestRedfishVendorConfig_RawValidation(t *testing.T) {
+	testcases := []struct {
+		name, value string
+		menuPath    []string
+		err         error
+	}{
+		{"UNKNOWN", "1", nil, errInvalidAttribute},
+		{"SecureBootMode", "Custom", nil, errInvalidAttribute},
+		{"PCIS007", "PCIS007_1", []string{"Advanced", "CPU Configuration"}, errInvalidAttribute},
+		{"PCIS007", "Enabled", []string{"Advanced", "PCI Subsystem Settings"}, errInvalidAttributeValue},
+		{"SETUP002", "70000", nil, errInvalidAttributeValue},
+		{"SecureBootEnable", "maybe", nil, errInvalidAttributeValue},
+	}
+
+	for _, tc := range testcases {
+		cm := newTestRedfishVendorConfig(t)
+		cm.Raw(tc.name, tc.value, tc.menuPath)
+
+		if _, err := cm.Marshal(); !errors.Is(err, tc.err) {
+			t.Errorf("%s = %s: Expected %v, got: %v", tc.name, tc.value, tc.err, err)
+		}
+	}
+
+	// without a registry the attributes are not validated
+	cm, _ := NewRedfishVendorConfigManager("json", nil)
+	cm.Raw("UNKNOWN", "1", nil)
+
+	if got, err := cm.Marshal(); err != nil || got != `{"Attributes":{"UNKNOWN":"1"}}` {
+		t.Errorf("Unexpected PATCH body: %s, %v", got, err)
+	}
+
+	if err := cm.SMT(true); !errors.Is(err, errUnsupportedSetting) {
+		t.Errorf("Expected errUnsupportedSetting, got: %v", err)
+	}
+}
+
+func TestRedfishVendorConfig_UnmarshalPatchBody(t *testing.T) {
+	cm := newTestRedfishVendorConfig(t)
+
+	if err := cm.SMT(false); err != nil {
+		t.Fatal(err)
+	}
+
+	cm.Raw("SETUP002", "5", nil)
+
+	body, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the PATCH body is read back as the staged attributes, the validation errors are reset
+	staged := newTestRedfishVendorConfig(t)
+	staged.Raw("UNKNOWN", "1", nil)
+
+	if err := staged.Unmarshal(body); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := staged.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != body {
+		t.Errorf("Expected PATCH body: %s, got: %s", body, got)
+	}
+
+	// the current attributes read from the Bios resource discard the staged attributes
+	bios, err := os.ReadFile("testdata/gigabyte_mz72_bios.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := staged.Unmarshal(string(bios)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := staged.Marshal(); err != nil || got != `{"Attributes":{}}` {
+		t.Errorf("Unexpected PATCH body: %s, %v", got, err)
+	}
+}
+
+func TestNewVendorConfigManager_AttributeRegistryRequired(t *testing.T) {
+	for _, vendor := range []string{"gigabyte", "quanta"} {
+		if _, err := NewVendorConfigManager("json", vendor, nil); !errors.Is(err, errMissingAttributeRegistry) {
+			t.Errorf("%s: Expected errMissingAttributeRegistry, got: %v", vendor, err)
+		}
+	}
+}
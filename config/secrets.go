@@ -0,0 +1,90 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Kinds of the BIOS passwords set with SetPassword
+const (
+	PasswordAdmin = "admin"
+	PasswordUser  = "user"
+)
+
+// IncludeSecretsOption is the vendorOptions key which, set to true, includes the values of secret
+// settings such as the BIOS passwords in the Marshal and StandardConfig output.
+const IncludeSecretsOption = "include_secrets"
+
+// RedactedValue replaces the value of secret settings in the StandardConfig output unless secrets
+// are included. Marshal fails on a set secret unless secrets are included, a redacted value is omitted.
+const RedactedValue = "REDACTED"
+
+// The normalized keys of the BIOS passwords, applied with SetPassword
+const (
+	adminPasswordKey = "admin_password"
+	userPasswordKey  = "user_password"
+)
+
+// includeSecrets returns true when the vendorOptions request the secrets to be included
+func includeSecrets(vendorOptions map[string]string) bool {
+	include, _ := strconv.ParseBool(vendorOptions[IncludeSecretsOption])
+
+	return include
+}
+
+// redactSecret returns the value of a secret setting to output in the StandardConfig, set secrets
+// are redacted unless included. A redacted value read back from an earlier output is not a secret
+// to set, ok is false when it is to be omitted from the output including secrets.
+func redactSecret(value string, include bool) (v string, ok bool) {
+	switch {
+	case value == RedactedValue:
+		return value, !include
+	case value == "" || include:
+		return value, true
+	default:
+		return RedactedValue, true
+	}
+}
+
+// marshalSecret returns the value of the named secret setting to Marshal, ok is false when the setting
+// is to be omitted as are the redacted values read back from a StandardConfig. A set secret is not
+// omitted silently, an error is returned unless secrets are included.
+func marshalSecret(name, value string, include bool) (v string, ok bool, err error) {
+	switch {
+	case value == RedactedValue:
+		return "", false, nil
+	case value == "" || include:
+		return value, true, nil
+	default:
+		return "", false, SecretOmitted(name)
+	}
+}
+
+// secretKey returns true for the normalized keys of secret settings, the BIOS passwords and the
+// raw: keys of the vendor password settings.
+func secretKey(k string) bool {
+	switch k {
+	case adminPasswordKey, userPasswordKey:
+		return true
+	}
+
+	if name := strings.TrimPrefix(k, rawPrefix); name != k {
+		if dellSecretAttribute(name) || hpeSecretAttribute(name) || supermicroSecretSetting(&supermicroBiosCfgSetting{Name: name}) {
+			return true
+		}
+	}
+
+	return catalogPassword(k)
+}
+
+// passwordKind returns the kind of the password with the normalized key
+func passwordKind(k string) string {
+	switch k {
+	case adminPasswordKey:
+		return PasswordAdmin
+	case userPasswordKey:
+		return PasswordUser
+	default:
+		return ""
+	}
+}
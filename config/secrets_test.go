@@ -0,0 +1,307 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetPassword(t *testing.T) {
+	testcases := []struct {
+		vendor string
+		format string
+		// the marshalled admin password
+		admin string
+		// omitted is marshalled only when secrets are included
+		omitted string
+	}{
+		{"dell", "xml", `<Attribute Name="SetupPassword">%s</Attribute>`, `Name="SetupPassword"`},
+		{"dell", "json", `"Name":"SetupPassword","Value":"%s"`, `"Name":"SetupPassword"`},
+		{"supermicro", "xml", `<NewPassword><![CDATA[%s]]></NewPassword><ConfirmNewPassword><![CDATA[%s]]></ConfirmNewPassword>`, `<NewPassword>`},
+	}
+
+	for _, tc := range testcases {
+		for _, include := range []bool{false, true} {
+			options := map[string]string{}
+			if include {
+				options[IncludeSecretsOption] = "true"
+			}
+
+			cm, err := NewVendorConfigManager(tc.format, tc.vendor, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cm.SetPassword(PasswordAdmin, "s3cret"); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cm.SetPassword(PasswordUser, "user"); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cm.SetPassword("root", "x"); !errors.Is(err, errInvalidPasswordKind) {
+				t.Errorf("%s: expected an invalid password kind error, got: %v", tc.vendor, err)
+			}
+
+			x, err := cm.Marshal()
+
+			switch {
+			case !include && !errors.Is(err, errSecretOmitted):
+				t.Errorf("%s %s: expected errSecretOmitted, got: %v", tc.vendor, tc.format, err)
+			case !include && strings.Contains(x, tc.omitted):
+				t.Errorf("%s %s: expected no %s in:\n%s", tc.vendor, tc.format, tc.omitted, x)
+			case include && err != nil:
+				t.Fatal(err)
+			case include && !strings.Contains(x, strings.ReplaceAll(tc.admin, "%s", "s3cret")):
+				t.Errorf("%s %s: expected %s in:\n%s", tc.vendor, tc.format, strings.ReplaceAll(tc.admin, "%s", "s3cret"), x)
+			}
+
+			biosConfig, err := cm.StandardConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := RedactedValue
+			if include {
+				want = "user"
+			}
+
+			if biosConfig[userPasswordKey] != want {
+				t.Errorf("%s %s include %v: expected user_password %s, got: %s", tc.vendor, tc.format, include, want, biosConfig[userPasswordKey])
+			}
+		}
+	}
+}
+
+func TestSetPassword_Unsupported(t *testing.T) {
+	registry, err := os.ReadFile("testdata/gigabyte_mz72_registry.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vendor := range []string{"asrockrack", "hpe", "gigabyte"} {
+		format := "json"
+
+		cm, err := NewVendorConfigManager(format, vendor, map[string]string{RedfishAttributeRegistryOption: string(registry)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := cm.SetPassword(PasswordAdmin, "secret"); !errors.Is(err, errUnsupportedSetting) {
+			t.Errorf("%s: expected an unsupported setting error, got: %v", vendor, err)
+		}
+	}
+}
+
+func TestRedactedSecretsReadBack(t *testing.T) {
+	redacting, _ := NewSupermicroVendorConfigManager("xml", nil)
+	_ = redacting.SetPassword(PasswordAdmin, "secret")
+
+	stored, err := redacting.StandardConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the redacted config read back is applied without the password
+	cm, _ := NewSupermicroVendorConfigManager("xml", map[string]string{IncludeSecretsOption: "true"})
+	if err := cm.ApplyStandardConfig(stored); err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(x, "NewPassword>") {
+		t.Errorf("expected the redacted password to be omitted, got:\n%s", x)
+	}
+
+	// setting the password replaces the redacted one
+	_ = cm.SetPassword(PasswordAdmin, "secret")
+
+	x, err = cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(x, "<NewPassword><![CDATA[secret]]></NewPassword>") {
+		t.Errorf("expected the password to be set, got:\n%s", x)
+	}
+
+	// as does applying the standard config, where a redacted password is ignored
+	dell, _ := NewDellVendorConfigManager("xml", map[string]string{IncludeSecretsOption: "true"})
+
+	if err := dell.ApplyStandardConfig(map[string]string{
+		adminPasswordKey:       RedactedValue,
+		userPasswordKey:        "user",
+		"raw:OldSetupPassword": RedactedValue,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	biosConfig, _ := dell.StandardConfig()
+	if len(biosConfig) != 1 || biosConfig[userPasswordKey] != "user" {
+		t.Errorf("expected only the user password to be set, got: %v", biosConfig)
+	}
+}
+
+func TestSupermicroPasswordRoundTrip(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "supermicro", "x11dph-t.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm, _ := NewSupermicroVendorConfigManager("xml", map[string]string{IncludeSecretsOption: "true"})
+	if err := cm.Unmarshal(string(golden)); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = cm.SetPassword(PasswordAdmin, "secret")
+
+	x, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `    <Setting name="NewSetupPassword" type="Password">
+      <NewPassword><![CDATA[secret]]></NewPassword>
+      <ConfirmNewPassword><![CDATA[secret]]></ConfirmNewPassword>
+      <Information>`
+	if !strings.Contains(x, want) {
+		t.Errorf("expected the password elements before the setting Information, got:\n%s", x)
+	}
+
+	// passwords ending a CDATA section are split
+	_ = cm.SetPassword(PasswordUser, "pass]]>word")
+
+	x, err = cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the password is read back and marshalled as read
+	read, _ := NewSupermicroVendorConfigManager("xml", map[string]string{IncludeSecretsOption: "true"})
+	if err := read.Unmarshal(x); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := read.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again != x {
+		t.Errorf("expected the config with the password to round trip, got:\n%s", again)
+	}
+
+	biosConfig, _ := read.StandardConfig()
+	if biosConfig[adminPasswordKey] != "secret" {
+		t.Errorf("expected admin_password secret, got: %s", biosConfig[adminPasswordKey])
+	}
+
+	if biosConfig[userPasswordKey] != "pass]]>word" {
+		t.Errorf("expected user_password pass]]>word, got: %s", biosConfig[userPasswordKey])
+	}
+}
+
+func TestRedactStandardConfig(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "hpe_dl360_gen10_bios.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm, _ := NewHPEVendorConfigManager("json", nil)
+	if err := cm.Unmarshal(string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	cm.Raw("AdminPassword", "secret", nil)
+
+	biosConfig, _ := cm.StandardConfig()
+	if biosConfig[adminPasswordKey] != RedactedValue {
+		t.Errorf("expected the admin password to be redacted, got: %s", biosConfig[adminPasswordKey])
+	}
+
+	if _, err := cm.Marshal(); !errors.Is(err, errSecretOmitted) {
+		t.Errorf("expected errSecretOmitted, got: %v", err)
+	}
+
+	data, err = os.ReadFile(filepath.Join("testdata", "asrockrack_e3c246d4i.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asrr, _ := NewAsrockrackVendorConfigManager("xml", nil)
+	if err := asrr.Unmarshal(string(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	asrr.Raw("Administrator Password", "secret", nil)
+
+	if _, err := asrr.Marshal(); !errors.Is(err, errSecretOmitted) {
+		t.Errorf("expected errSecretOmitted, got: %v", err)
+	}
+}
+
+func TestMarshalOmitsSecrets(t *testing.T) {
+	registry, err := os.ReadFile(filepath.Join("testdata", "gigabyte_mz72_registry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		vendor string
+		format string
+		// the password setting set with Raw
+		name string
+	}{
+		{"dell", "xml", "SetupPassword"},
+		{"dell", "json", "SetupPassword"},
+		{"supermicro", "xml", "NewSetupPassword"},
+		{"asrockrack", "xml", "Administrator Password"},
+		{"asrockrack", "json", "Administrator Password"},
+		{"hpe", "json", "AdminPassword"},
+		{"gigabyte", "json", "SETUP001"},
+	}
+
+	for _, tc := range testcases {
+		for _, value := range []string{"s3cret", RedactedValue} {
+			cm, err := NewVendorConfigManager(tc.format, tc.vendor, map[string]string{RedfishAttributeRegistryOption: string(registry)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cm.Raw(tc.name, value, nil)
+
+			// a set secret is not omitted silently
+			x, err := cm.Marshal()
+			if value != RedactedValue && !errors.Is(err, errSecretOmitted) {
+				t.Errorf("%s %s: expected errSecretOmitted, got: %v", tc.vendor, tc.format, err)
+			}
+
+			if value == RedactedValue && err != nil {
+				t.Errorf("%s %s: expected the redacted value to be omitted, got: %v", tc.vendor, tc.format, err)
+			}
+
+			if strings.Contains(x, value) {
+				t.Errorf("%s %s: expected no %s in:\n%s", tc.vendor, tc.format, value, x)
+			}
+
+			// a placeholder is not marshalled including secrets
+			cm, _ = NewVendorConfigManager(tc.format, tc.vendor, map[string]string{
+				RedfishAttributeRegistryOption: string(registry),
+				IncludeSecretsOption:           "true",
+			})
+
+			cm.Raw(tc.name, RedactedValue, nil)
+
+			if x, _ := cm.Marshal(); strings.Contains(x, RedactedValue) {
+				t.Errorf("%s %s: expected no %s including secrets in:\n%s", tc.vendor, tc.format, RedactedValue, x)
+			}
+		}
+	}
+}
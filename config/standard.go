@@ -0,0 +1,126 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// rawPrefix prefixes the keys of the settings StandardConfig does not normalize
+const rawPrefix = "raw:"
+
+// standardKeys are the normalized keys set with the generic config options
+var standardKeys = []string{"boot_mode", adminPasswordKey, userPasswordKey, "intel_sgx", "secure_boot", "smt", "sr_iov", "tpm"}
+
+// standardSetter is implemented by the config managers setting the normalized settings without
+// a generic config option by other means than the vendor catalog.
+type standardSetter interface {
+	setStandard(key, value string) error
+}
+
+// applyStandardConfig sets the normalized settings, as returned by StandardConfig, with the generic
+// config options of the VendorConfigManager. Keys prefixed with raw: are set with Raw, the other
+// keys are set with Raw as listed in the vendor catalog, see setCatalogSetting.
+func applyStandardConfig(cm VendorConfigManager, vendor string, biosConfig map[string]string) error {
+	keys := make([]string, 0, len(biosConfig))
+	for k := range biosConfig {
+		keys = append(keys, k)
+	}
+
+	// apply in a stable order
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := applyStandardSetting(cm, vendor, k, biosConfig[k]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyStandardSetting(cm VendorConfigManager, vendor, k, v string) error {
+	// redacted secrets are left as they are
+	if v == RedactedValue {
+		return nil
+	}
+
+	if strings.HasPrefix(k, rawPrefix) {
+		cm.Raw(strings.TrimPrefix(k, rawPrefix), v, nil)
+		return nil
+	}
+
+	enabled := func() (bool, error) {
+		switch normalizeValue(k, v) {
+		case enabledValue:
+			return true, nil
+		case disabledValue:
+			return false, nil
+		default:
+			return false, InvalidAttributeValue(k, v)
+		}
+	}
+
+	switch k {
+	case "boot_mode":
+		mode := normalizeBootMode(v)
+		if mode == "BIOS" {
+			mode = "LEGACY"
+		}
+
+		return cm.BootMode(mode)
+	case adminPasswordKey, userPasswordKey:
+		return cm.SetPassword(passwordKind(k), v)
+	case "intel_sgx":
+		return cm.IntelSGX(normalizeValue(k, v))
+	case "secure_boot", "smt", "sr_iov", "tpm":
+		enable, err := enabled()
+		if err != nil {
+			return err
+		}
+
+		switch k {
+		case "secure_boot":
+			return cm.SecureBoot(enable)
+		case "smt":
+			return cm.SMT(enable)
+		case "sr_iov":
+			return cm.SRIOV(enable)
+		default:
+			return cm.TPM(enable)
+		}
+	default:
+		if ss, ok := cm.(standardSetter); ok {
+			return ss.setStandard(k, v)
+		}
+
+		return setCatalogSetting(cm, vendor, k, v)
+	}
+}
+
+// setCatalogSetting sets the normalized setting with Raw, using the first native name of the vendor
+// catalog setting and the first native value mapped to the normalized value. The setting is set in
+// the catalog menu path, as Raw does when no menu path is given.
+func setCatalogSetting(cm VendorConfigManager, vendor, k, v string) error {
+	s := catalogSettingByKey(vendor, k)
+	if s == nil {
+		return UnsupportedSetting(k)
+	}
+
+	native, err := s.nativeValue(v)
+	if err != nil {
+		return err
+	}
+
+	cm.Raw(s.Names[0], native, nil)
+
+	return nil
+}
+
+// setCatalogEnabled sets the normalized setting to Enabled or Disabled, see setCatalogSetting
+func setCatalogEnabled(cm VendorConfigManager, vendor, k string, enable bool) error {
+	if enable {
+		return setCatalogSetting(cm, vendor, k, enabledValue)
+	}
+
+	return setCatalogSetting(cm, vendor, k, disabledValue)
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyStandardConfig(t *testing.T) {
+	desired := map[string]string{
+		"boot_mode":   "UEFI",
+		"secure_boot": "Enable",
+		"smt":         "off",
+		"sr_iov":      "Enabled",
+		"tpm":         "Enabled",
+	}
+
+	testcases := []struct {
+		vendor string
+		format string
+		raw    string
+		// catalog settings without a generic config option
+		catalog map[string]string
+	}{
+		{"dell", "xml", "raw:ProcVirtualization", map[string]string{"intel_txt": enabledValue}},
+		{"asrockrack", "xml", "raw:Above 4G Decoding", nil},
+		{"hpe", "json", "raw:ProcVirtualization", nil},
+		{"supermicro", "xml", "raw:Restore on AC Power Loss", map[string]string{"amd_sev": "100"}},
+	}
+
+	for _, tc := range testcases {
+		cm, err := NewVendorConfigManager(tc.format, tc.vendor, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		biosConfig := map[string]string{tc.raw: "Enabled"}
+		for k, v := range desired {
+			biosConfig[k] = v
+		}
+
+		for k, v := range tc.catalog {
+			biosConfig[k] = v
+		}
+
+		if err := cm.ApplyStandardConfig(biosConfig); err != nil {
+			t.Fatalf("%s: %v", tc.vendor, err)
+		}
+
+		got, err := cm.StandardConfig()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.vendor, err)
+		}
+
+		expected := map[string]string{
+			"boot_mode":   "UEFI",
+			"secure_boot": enabledValue,
+			"smt":         disabledValue,
+			"sr_iov":      enabledValue,
+			"tpm":         enabledValue,
+			tc.raw:        enabledValue,
+		}
+
+		for k, v := range tc.catalog {
+			expected[k] = v
+		}
+
+		for k, v := range expected {
+			if got[k] != v {
+				t.Errorf("%s: Expected %s = %s, got: %s", tc.vendor, k, v, got[k])
+			}
+		}
+	}
+}
+
+func TestApplyStandardConfigErrors(t *testing.T) {
+	cm, err := NewVendorConfigManager("xml", "dell", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.ApplyStandardConfig(map[string]string{"amd_sev": "100"}); !errors.Is(err, errUnsupportedSetting) {
+		t.Errorf("Expected errUnsupportedSetting, got: %v", err)
+	}
+
+	if err := cm.ApplyStandardConfig(map[string]string{"smt": "Auto"}); !errors.Is(err, errInvalidAttributeValue) {
+		t.Errorf("Expected errInvalidAttributeValue, got: %v", err)
+	}
+
+	if err := cm.ApplyStandardConfig(map[string]string{"boot_mode": "DUAL"}); !errors.Is(err, errInvalidBootModeOption) {
+		t.Errorf("Expected errInvalidBootModeOption, got: %v", err)
+	}
+}
+
+func TestSupermicroUnimplementedOptions(t *testing.T) {
+	cm, err := NewVendorConfigManager("xml", "supermicro", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.BootOrder("DUAL"); !errors.Is(err, errUnsupportedSetting) {
+		t.Errorf("Expected errUnsupportedSetting, got: %v", err)
+	}
+
+	if err := cm.ApplyStandardConfig(map[string]string{"intel_tdx": "Enabled"}); !errors.Is(err, errUnsupportedSetting) {
+		t.Errorf("Expected errUnsupportedSetting, got: %v", err)
+	}
+
+	// intel_txt is set as listed in the catalog
+	if err := cm.ApplyStandardConfig(map[string]string{"intel_txt": "On"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if biosConfig, _ := cm.StandardConfig(); biosConfig["intel_txt"] != enabledValue {
+		t.Errorf("Expected intel_txt Enabled, got: %v", biosConfig)
+	}
+
+	if err := cm.ApplyStandardConfig(map[string]string{"amd_sev": "1000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cm.Marshal(); !errors.Is(err, errInvalidAttributeValue) {
+		t.Errorf("Expected errInvalidAttributeValue, got: %v", err)
+	}
+}
@@ -4,20 +4,41 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/bmc-toolbox/common"
 	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/charmap"
 )
 
 const (
 	// enabledValue and disabledValue are utilized for bios setting value normalization
 	enabledValue  = "Enabled"
 	disabledValue = "Disabled"
+
+	// supermicroDefaultMenu is the menu holding settings set with Raw without a menu path
+	supermicroDefaultMenu = "Advanced"
+
+	// supermicroXMLHeader is the declaration of the ISO-8859-1 encoded xml read and written by sum
+	supermicroXMLHeader = `<?xml version="1.0" encoding="ISO-8859-1" standalone="yes"?>` + "\n"
 )
 
+// supermicroSelfClosingAttr marks the settings marshalled as self-closing elements, the empty
+// element written by the encoder is closed by Marshal.
+const supermicroSelfClosingAttr = "supermicro-self-closing"
+
+// supermicroPasswordElements matches the elements of a Password setting holding the password
+var supermicroPasswordElements = regexp.MustCompile(`(?s)\s*<(?:NewPassword|ConfirmNewPassword)>.*?</(?:NewPassword|ConfirmNewPassword)>`)
+
 type supermicroVendorConfig struct {
 	ConfigFormat string
 	ConfigData   *supermicroConfig
+
+	// includeSecrets includes the passwords in the Marshal and StandardConfig output
+	includeSecrets bool
+	// errs are the validation errors of the Raw settings, returned by Marshal
+	errs []error
 }
 
 type supermicroConfig struct {
@@ -29,21 +50,237 @@ type supermicroBiosCfg struct {
 	Menus   []*supermicroBiosCfgMenu `xml:"Menu"`
 }
 
+// supermicroBiosCfgMenu is a Menu of the sum BIOS config, the order of its settings, sub menus
+// and other elements is retained when the config is marshalled.
 type supermicroBiosCfgMenu struct {
 	XMLName  xml.Name                    `xml:"Menu"`
 	Name     string                      `xml:"name,attr"`
 	Settings []*supermicroBiosCfgSetting `xml:"Setting"`
 	Menus    []*supermicroBiosCfgMenu    `xml:"Menu"`
+
+	// attrs are the menu attributes other than name
+	attrs []xml.Attr
+	// items lists the settings, sub menus and other elements such as Information in document order
+	items []interface{}
 }
 
 type supermicroBiosCfgSetting struct {
 	XMLName        xml.Name `xml:"Setting"`
 	Name           string   `xml:"name,attr"`
-	Order          string   `xml:"order,attr"`
-	SelectedOption string   `xml:"selectedOption,attr"`
-	Type           string   `xml:"type,attr"`
-	CheckedStatus  string   `xml:"checkedStatus,attr"`
-	NumericValue   string   `xml:"numericValue,attr"`
+	Order          string   `xml:"order,attr,omitempty"`
+	SelectedOption string   `xml:"selectedOption,attr,omitempty"`
+	Type           string   `xml:"type,attr,omitempty"`
+	CheckedStatus  string   `xml:"checkedStatus,attr,omitempty"`
+	NumericValue   string   `xml:"numericValue,attr,omitempty"`
+	// Attrs are the other attributes of the setting
+	Attrs []xml.Attr `xml:",any,attr"`
+	// Inner is the content of the setting, such as the Information element listing the options, retained as is
+	Inner string `xml:",innerxml"`
+
+	// password is the password of a Password setting, marshalled as the NewPassword and ConfirmNewPassword elements
+	password string
+	// attrs are the attributes as decoded, their order is retained when the setting is marshalled
+	attrs []xml.Attr
+	// selfClosing is set for settings decoded from a self-closing element
+	selfClosing bool
+}
+
+// supermicroRawElement is an element of a Menu other than a Setting or Menu, retained as is
+type supermicroRawElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+}
+
+// UnmarshalXML decodes the menu retaining the order of its elements
+func (m *supermicroBiosCfgMenu) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	m.XMLName = start.Name
+
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "name" {
+			m.Name = attr.Value
+			continue
+		}
+
+		m.attrs = append(m.attrs, attr)
+	}
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "Setting":
+				s := &supermicroBiosCfgSetting{}
+				if err := d.DecodeElement(s, &t); err != nil {
+					return err
+				}
+
+				m.Settings = append(m.Settings, s)
+				m.items = append(m.items, s)
+			case "Menu":
+				sub := &supermicroBiosCfgMenu{}
+				if err := d.DecodeElement(sub, &t); err != nil {
+					return err
+				}
+
+				m.Menus = append(m.Menus, sub)
+				m.items = append(m.items, sub)
+			default:
+				raw := &supermicroRawElement{}
+				if err := d.DecodeElement(raw, &t); err != nil {
+					return err
+				}
+
+				m.items = append(m.items, raw)
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// MarshalXML encodes the menu elements in the order they were decoded, followed by
+// the settings and sub menus added since.
+func (m *supermicroBiosCfgMenu) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	start := xml.StartElement{
+		Name: xml.Name{Local: "Menu"},
+		Attr: append([]xml.Attr{{Name: xml.Name{Local: "name"}, Value: m.Name}}, m.attrs...),
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	encoded := map[interface{}]bool{}
+
+	for _, item := range m.items {
+		if !m.contains(item) {
+			continue
+		}
+
+		if err := e.Encode(item); err != nil {
+			return err
+		}
+
+		encoded[item] = true
+	}
+
+	for _, s := range m.Settings {
+		if !encoded[s] {
+			if err := e.Encode(s); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, sub := range m.Menus {
+		if !encoded[sub] {
+			if err := e.Encode(sub); err != nil {
+				return err
+			}
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// contains returns true when the decoded item is still listed in the menu
+func (m *supermicroBiosCfgMenu) contains(item interface{}) bool {
+	switch v := item.(type) {
+	case *supermicroBiosCfgSetting:
+		for _, s := range m.Settings {
+			if s == v {
+				return true
+			}
+		}
+
+		return false
+	case *supermicroBiosCfgMenu:
+		for _, sub := range m.Menus {
+			if sub == v {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return true
+	}
+}
+
+// UnmarshalXML decodes the setting retaining the order of its attributes and its self-closing form
+func (s *supermicroBiosCfgSetting) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type setting supermicroBiosCfgSetting
+
+	// no input is read past the start of a self-closing element
+	offset := d.InputOffset()
+
+	if err := d.DecodeElement((*setting)(s), &start); err != nil {
+		return err
+	}
+
+	s.attrs = append([]xml.Attr{}, start.Attr...)
+	s.selfClosing = d.InputOffset() == offset
+
+	return nil
+}
+
+// MarshalXML encodes the setting attributes in the order they were decoded, followed by the
+// attributes set since. Attributes emptied since are dropped.
+func (s *supermicroBiosCfgSetting) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	current := []xml.Attr{{Name: xml.Name{Local: "name"}, Value: s.Name}}
+
+	for _, attr := range []xml.Attr{
+		{Name: xml.Name{Local: "order"}, Value: s.Order},
+		{Name: xml.Name{Local: "selectedOption"}, Value: s.SelectedOption},
+		{Name: xml.Name{Local: "type"}, Value: s.Type},
+		{Name: xml.Name{Local: "checkedStatus"}, Value: s.CheckedStatus},
+		{Name: xml.Name{Local: "numericValue"}, Value: s.NumericValue},
+	} {
+		if attr.Value != "" {
+			current = append(current, attr)
+		}
+	}
+
+	current = append(current, s.Attrs...)
+
+	start := xml.StartElement{Name: xml.Name{Local: "Setting"}}
+	encoded := map[xml.Name]bool{}
+
+	for _, decoded := range s.attrs {
+		for _, attr := range current {
+			if attr.Name == decoded.Name && !encoded[attr.Name] {
+				start.Attr = append(start.Attr, attr)
+				encoded[attr.Name] = true
+			}
+		}
+	}
+
+	for _, attr := range current {
+		if !encoded[attr.Name] {
+			start.Attr = append(start.Attr, attr)
+		}
+	}
+
+	if s.selfClosing && s.Inner == "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: supermicroSelfClosingAttr}})
+	}
+
+	return e.EncodeElement(struct {
+		Inner string `xml:",innerxml"`
+	}{s.Inner}, start)
+}
+
+// MarshalXML encodes the raw element as decoded
+func (r *supermicroRawElement) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type rawElement supermicroRawElement
+
+	return e.EncodeElement((*rawElement)(r), xml.StartElement{Name: r.XMLName})
 }
 
 func NewSupermicroVendorConfigManager(configFormat string, vendorOptions map[string]string) (VendorConfigManager, error) {
@@ -60,34 +297,110 @@ func NewSupermicroVendorConfigManager(configFormat string, vendorOptions map[str
 		BiosCfg: &supermicroBiosCfg{},
 	}
 
+	supermicro.includeSecrets = includeSecrets(vendorOptions)
+
 	return supermicro, nil
 }
 
-// Function to find or create a setting by path
+// FindOrCreateSetting locates the setting at the path of menu names ending with the setting name.
+//
+// With a menu path the setting, or one of the names of its catalog setting, is looked up in the menu
+// at the path and is created there when not found. Without a menu path the setting is looked up in
+// all the menus, and is created in the Advanced menu when not found.
 func (cm *supermicroVendorConfig) FindOrCreateSetting(path []string, value string) *supermicroBiosCfgSetting {
-	biosCfg := cm.ConfigData.BiosCfg
+	if len(path) == 0 {
+		return nil
+	}
 
-	var currentMenus = &biosCfg.Menus
+	menuPath, name := path[:len(path)-1], path[len(path)-1]
 
-	for i, part := range path {
-		if i == len(path)-1 {
-			// Last part, create or find the setting
-			for j := range *currentMenus {
-				for k := range (*currentMenus)[j].Settings {
-					if (*currentMenus)[j].Settings[k].Name == part {
-						return (*currentMenus)[j].Settings[k]
+	if len(menuPath) > 0 {
+		names := []string{name}
+		if c := catalogSetting(common.VendorSupermicro, name); c != nil {
+			names = append(names, c.Names...)
+		}
+
+		if menu := findSupermicroMenu(cm.ConfigData.BiosCfg.Menus, menuPath); menu != nil {
+			for _, n := range names {
+				for _, s := range menu.Settings {
+					if s.Name == n {
+						return s
 					}
 				}
 			}
+		}
+	} else if s := findSupermicroSetting(cm.ConfigData.BiosCfg.Menus, name); s != nil {
+		return s
+	}
 
-			// If no setting found in any menu, create a new setting in the first menu
-			newSetting := supermicroBiosCfgSetting{Name: part, SelectedOption: ""}
-			(*currentMenus)[0].Settings = append((*currentMenus)[0].Settings, &newSetting)
+	if len(menuPath) == 0 {
+		menuPath = []string{supermicroDefaultMenu}
+	}
+
+	menus := &cm.ConfigData.BiosCfg.Menus
 
-			return (*currentMenus)[0].Settings[len((*currentMenus)[0].Settings)-1]
-		} else {
-			// Intermediate part, find or create the menu
-			_ = cm.FindOrCreateMenu(currentMenus, part)
+	var menu *supermicroBiosCfgMenu
+
+	for _, menuName := range menuPath {
+		menu = cm.FindOrCreateMenu(menus, menuName)
+		menus = &menu.Menus
+	}
+
+	s := &supermicroBiosCfgSetting{Name: name, Type: "Option"}
+	menu.Settings = append(menu.Settings, s)
+
+	return s
+}
+
+// findCatalogSetting returns the first setting in the menus with the given name, or one of the names
+// of its catalog setting, nil if none exists.
+func (cm *supermicroVendorConfig) findCatalogSetting(name string) *supermicroBiosCfgSetting {
+	names := []string{name}
+	if c := catalogSetting(common.VendorSupermicro, name); c != nil {
+		names = append(names, c.Names...)
+	}
+
+	for _, n := range names {
+		if s := findSupermicroSetting(cm.ConfigData.BiosCfg.Menus, n); s != nil {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// findSupermicroMenu returns the menu at the path of menu names, nil if it does not exist
+func findSupermicroMenu(menus []*supermicroBiosCfgMenu, path []string) *supermicroBiosCfgMenu {
+	if len(path) == 0 {
+		return nil
+	}
+
+	for _, m := range menus {
+		if m.Name != path[0] {
+			continue
+		}
+
+		if len(path) == 1 {
+			return m
+		}
+
+		return findSupermicroMenu(m.Menus, path[1:])
+	}
+
+	return nil
+}
+
+// findSupermicroSetting returns the first setting with the given name in the menus or their sub menus
+func findSupermicroSetting(menus []*supermicroBiosCfgMenu, name string) *supermicroBiosCfgSetting {
+	for _, m := range menus {
+		for _, s := range m.Settings {
+			if s.Name == name {
+				return s
+			}
+		}
+
+		if s := findSupermicroSetting(m.Menus, name); s != nil {
+			return s
 		}
 	}
 
@@ -108,16 +421,89 @@ func (cm *supermicroVendorConfig) FindOrCreateMenu(menus *[]*supermicroBiosCfgMe
 	return (*menus)[len(*menus)-1]
 }
 
+// Raw sets the setting in the menu at the given path. The values of the settings listed in the
+// catalog are validated, validation errors are returned by Marshal. Without a menu path an existing
+// setting is updated wherever it is, or created in its catalog menu.
 func (cm *supermicroVendorConfig) Raw(name, value string, menuPath []string) {
-	menuPath = append(menuPath, name)
+	if err := validateCatalogSetting(common.VendorSupermicro, name, value); err != nil {
+		cm.errs = append(cm.errs, err)
+		return
+	}
+
+	var s *supermicroBiosCfgSetting
+
+	if len(menuPath) == 0 {
+		s = cm.findCatalogSetting(name)
+		menuPath = catalogMenuPath(common.VendorSupermicro, name)
+	}
 
-	_ = cm.FindOrCreateSetting(menuPath, value)
+	if s == nil {
+		s = cm.FindOrCreateSetting(append(append([]string{}, menuPath...), name), value)
+	}
+
+	if s == nil {
+		return
+	}
+
+	if supermicroSecretSetting(s) {
+		s.Type = "Password"
+		s.password = value
+
+		return
+	}
+
+	switch s.Type {
+	case "CheckBox":
+		s.CheckedStatus = value
+	case "Numeric":
+		s.NumericValue = value
+	default:
+		s.SelectedOption = value
+	}
+}
+
+// supermicroSecretSetting returns true for the Password settings, and the password settings
+// set with Raw before the type is known.
+func supermicroSecretSetting(s *supermicroBiosCfgSetting) bool {
+	switch s.Name {
+	case "NewSetupPassword", "NewSysPassword", "OldSetupPassword", "OldSysPassword":
+		return true
+	default:
+		return s.Type == "Password"
+	}
 }
 
+// Marshal returns the ISO-8859-1 encoded xml config as read by sum, the password elements
+// are rendered when secrets are included, a set password is an error otherwise.
 func (cm *supermicroVendorConfig) Marshal() (string, error) {
+	if len(cm.errs) > 0 {
+		return "", cm.errs[0]
+	}
+
 	switch strings.ToLower(cm.ConfigFormat) {
 	case "xml":
-		x, err := xml.Marshal(cm.ConfigData)
+		restore, err := renderSupermicroPasswords(cm.ConfigData.BiosCfg.Menus, cm.includeSecrets)
+		defer restore()
+
+		if err != nil {
+			return "", err
+		}
+
+		buf := bytes.NewBufferString(supermicroXMLHeader)
+
+		encoder := xml.NewEncoder(buf)
+		encoder.Indent("", "  ")
+
+		if err := encoder.Encode(cm.ConfigData.BiosCfg); err != nil {
+			return "", err
+		}
+
+		buf.WriteString("\n")
+
+		// the encoder writes no self-closing elements
+		closed := bytes.ReplaceAll(buf.Bytes(), []byte(" "+supermicroSelfClosingAttr+`=""></Setting>`), []byte("/>"))
+
+		x, err := charmap.ISO8859_1.NewEncoder().Bytes(closed)
 		if err != nil {
 			return "", err
 		}
@@ -134,48 +520,145 @@ func (cm *supermicroVendorConfig) Unmarshal(cfgData string) (err error) {
 	// convert characters from non-UTF-8 to UTF-8
 	decoder.CharsetReader = charset.NewReaderLabel
 
-	return decoder.Decode(cm.ConfigData.BiosCfg)
+	if err := decoder.Decode(cm.ConfigData.BiosCfg); err != nil {
+		return err
+	}
+
+	return readSupermicroPasswords(cm.ConfigData.BiosCfg.Menus)
 }
 
-func (cm *supermicroVendorConfig) StandardConfig() (biosConfig map[string]string, err error) {
-	biosConfig = make(map[string]string)
+// readSupermicroPasswords moves the password elements of the Password settings to the setting password
+func readSupermicroPasswords(menus []*supermicroBiosCfgMenu) error {
+	for _, m := range menus {
+		for _, s := range m.Settings {
+			if s.Type != "Password" {
+				continue
+			}
 
-	for _, menu := range cm.ConfigData.BiosCfg.Menus {
-		for _, s := range menu.Settings {
-			switch s.Name {
-			// We want to drop this list of settings
-			case "NewSetupPassword", "NewSysPassword", "OldSetupPassword", "OldSysPassword":
-			// All others get normalized
-			default:
-				var k, v string
-				k, v, err = normalizeSetting(s)
+			for _, element := range supermicroPasswordElements.FindAllString(s.Inner, -1) {
+				password := struct {
+					XMLName xml.Name
+					Value   string `xml:",chardata"`
+				}{}
+
+				if err := xml.Unmarshal([]byte(element), &password); err != nil {
+					return err
+				}
+
+				if password.XMLName.Local == "NewPassword" {
+					s.password = password.Value
+				}
+			}
+
+			s.Inner = supermicroPasswordElements.ReplaceAllString(s.Inner, "")
+		}
+
+		if err := readSupermicroPasswords(m.Menus); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderSupermicroPasswords adds the password elements of the Password settings for the passwords set,
+// the returned func restores the settings once marshalled.
+func renderSupermicroPasswords(menus []*supermicroBiosCfgMenu, include bool) (restore func(), err error) {
+	inner := map[*supermicroBiosCfgSetting]string{}
+
+	var render func(menus []*supermicroBiosCfgMenu) error
+
+	render = func(menus []*supermicroBiosCfgMenu) error {
+		for _, m := range menus {
+			for _, s := range m.Settings {
+				if s.Type != "Password" {
+					continue
+				}
 
+				v, ok, err := marshalSecret(s.Name, s.password, include)
 				if err != nil {
-					return
+					return err
 				}
 
-				biosConfig[k] = v
+				if !ok || v == "" {
+					continue
+				}
+
+				inner[s] = s.Inner
+
+				// the elements are indented as the Information element following them
+				indent := s.Inner[:len(s.Inner)-len(strings.TrimLeft(s.Inner, " \t\r\n"))]
+
+				s.Inner = indent + "<NewPassword>" + supermicroCDATA(v) + "</NewPassword>" +
+					indent + "<ConfirmNewPassword>" + supermicroCDATA(v) + "</ConfirmNewPassword>" + s.Inner
+			}
+
+			if err := render(m.Menus); err != nil {
+				return err
 			}
 		}
+
+		return nil
 	}
 
+	err = render(menus)
+
+	return func() {
+		for s, v := range inner {
+			s.Inner = v
+		}
+	}, err
+}
+
+// supermicroCDATA returns the value as a CDATA section, as sum writes the setting text values
+func supermicroCDATA(v string) string {
+	return "<![CDATA[" + strings.ReplaceAll(v, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
+
+func (cm *supermicroVendorConfig) StandardConfig() (biosConfig map[string]string, err error) {
+	biosConfig = make(map[string]string)
+
+	err = normalizeSupermicroMenus(cm.ConfigData.BiosCfg.Menus, biosConfig, cm.includeSecrets)
+
 	return biosConfig, err
 }
 
+func normalizeSupermicroMenus(menus []*supermicroBiosCfgMenu, biosConfig map[string]string, includeSecrets bool) error {
+	for _, menu := range menus {
+		for _, s := range menu.Settings {
+			if supermicroSecretSetting(s) {
+				// unset passwords are dropped
+				if v, ok := redactSecret(s.password, includeSecrets); ok && v != "" {
+					biosConfig[normalizeName(common.VendorSupermicro, s.Name)] = v
+				}
+
+				continue
+			}
+
+			k, v, err := normalizeSetting(s)
+			if err != nil {
+				return err
+			}
+
+			biosConfig[k] = v
+		}
+
+		if err := normalizeSupermicroMenus(menu.Menus, biosConfig, includeSecrets); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func normalizeSetting(s *supermicroBiosCfgSetting) (k, v string, err error) {
 	switch s.Type {
 	case "CheckBox":
-		k = normalizeName(s.Name)
-		v = normalizeValue(k, s.CheckedStatus)
+		k, v = normalizeCatalogSetting(common.VendorSupermicro, s.Name, s.CheckedStatus)
 	case "Option":
-		k = normalizeName(s.Name)
-		v = normalizeValue(k, s.SelectedOption)
-	case "Password":
-		k = normalizeName(s.Name)
-		v = ""
+		k, v = normalizeCatalogSetting(common.VendorSupermicro, s.Name, s.SelectedOption)
 	case "Numeric":
-		k = normalizeName(s.Name)
-		v = normalizeValue(k, s.NumericValue)
+		k, v = normalizeCatalogSetting(common.VendorSupermicro, s.Name, s.NumericValue)
 	default:
 		err = UnknownSettingType(s.Type)
 		return
@@ -184,33 +667,20 @@ func normalizeSetting(s *supermicroBiosCfgSetting) (k, v string, err error) {
 	return
 }
 
-func normalizeName(k string) string {
-	switch k {
-	case "CpuMinSevAsid":
-		return "amd_sev"
-	case "BootMode", "Boot mode select":
-		return "boot_mode"
-	case "IntelTxt":
-		return "intel_txt"
-	case "Software Guard Extensions (SGX)":
-		return "intel_sgx"
-	case "SecureBoot", "Secure Boot":
-		return "secure_boot"
-	case "Hyper-Threading", "Hyper-Threading [ALL]", "LogicalProc":
-		return "smt"
-	case "SriovGlobalEnable":
-		return "sr_iov"
-	case "TpmSecurity", "Security Device Support":
-		return "tpm"
-	default:
-		// When we don't normalize the key prepend "raw:"
-		return "raw:" + k
+// normalizeName returns the normalized key of the native setting name listed in the vendor catalog,
+// or the raw: prefixed name.
+func normalizeName(vendor, k string) string {
+	if s := catalogSetting(vendor, k); s != nil {
+		return s.Key
 	}
+
+	// When we don't normalize the key prepend "raw:"
+	return rawPrefix + k
 }
 
 func normalizeBootMode(v string) string {
 	switch strings.ToLower(v) {
-	case "legacy":
+	case "legacy", "legacybios":
 		return "BIOS"
 	default:
 		return strings.ToUpper(v)
@@ -240,17 +710,55 @@ func normalizeValue(k, v string) string {
 	}
 }
 
+func (cm *supermicroVendorConfig) menuPaths() map[string][]string {
+	paths := map[string][]string{}
+
+	var walk func(menus []*supermicroBiosCfgMenu, parent []string)
+
+	walk = func(menus []*supermicroBiosCfgMenu, parent []string) {
+		for _, menu := range menus {
+			path := append(append([]string{}, parent...), menu.Name)
+
+			for _, s := range menu.Settings {
+				paths[normalizeName(common.VendorSupermicro, s.Name)] = path
+			}
+
+			walk(menu.Menus, path)
+		}
+	}
+
+	walk(cm.ConfigData.BiosCfg.Menus, nil)
+
+	return paths
+}
+
+// SetPassword sets the BIOS administrator or user password in the Security menu
+func (cm *supermicroVendorConfig) SetPassword(kind, password string) error {
+	switch kind {
+	case PasswordAdmin:
+		cm.Raw("NewSetupPassword", password, []string{"Security"})
+	case PasswordUser:
+		cm.Raw("NewSysPassword", password, []string{"Security"})
+	default:
+		return InvalidPasswordKind(kind)
+	}
+
+	return nil
+}
+
+func (cm *supermicroVendorConfig) ApplyStandardConfig(biosConfig map[string]string) error {
+	return applyStandardConfig(cm, common.VendorSupermicro, biosConfig)
+}
+
 // Generic config options
 
 func (cm *supermicroVendorConfig) BootMode(mode string) error {
 	switch strings.ToUpper(mode) {
 	case "LEGACY", "UEFI", "DUAL":
-		cm.Raw("Boot mode select", strings.ToUpper(mode), []string{"Boot"})
+		return setCatalogSetting(cm, common.VendorSupermicro, "boot_mode", strings.ToUpper(mode))
 	default:
 		return InvalidBootModeOption(strings.ToUpper(mode))
 	}
-
-	return nil
 }
 
 func (cm *supermicroVendorConfig) BootOrder(mode string) error {
@@ -273,7 +781,8 @@ func (cm *supermicroVendorConfig) BootOrder(mode string) error {
 			cm.Raw("UEFI Boot Option #"+fmt.Sprint(i), "Disabled", []string{"Boot"})
 		}
 	case "DUAL":
-		// TODO(jwb) Is this just both sets?
+		// the order of the legacy and UEFI boot options in DUAL mode is not known
+		return UnsupportedSetting("boot_order " + strings.ToUpper(mode))
 	default:
 		return InvalidBootModeOption(strings.ToUpper(mode))
 	}
@@ -284,50 +793,40 @@ func (cm *supermicroVendorConfig) BootOrder(mode string) error {
 func (cm *supermicroVendorConfig) IntelSGX(mode string) error {
 	switch mode {
 	case "Disabled", "Enabled", "Software Controlled":
-		// TODO(jwb) Path needs to be determined.
-		cm.Raw("Software Guard Extensions (SGX)", mode, []string{"Advanced", "PCIe/PCI/PnP Configuration"})
+		return setCatalogSetting(cm, common.VendorSupermicro, "intel_sgx", mode)
 	default:
 		return InvalidSGXOption(mode)
 	}
-
-	return nil
 }
 
 func (cm *supermicroVendorConfig) SecureBoot(enable bool) error {
-	if !enable {
-		cm.Raw("Secure Boot", "Disabled", []string{"SMC Secure Boot Configuration"})
-	} else {
-		cm.Raw("Secure Boot", "Enabled", []string{"SMC Secure Boot Configuration"})
-	}
-
-	return nil
+	return setCatalogEnabled(cm, common.VendorSupermicro, "secure_boot", enable)
 }
 
+// TPM sets the security device support and the SHA-1 PCR bank, held in the same menu
 func (cm *supermicroVendorConfig) TPM(enable bool) error {
-	if enable {
-		// Note, this is actually 'Enable' not 'Enabled' like everything else.
-		cm.Raw(" Security Device Support", "Enable", []string{"Trusted Computing"})
-		cm.Raw(" SHA-1 PCR Bank", "Enabled", []string{"Trusted Computing"})
-	} else {
-		// Note, this is actually 'Disable' not 'Disabled' like everything else.
-		cm.Raw(" Security Device Support", "Disable", []string{"Trusted Computing"})
-		cm.Raw(" SHA-1 PCR Bank", "Disabled", []string{"Trusted Computing"})
+	if err := setCatalogEnabled(cm, common.VendorSupermicro, "tpm", enable); err != nil {
+		return err
 	}
 
-	return nil
-}
+	var menuPath []string
+	if s := catalogSettingByKey(common.VendorSupermicro, "tpm"); s != nil {
+		menuPath = s.MenuPath
+	}
 
-func (cm *supermicroVendorConfig) SMT(enable bool) error {
 	if enable {
-		cm.Raw("Hyper-Threading", "Enabled", []string{"Advanced", "CPU Configuration"})
+		cm.Raw(" SHA-1 PCR Bank", "Enabled", menuPath)
 	} else {
-		cm.Raw("Hyper-Threading", "Disabled", []string{"Advanced", "CPU Configuration"})
+		cm.Raw(" SHA-1 PCR Bank", "Disabled", menuPath)
 	}
 
 	return nil
 }
 
+func (cm *supermicroVendorConfig) SMT(enable bool) error {
+	return setCatalogEnabled(cm, common.VendorSupermicro, "smt", enable)
+}
+
 func (cm *supermicroVendorConfig) SRIOV(enable bool) error {
-	// TODO(jwb) Need to figure out how we do this on platforms that support it...
-	return nil
+	return setCatalogEnabled(cm, common.VendorSupermicro, "sr_iov", enable)
 }
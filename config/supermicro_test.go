@@ -1,6 +1,9 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -84,4 +87,190 @@ func TestSupermicroVendorConfig_FindOrCreateSetting(t *testing.T) {
 	if existingSetting != cm.ConfigData.BiosCfg.Menus[0].Settings[0] {
 		t.Errorf("Expected setting: %v, got: %v", cm.ConfigData.BiosCfg.Menus[0].Settings[0], existingSetting)
 	}
+
+	// A setting in another menu is not returned for a path, the setting is created at the path
+	created := cm.FindOrCreateSetting([]string{"Menu2", "Setting1"}, "Option1")
+	if created == existingSetting || len(cm.ConfigData.BiosCfg.Menus) != 2 || cm.ConfigData.BiosCfg.Menus[1].Settings[0] != created {
+		t.Errorf("Expected Setting1 to be created in Menu2, got: %+v", cm.ConfigData.BiosCfg.Menus)
+	}
+
+	// Without a path the setting is looked up in all the menus
+	if found := cm.FindOrCreateSetting([]string{"Setting2"}, "Option2"); found != setting {
+		t.Errorf("Expected setting: %v, got: %v", setting, found)
+	}
+}
+
+func TestSupermicroVendorConfig_RoundTrip(t *testing.T) {
+	for _, board := range []string{"x11dph-t", "x12spo-ntf", "h12ssl-i"} {
+		t.Run(board, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", "supermicro", board+".xml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cm, err := NewSupermicroVendorConfigManager("xml", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cm.Unmarshal(string(golden)); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := cm.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != string(golden) {
+				t.Errorf("round trip differs from %s.xml, got:\n%s", board, got)
+			}
+		})
+	}
+}
+
+func TestSupermicroVendorConfig_RawNested(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "supermicro", "x12spo-ntf.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm := &supermicroVendorConfig{ConfigFormat: "xml", ConfigData: &supermicroConfig{BiosCfg: &supermicroBiosCfg{}}}
+	if err := cm.Unmarshal(string(golden)); err != nil {
+		t.Fatal(err)
+	}
+
+	path := []string{"Advanced", "Chipset Configuration", "North Bridge", "IIO Configuration"}
+	name := "Intel® VT for Directed I/O (VT-d)"
+
+	cm.Raw(name, "Disable", path)
+
+	s := findSupermicroMenu(cm.ConfigData.BiosCfg.Menus, path).Settings[0]
+	if s.Name != name || s.SelectedOption != "Disable" {
+		t.Errorf("expected %s to be updated in place, got: %+v", name, s)
+	}
+
+	// without a path the setting is found in its nested menu
+	cm.Raw("PRMRR Size", "2G", nil)
+
+	if s := findSupermicroSetting(cm.ConfigData.BiosCfg.Menus, "PRMRR Size"); s.SelectedOption != "2G" {
+		t.Errorf("expected PRMRR Size 2G, got: %s", s.SelectedOption)
+	}
+
+	// a setting missing from the config creates the menus along the path
+	cm.Raw("Re-Size BAR Support", "Enabled", []string{"Advanced", "PCIe/PCI/PnP Configuration", "Resizable BAR"})
+
+	m := findSupermicroMenu(cm.ConfigData.BiosCfg.Menus, []string{"Advanced", "PCIe/PCI/PnP Configuration", "Resizable BAR"})
+	if m == nil || len(m.Settings) != 1 || m.Settings[0].SelectedOption != "Enabled" {
+		t.Fatalf("expected the Resizable BAR menu to be created, got: %+v", m)
+	}
+
+	x, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the marshalled config remains readable and ISO-8859-1 encoded
+	if !strings.Contains(x, "Intel\xae VT for Directed I/O (VT-d)") {
+		t.Error("expected ISO-8859-1 encoded setting names")
+	}
+
+	if err := cm.Unmarshal(x); err != nil {
+		t.Fatal(err)
+	}
+
+	biosConfig, err := cm.StandardConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if biosConfig["sr_iov"] != "Enabled" {
+		t.Errorf("expected sr_iov Enabled, got: %s", biosConfig["sr_iov"])
+	}
+}
+
+func TestSupermicroVendorConfig_RawNumericAndCheckBox(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "supermicro", "x11dph-t.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm := &supermicroVendorConfig{ConfigFormat: "xml", ConfigData: &supermicroConfig{BiosCfg: &supermicroBiosCfg{}}}
+	if err := cm.Unmarshal(string(golden)); err != nil {
+		t.Fatal(err)
+	}
+
+	cm.Raw("Thermal Trip Temperature (°C)", "90", nil)
+	cm.Raw(" SHA-1 PCR Bank", "Unchecked", []string{"Advanced", "Trusted Computing"})
+
+	x, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<Setting name="Thermal Trip Temperature (` + "\xb0" + `C)" numericValue="90" type="Numeric">`,
+		`<Setting name=" SHA-1 PCR Bank" checkedStatus="Unchecked" type="CheckBox">`,
+	} {
+		if !strings.Contains(x, want) {
+			t.Errorf("expected %q in the marshalled config", want)
+		}
+	}
+}
+
+func TestSupermicroVendorConfig_SettingAttributes(t *testing.T) {
+	cm, err := NewSupermicroVendorConfigManager("xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := supermicroXMLHeader + `<BiosCfg>
+  <Menu name="Advanced">
+    <Setting type="Option" name="Above 4G Decoding" selectedOption="Disabled"/>
+    <Setting selectedOption="Disabled" name="SR-IOV Support" type="Option"></Setting>
+  </Menu>
+</BiosCfg>
+`
+
+	if err := cm.Unmarshal(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	cm.Raw("Above 4G Decoding", "Enabled", []string{"Advanced"})
+	cm.Raw("SR-IOV Support", "Enabled", []string{"Advanced"})
+
+	x, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the attributes keep their order, the elements their form
+	want := strings.NewReplacer(`selectedOption="Disabled"`, `selectedOption="Enabled"`).Replace(cfg)
+	if x != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, x)
+	}
+}
+
+func TestSupermicroVendorConfig_RawEmpty(t *testing.T) {
+	cm, err := NewSupermicroVendorConfigManager("xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm.Raw("Quiet Boot", "Disabled", nil)
+
+	x, err := cm.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := supermicroXMLHeader + `<BiosCfg>
+  <Menu name="Advanced">
+    <Setting name="Quiet Boot" selectedOption="Disabled" type="Option"></Setting>
+  </Menu>
+</BiosCfg>
+`
+	if x != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, x)
+	}
 }
@@ -41,6 +41,8 @@ const (
 	SlugBIOS                  = "BIOS"
 	SlugDrive                 = "Drive"
 	SlugDrives                = "Drives"
+	SlugVirtualDisk           = "VirtualDisk"
+	SlugVirtualDisks          = "VirtualDisks"
 	SlugDriveTypePCIeNVMEeSSD = "NVMe-PCIe-SSD"
 	SlugDriveTypeSATASSD      = "Sata-SSD"
 	SlugDriveTypeSATAHDD      = "Sata-HDD"
@@ -82,6 +84,8 @@ func ComponentTypes() []string {
 		SlugBIOS,
 		SlugDrive,
 		SlugDrives,
+		SlugVirtualDisk,
+		SlugVirtualDisks,
 		SlugDriveTypePCIeNVMEeSSD,
 		SlugDriveTypeSATASSD,
 		SlugDriveTypeSATAHDD,
@@ -100,139 +104,88 @@ func ComponentTypes() []string {
 // FormatVendorName compares the given strings to identify and returned a known
 // vendor name. When a match is not found, the string is returned as is.
 //
-// Note: This method will most likely return incorrect matches if the given
-// vendor string is too short and or not unique enough.
-//
-// nolint:gocyclo // This list is expected to be long.
+// The vendors are identified by the registry, see MatchVendorName and RegisterVendor.
+// Check the match confidence with MatchVendorName when the name given may be
+// short or not unique enough.
 func FormatVendorName(name string) string {
-	v := strings.TrimSpace(strings.ToLower(name))
-
-	switch v {
-	case "hp", "hpe":
-		return VendorHPE
-	case "ami":
-		return VendorAmericanMegatrends
-	case "lsi":
-		return VendorLSI
-	case "amd":
-		return VendorAMD
+	if m, ok := MatchVendorName(name); ok {
+		return m.Vendor
 	}
 
-	switch {
-	case strings.Contains(v, VendorAMD):
-		return VendorAMD
-	case strings.Contains(v, VendorAsrockrack):
-		return VendorAsrockrack
-	case strings.Contains(v, VendorDell):
-		return VendorDell
-	case strings.Contains(v, VendorSupermicro):
-		return VendorSupermicro
-	case strings.Contains(v, VendorQuanta):
-		return VendorQuanta
-	case strings.Contains(v, VendorGigabyte):
-		return VendorGigabyte
-	case strings.Contains(v, VendorIntel):
-		return VendorIntel
-	case strings.Contains(v, VendorPacket):
-		return VendorPacket
-	case strings.Contains(v, VendorHynix):
-		return VendorHynix
-	case strings.Contains(v, VendorInfineon):
-		return VendorInfineon
-	case strings.Contains(v, VendorBroadcom):
-		return VendorBroadcom
-	case strings.Contains(v, VendorMellanox):
-		return VendorMellanox
-	case strings.Contains(v, VendorHGST):
-		return VendorHGST
-	case strings.Contains(v, VendorToshiba):
-		return VendorToshiba
-	case strings.Contains(v, VendorMicron):
-		return VendorMicron
-	case strings.Contains(v, VendorAmericanMegatrends):
-		return VendorAmericanMegatrends
-	case strings.Contains(v, VendorSamsung):
-		return VendorSamsung
-	case strings.Contains(v, VendorMarvell):
-		return VendorMarvell
-	default:
-		return name
-	}
+	return name
+}
+
+// placeholderValues are the values firmware reports for unset or unpopulated attributes,
+// in lower case.
+var placeholderValues = map[string]bool{
+	strings.ToLower(SystemManufacturerUndefined): true,
+	"to be filled by o.e.m":                      true,
+	"not specified":                              true,
+	"not available":                              true,
+	"not provided":                               true,
+	"notprovided":                                true,
+	"no dimm":                                    true,
+	"no module installed":                        true,
+	"[empty]":                                    true,
+	"empty":                                      true,
+	"unknown":                                    true,
+	"none":                                       true,
+	"default string":                             true,
+	"0123456789":                                 true,
+	"123456789":                                  true,
+	"system serial number":                       true,
+	"system product name":                        true,
+	"system manufacturer":                        true,
+	"system version":                             true,
+	"base board serial number":                   true,
+	"chassis serial number":                      true,
+	"o.e.m.":                                     true,
+}
+
+// IsPlaceholder returns true when the given value is a placeholder string reported
+// by the firmware for an unset attribute, for example SystemManufacturerUndefined.
+func IsPlaceholder(s string) bool {
+	return placeholderValues[strings.ToLower(strings.TrimSpace(s))]
 }
 
 // Return the product vendor name, given a product name/model string
+//
+// The vendors are identified by the registry, see MatchVendorProduct and RegisterVendor.
 func VendorFromString(s string) string {
-	s = strings.ToLower(s)
-
-	switch {
-	case strings.Contains(s, "dell"):
-		return VendorDell
-	case strings.Contains(s, "lsi3008-it"):
-		return VendorLSI
-	case strings.Contains(s, "hgst "):
-		return VendorHGST
-	case strings.Contains(s, "intel "):
-		return VendorIntel
-	case strings.Contains(s, "micron_"), strings.HasPrefix(s, "mtfd"):
-		return VendorMicron
-	case strings.Contains(s, "toshiba"):
-		return VendorToshiba
-	case strings.Contains(s, "connectx4lx"):
-		return VendorMellanox
-	case strings.Contains(s, "infineon"):
-		return VendorInfineon
-	case strings.Contains(s, VendorMarvell), strings.Contains(s, VendorMarvellPciID):
-		return VendorMarvell
-	default:
-		return ""
+	if m, ok := MatchVendorProduct(s); ok {
+		return m.Vendor
 	}
+
+	return ""
 }
 
 // Return a normalized product name given a product name
 //
-// nolint:gocyclo // This list is expected to be long.
+// The product names are mapped by the model catalog, see ModelByProductName and RegisterModel.
+// Unlike ModelByProductName the product name is matched exactly, other strings are returned as given.
 func FormatProductName(s string) string {
-	switch s {
-	case "PowerEdge R6515":
-		return "r6515"
-	case "PowerEdge R640":
-		return "r640"
-	case "PowerEdge R6415":
-		return "r6415"
-	case "PowerEdge R750":
-		return "r750"
-	case "PowerEdge C6320":
-		return "c6320"
-	case "PIO-519C-MR-PH004":
-		return "x11sch-f"
-	case "SYS-5019C-MR-PH004", "SYS-5019C-MR":
-		return "x11scm-f"
-	case "SYS-5039MS-H12TRF":
-		return "x11sse-f"
-	case "SYS-510T-MR-EI018", "SYS-510T-MR1-EI018", "SYS-510T-MR2-EI018":
-		return "x12sth-sys"
-	case "SSG-6029P-E1CR12L-PH004", "SSG-6029P-E1CR12L":
-		return "x11dph-t"
-	case "SSG-110P-NTR10", "SSG-110P-NTR10-EI018", "SSG-110P-NTR10-2-EI018":
-		return "x12spo-ntf"
-	case "Micron_5200_MTFDDAK480TDN":
-		return "5200MAX"
-	case "SYS-221H-TN24R":
-		return "x13dem"
-	default:
+	m, ok := ModelByProductName(s)
+	if !ok {
 		return s
 	}
+
+	for _, name := range m.ProductNames {
+		if name == s {
+			return m.Model
+		}
+	}
+
+	return s
 }
 
 // Returns the Supermicro motherboard model for the given BoardID identifier
 // from /redfish/v1/Chassis/1
+//
+// The board IDs are mapped by the model catalog, see ModelByBoardID and RegisterModel.
 func SupermicroModelFromBoardID(id string) string {
-	switch strings.ToLower(id) {
-	case "0x89a":
-		return "x11ssl-f"
-	case "0x1b09": // 15d9:1b09 is the Q370 Chipset LPC/eSPI Controller on the board
-		return "x11scz-f"
-	default:
-		return ""
+	if m, ok := ModelByBoardID(VendorSupermicro, id); ok {
+		return m.Model
 	}
+
+	return ""
 }
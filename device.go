@@ -23,6 +23,9 @@ type Common struct {
 type Device struct {
 	Common
 
+	// SchemaVersion is the version of the serialized Device, it is set to DeviceSchemaVersion when marshalled.
+	SchemaVersion int `json:"schema_version" diff:"ignore"`
+
 	HardwareType       string               `json:"hardware_type,omitempty"`
 	Chassis            string               `json:"chassis,omitempty"`
 	BIOS               *BIOS                `json:"bios,omitempty"`
@@ -38,11 +41,13 @@ type Device struct {
 	StorageControllers []*StorageController `json:"storage_controller,omitempty"`
 	PSUs               []*PSU               `json:"power_supplies,omitempty"`
 	Enclosures         []*Enclosure         `json:"enclosures,omitempty"`
+	VirtualDisks       []*VirtualDisk       `json:"virtual_disks,omitempty"`
 }
 
 // NewDevice returns a pointer to an initialized Device type
 func NewDevice() Device {
 	return Device{
+		SchemaVersion:      DeviceSchemaVersion,
 		BMC:                &BMC{NIC: &NIC{}},
 		BIOS:               &BIOS{},
 		Mainboard:          &Mainboard{},
@@ -56,6 +61,7 @@ func NewDevice() Device {
 		Drives:             []*Drive{},
 		StorageControllers: []*StorageController{},
 		Enclosures:         []*Enclosure{},
+		VirtualDisks:       []*VirtualDisk{},
 	}
 }
 
@@ -82,8 +88,8 @@ type Capability struct {
 
 // Status is the health status of a component
 type Status struct {
-	Health         string
-	State          string
+	Health         string `json:"health"`
+	State          string `json:"state"`
 	PostCode       int    `json:"post_code,omitempty"`
 	PostCodeStatus string `json:"post_code_status,omitempty"`
 }
@@ -145,7 +151,7 @@ type CPU struct {
 	ID           string `json:"id,omitempty"`
 	Slot         string `json:"slot,omitempty"`
 	Architecture string `json:"architecture,omitempty"`
-	ClockSpeedHz int64  `json:"clock_speeed_hz,omitempty"`
+	ClockSpeedHz int64  `json:"clock_speed_hz,omitempty"`
 	Cores        int    `json:"cores,omitempty"`
 	Threads      int    `json:"threads,omitempty"`
 }
@@ -230,13 +236,18 @@ type Drive struct {
 }
 
 // VirtualDisk models RAID arrays
+//
+// The owning StorageController and member Drives are referenced by their ID,
+// use Device.StorageTree to resolve them.
 type VirtualDisk struct {
-	ID             string   `json:"id,omitempty"`
-	Name           string   `json:"name,omitempty"`
-	RaidType       string   `json:"raid_type,omitempty"`
-	SizeBytes      int64    `json:"size_bytes,omitempty"`
-	Status         string   `json:"status,omitempty"`
-	PhysicalDrives []*Drive `json:"physical_drives,omitempty"`
+	ID                 string   `json:"id,omitempty"`
+	Name               string   `json:"name,omitempty"`
+	RaidType           string   `json:"raid_type,omitempty"`
+	RaidImplementation string   `json:"raid_implementation,omitempty"` // SlugRAIDImpl* - hardware, linuxsw, zfs
+	StorageController  string   `json:"storage_controller,omitempty"`  // StorageController ID, empty for software RAID
+	SizeBytes          int64    `json:"size_bytes,omitempty"`
+	Status             string   `json:"status,omitempty"`
+	DriveIDs           []string `json:"drive_ids,omitempty"` // member Drive IDs
 }
 
 // DriveSmartAttributes holds SMART attributes for a drive.
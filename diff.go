@@ -0,0 +1,469 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeType identifies the kind of change recorded in a ChangeSet
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "changed"
+
+	// Struct tag and tag options recognized by Diff.
+	//
+	// `diff:"ignore"` excludes a field from comparison,
+	// `diff:"immutable"` flags changes to the field as Immutable.
+	diffTag          = "diff"
+	diffTagIgnore    = "ignore"
+	diffTagImmutable = "immutable"
+
+	// deviceComponent is the Component value for top level Device fields.
+	deviceComponent = "Device"
+)
+
+// diffComponentSlugs maps component field names to the slug set on Change.Component
+var diffComponentSlugs = map[string]string{
+	"BIOS":               SlugBIOS,
+	"BMC":                SlugBMC,
+	"Mainboard":          SlugMainboard,
+	"CPLDs":              SlugCPLD,
+	"TPMs":               SlugTPM,
+	"GPUs":               SlugGPU,
+	"CPUs":               SlugCPU,
+	"Memory":             SlugPhysicalMem,
+	"NICs":               SlugNIC,
+	"NICPorts":           SlugNICPort,
+	"Drives":             SlugDrive,
+	"StorageControllers": SlugStorageController,
+	"PSUs":               SlugPSU,
+	"Enclosures":         SlugEnclosure,
+	"VirtualDisks":       SlugVirtualDisk,
+}
+
+// diffIdentityFields lists the fields used to match list elements between
+// two snapshots, in order of precedence.
+var diffIdentityFields = []struct{ field, label string }{
+	{"Serial", "serial"},
+	{"Slot", "slot"},
+	{"ID", "id"},
+	{"BusInfo", "bus_info"},
+}
+
+// Change describes a single difference between two Device snapshots.
+//
+// For added and removed components Field is empty and Old/New hold the component itself.
+type Change struct {
+	Type      ChangeType  `json:"type"`
+	Component string      `json:"component"`
+	Identity  string      `json:"identity,omitempty"`
+	Field     string      `json:"field,omitempty"`
+	Old       interface{} `json:"old,omitempty"`
+	New       interface{} `json:"new,omitempty"`
+	Immutable bool        `json:"immutable,omitempty"`
+}
+
+func (c *Change) String() string {
+	var s strings.Builder
+
+	s.WriteString(string(c.Type) + " " + c.Component)
+
+	if c.Identity != "" {
+		s.WriteString("[" + c.Identity + "]")
+	}
+
+	if c.Field != "" {
+		s.WriteString("." + c.Field)
+	}
+
+	if c.Type == ChangeModified {
+		s.WriteString(fmt.Sprintf(": %v -> %v", c.Old, c.New))
+	}
+
+	return s.String()
+}
+
+// ChangeSet is the list of changes returned by Diff
+type ChangeSet struct {
+	Changes []*Change `json:"changes"`
+}
+
+// Empty returns true when the ChangeSet holds no changes
+func (cs *ChangeSet) Empty() bool {
+	return len(cs.Changes) == 0
+}
+
+// Added returns the changes for components or elements present only in the after snapshot
+func (cs *ChangeSet) Added() []*Change {
+	return cs.filter(func(c *Change) bool { return c.Type == ChangeAdded })
+}
+
+// Removed returns the changes for components or elements present only in the before snapshot
+func (cs *ChangeSet) Removed() []*Change {
+	return cs.filter(func(c *Change) bool { return c.Type == ChangeRemoved })
+}
+
+// Modified returns the field level changes on components present in both snapshots
+func (cs *ChangeSet) Modified() []*Change {
+	return cs.filter(func(c *Change) bool { return c.Type == ChangeModified })
+}
+
+// Immutable returns the changes made to fields tagged `diff:"immutable"`
+func (cs *ChangeSet) Immutable() []*Change {
+	return cs.filter(func(c *Change) bool { return c.Immutable })
+}
+
+// ForComponent returns the changes on the component identified by the given slug
+func (cs *ChangeSet) ForComponent(slug string) []*Change {
+	return cs.filter(func(c *Change) bool { return c.Component == slug })
+}
+
+func (cs *ChangeSet) filter(fn func(c *Change) bool) []*Change {
+	changes := []*Change{}
+
+	for _, c := range cs.Changes {
+		if fn(c) {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// Diff compares two Device snapshots and returns the changes required to go from before to after.
+//
+// Components in lists are matched by their Serial, then Slot, then ID and then BusInfo,
+// falling back to the list position when none of those are set. Fields tagged
+// `diff:"ignore"` are skipped and changes to fields tagged `diff:"immutable"` are flagged.
+func Diff(before, after *Device) *ChangeSet {
+	if before == nil {
+		before = &Device{}
+	}
+
+	if after == nil {
+		after = &Device{}
+	}
+
+	d := &differ{cs: &ChangeSet{}}
+
+	a := reflect.ValueOf(before).Elem()
+	b := reflect.ValueOf(after).Elem()
+
+	d.diffStruct(diffContext{component: deviceComponent}, a, b, false)
+
+	return d.cs
+}
+
+// diffContext holds the location of the values being compared
+type diffContext struct {
+	component string
+	identity  string
+	path      string
+}
+
+func (c diffContext) field(name string) diffContext {
+	if c.path != "" {
+		name = c.path + "." + name
+	}
+
+	return diffContext{component: c.component, identity: c.identity, path: name}
+}
+
+type differ struct {
+	cs *ChangeSet
+}
+
+func (d *differ) add(c *Change) {
+	d.cs.Changes = append(d.cs.Changes, c)
+}
+
+func (d *differ) diffStruct(ctx diffContext, a, b reflect.Value, immutable bool) {
+	t := a.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		// skip unexported fields
+		if f.PkgPath != "" {
+			continue
+		}
+
+		ignore, fImmutable := parseDiffTag(f.Tag.Get(diffTag))
+		if ignore {
+			continue
+		}
+
+		fImmutable = fImmutable || immutable
+
+		// embedded structs are flattened into the parent
+		if f.Anonymous {
+			d.diffValue(ctx, a.Field(i), b.Field(i), fImmutable)
+			continue
+		}
+
+		fctx := ctx.field(f.Name)
+
+		if slug, ok := diffComponentSlugs[f.Name]; ok {
+			// nested components carry their parent identity as a prefix
+			var prefix string
+			if ctx.identity != "" {
+				prefix = ctx.identity + "/"
+			}
+
+			fctx = diffContext{component: slug, identity: prefix}
+		}
+
+		d.diffValue(fctx, a.Field(i), b.Field(i), fImmutable)
+	}
+}
+
+func (d *differ) diffValue(ctx diffContext, a, b reflect.Value, immutable bool) {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() && b.IsNil() {
+			return
+		}
+
+		if a.IsNil() || b.IsNil() {
+			d.addOrRemove(ctx, a, b, immutable)
+			return
+		}
+
+		d.diffValue(ctx, a.Elem(), b.Elem(), immutable)
+	case reflect.Struct:
+		d.diffStruct(ctx, a, b, immutable)
+	case reflect.Slice:
+		if isStructList(a.Type()) {
+			d.diffList(ctx, a, b, immutable)
+			return
+		}
+
+		// treat empty and nil slices as equal
+		if a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+
+		d.diffLeaf(ctx, a, b, immutable)
+	case reflect.Map:
+		d.diffMap(ctx, a, b, immutable)
+	default:
+		d.diffLeaf(ctx, a, b, immutable)
+	}
+}
+
+func (d *differ) diffLeaf(ctx diffContext, a, b reflect.Value, immutable bool) {
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return
+	}
+
+	d.add(&Change{
+		Type:      ChangeModified,
+		Component: ctx.component,
+		Identity:  ctx.identity,
+		Field:     ctx.path,
+		Old:       a.Interface(),
+		New:       b.Interface(),
+		Immutable: immutable,
+	})
+}
+
+// addOrRemove records a change where one of the given values is a nil pointer
+func (d *differ) addOrRemove(ctx diffContext, a, b reflect.Value, immutable bool) {
+	c := &Change{
+		Component: ctx.component,
+		Identity:  ctx.identity,
+		Field:     ctx.path,
+		Immutable: immutable,
+	}
+
+	switch {
+	// nested values such as Firmware being set or unset are field changes
+	case ctx.path != "":
+		c.Type = ChangeModified
+		c.Old = a.Interface()
+		c.New = b.Interface()
+	case a.IsNil():
+		c.Type = ChangeAdded
+		c.New = b.Interface()
+	default:
+		c.Type = ChangeRemoved
+		c.Old = a.Interface()
+	}
+
+	d.add(c)
+}
+
+func (d *differ) diffMap(ctx diffContext, a, b reflect.Value, immutable bool) {
+	keys := map[string]reflect.Value{}
+
+	for _, k := range a.MapKeys() {
+		keys[fmt.Sprint(k.Interface())] = k
+	}
+
+	for _, k := range b.MapKeys() {
+		keys[fmt.Sprint(k.Interface())] = k
+	}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		k := keys[name]
+		av, bv := a.MapIndex(k), b.MapIndex(k)
+
+		var old, new interface{}
+		if av.IsValid() {
+			old = av.Interface()
+		}
+
+		if bv.IsValid() {
+			new = bv.Interface()
+		}
+
+		if av.IsValid() && bv.IsValid() && reflect.DeepEqual(old, new) {
+			continue
+		}
+
+		d.add(&Change{
+			Type:      ChangeModified,
+			Component: ctx.component,
+			Identity:  ctx.identity,
+			Field:     ctx.path + "[" + name + "]",
+			Old:       old,
+			New:       new,
+			Immutable: immutable,
+		})
+	}
+}
+
+func (d *differ) diffList(ctx diffContext, a, b reflect.Value, immutable bool) {
+	aKeys, aIndex := keyList(a)
+	bKeys, bIndex := keyList(b)
+
+	// a list of components has its own slug, other lists are fields of the current component
+	component := ctx.path == ""
+
+	elementCtx := func(key string) diffContext {
+		if component {
+			return diffContext{component: ctx.component, identity: ctx.identity + key}
+		}
+
+		return diffContext{component: ctx.component, identity: ctx.identity, path: ctx.path + "[" + key + "]"}
+	}
+
+	for _, key := range aKeys {
+		av := aIndex[key]
+
+		bv, ok := bIndex[key]
+		if !ok {
+			ectx := elementCtx(key)
+			d.add(&Change{
+				Type:      ChangeRemoved,
+				Component: ectx.component,
+				Identity:  ectx.identity,
+				Field:     ectx.path,
+				Old:       av.Interface(),
+				Immutable: immutable,
+			})
+
+			continue
+		}
+
+		d.diffValue(elementCtx(key), av, bv, immutable)
+	}
+
+	for _, key := range bKeys {
+		if _, ok := aIndex[key]; ok {
+			continue
+		}
+
+		ectx := elementCtx(key)
+		d.add(&Change{
+			Type:      ChangeAdded,
+			Component: ectx.component,
+			Identity:  ectx.identity,
+			Field:     ectx.path,
+			New:       bIndex[key].Interface(),
+			Immutable: immutable,
+		})
+	}
+}
+
+// keyList returns the identity keys of the list elements in order, along with a lookup by key
+func keyList(list reflect.Value) (keys []string, index map[string]reflect.Value) {
+	index = make(map[string]reflect.Value, list.Len())
+	seen := map[string]int{}
+
+	for i := 0; i < list.Len(); i++ {
+		v := list.Index(i)
+
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			continue
+		}
+
+		key := identityKey(v, i)
+
+		// disambiguate elements sharing an identity
+		seen[key]++
+		if seen[key] > 1 {
+			key = fmt.Sprintf("%s#%d", key, seen[key])
+		}
+
+		keys = append(keys, key)
+		index[key] = v
+	}
+
+	return keys, index
+}
+
+// identityKey returns the stable identity of a list element, or its position
+// when it has no identifying fields set.
+func identityKey(v reflect.Value, position int) string {
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	for _, id := range diffIdentityFields {
+		f := v.FieldByName(id.field)
+		if !f.IsValid() || f.Kind() != reflect.String {
+			continue
+		}
+
+		if s := strings.TrimSpace(f.String()); s != "" {
+			return id.label + "=" + s
+		}
+	}
+
+	return fmt.Sprintf("index=%d", position)
+}
+
+func isStructList(t reflect.Type) bool {
+	e := t.Elem()
+	for e.Kind() == reflect.Ptr {
+		e = e.Elem()
+	}
+
+	return e.Kind() == reflect.Struct
+}
+
+func parseDiffTag(tag string) (ignore, immutable bool) {
+	for _, opt := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(opt) {
+		case diffTagIgnore, "-":
+			ignore = true
+		case diffTagImmutable:
+			immutable = true
+		}
+	}
+
+	return ignore, immutable
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"testing"
+)
+
+func diffTestDevice() *Device {
+	d := NewDevice()
+	d.Serial = "ABC123"
+	d.BIOS.Firmware = &Firmware{Installed: "2.10.2"}
+	d.BIOS.CapacityBytes = 33554432
+	d.CPUs = []*CPU{
+		{Common: Common{Serial: "cpu-0"}, Slot: "CPU.Socket.1", Cores: 32},
+		{Common: Common{Serial: "cpu-1"}, Slot: "CPU.Socket.2", Cores: 32},
+	}
+	d.Memory = []*Memory{
+		{Slot: "DIMM.A1", SizeBytes: 34359738368},
+		{Slot: "DIMM.A2"},
+	}
+	d.NICs = []*NIC{
+		{
+			Common: Common{Serial: "nic-0"},
+			NICPorts: []*NICPort{
+				{ID: "1", MacAddress: "b8:59:9f:00:00:01"},
+				{ID: "2", MacAddress: "b8:59:9f:00:00:02"},
+			},
+		},
+	}
+
+	return &d
+}
+
+func TestDiff(t *testing.T) {
+	before := diffTestDevice()
+
+	t.Run("identical", func(t *testing.T) {
+		cs := Diff(before, diffTestDevice())
+		if !cs.Empty() {
+			t.Errorf("expected no changes, got: %v", cs.Changes)
+		}
+	})
+
+	t.Run("reordered components match by identity", func(t *testing.T) {
+		after := diffTestDevice()
+		after.CPUs[0], after.CPUs[1] = after.CPUs[1], after.CPUs[0]
+
+		cs := Diff(before, after)
+		if !cs.Empty() {
+			t.Errorf("expected no changes, got: %v", cs.Changes)
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		after := diffTestDevice()
+		after.BIOS.Firmware.Installed = "2.17.1"
+		after.BIOS.CapacityBytes = 67108864
+		after.Memory[1].SizeBytes = 34359738368
+		after.CPUs = after.CPUs[:1]
+		after.NICs[0].NICPorts[1].MacAddress = "b8:59:9f:00:00:03"
+		after.GPUs = append(after.GPUs, &GPU{Common: Common{Serial: "gpu-0"}})
+
+		cs := Diff(before, after)
+
+		expected := map[string]ChangeType{
+			"changed BIOS.Firmware.Installed: 2.10.2 -> 2.17.1":                                     ChangeModified,
+			"changed BIOS.CapacityBytes: 33554432 -> 67108864":                                      ChangeModified,
+			"changed PhysicalMemory[slot=DIMM.A2].SizeBytes: 0 -> 34359738368":                      ChangeModified,
+			"removed CPU[serial=cpu-1]":                                                             ChangeRemoved,
+			"changed NICPort[serial=nic-0/id=2].MacAddress: b8:59:9f:00:00:02 -> b8:59:9f:00:00:03": ChangeModified,
+			"added GPU[serial=gpu-0]":                                                               ChangeAdded,
+		}
+
+		if len(cs.Changes) != len(expected) {
+			t.Fatalf("expected %d changes, got: %v", len(expected), cs.Changes)
+		}
+
+		for _, c := range cs.Changes {
+			typ, ok := expected[c.String()]
+			if !ok {
+				t.Errorf("unexpected change: %s", c)
+				continue
+			}
+
+			if typ != c.Type {
+				t.Errorf("expected change type %s, got: %s", typ, c.Type)
+			}
+		}
+
+		immutable := cs.Immutable()
+		if len(immutable) != 1 || immutable[0].Field != "CapacityBytes" {
+			t.Errorf("expected CapacityBytes to be flagged immutable, got: %v", immutable)
+		}
+	})
+}
+
+func TestParseDiffTag(t *testing.T) {
+	testcases := []struct {
+		tag       string
+		ignore    bool
+		immutable bool
+	}{
+		{"", false, false},
+		{"immutable", false, true},
+		{"ignore", true, false},
+		{"-", true, false},
+		{"ignore, immutable", true, true},
+	}
+
+	for _, tc := range testcases {
+		ignore, immutable := parseDiffTag(tc.tag)
+		if ignore != tc.ignore || immutable != tc.immutable {
+			t.Errorf("%q: expected ignore=%v immutable=%v, got ignore=%v immutable=%v", tc.tag, tc.ignore, tc.immutable, ignore, immutable)
+		}
+	}
+}
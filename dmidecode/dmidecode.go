@@ -0,0 +1,441 @@
+// Package dmidecode populates a common.Device from the SMBIOS tables, read either from the
+// text output of dmidecode or from the raw table dump exposed by the kernel in /sys/firmware/dmi/tables.
+package dmidecode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+var errNoRecords = errors.New("no SMBIOS records found")
+
+// SMBIOS structure types populated on the Device
+const (
+	TypeBIOS         = 0
+	TypeSystem       = 1
+	TypeBaseboard    = 2
+	TypeChassis      = 3
+	TypeProcessor    = 4
+	TypeMemoryDevice = 17
+)
+
+// Record is an SMBIOS structure as presented by dmidecode
+type Record struct {
+	Handle string
+	Type   int
+	Name   string
+	// Fields holds the "Key: Value" attributes of the record
+	Fields map[string]string
+	// Lists holds the attributes listing multiple values, for example the "Characteristics"
+	Lists map[string][]string
+}
+
+func newRecord(handle string, typ int) *Record {
+	return &Record{
+		Handle: handle,
+		Type:   typ,
+		Fields: map[string]string{},
+		Lists:  map[string][]string{},
+	}
+}
+
+// field returns the record attribute with placeholder values treated as empty
+func (r *Record) field(key string) string {
+	v := strings.TrimSpace(r.Fields[key])
+	if common.IsPlaceholder(v) {
+		return ""
+	}
+
+	return v
+}
+
+func (r *Record) hasListItem(key, item string) bool {
+	for _, v := range r.Lists[key] {
+		if strings.EqualFold(v, item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse reads the text output of dmidecode and returns the Device it describes
+func Parse(r io.Reader) (*common.Device, error) {
+	records, err := ParseRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDevice(records)
+}
+
+// ParseRecords reads the text output of dmidecode and returns the records listed
+func ParseRecords(r io.Reader) ([]*Record, error) {
+	records := []*Record{}
+
+	var (
+		current  *Record
+		listKey  string
+		wantName bool
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		// Handle 0x0000, DMI type 0, 26 bytes
+		case strings.HasPrefix(line, "Handle "):
+			rec, err := parseHandle(line)
+			if err != nil {
+				return nil, err
+			}
+
+			current, listKey, wantName = rec, "", true
+			records = append(records, current)
+		case current == nil:
+			continue
+		case strings.TrimSpace(line) == "":
+			current, listKey = nil, ""
+		case wantName:
+			current.Name = strings.TrimSpace(line)
+			wantName = false
+		// list items are indented with two tabs
+		case strings.HasPrefix(line, "\t\t"):
+			if listKey != "" {
+				current.Lists[listKey] = append(current.Lists[listKey], strings.TrimSpace(line))
+			}
+		case strings.HasPrefix(line, "\t"):
+			parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+			key := strings.TrimSpace(parts[0])
+
+			value := ""
+			if len(parts) == 2 {
+				value = strings.TrimSpace(parts[1])
+			}
+
+			if value == "" {
+				listKey = key
+				continue
+			}
+
+			listKey = ""
+			current.Fields[key] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errNoRecords
+	}
+
+	return records, nil
+}
+
+func parseHandle(line string) (*Record, error) {
+	parts := strings.Split(line, ",")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid dmidecode handle line: %q", line)
+	}
+
+	handle := strings.TrimSpace(strings.TrimPrefix(parts[0], "Handle "))
+
+	typ, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(parts[1]), "DMI type")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid dmidecode handle line: %q: %w", line, err)
+	}
+
+	return newRecord(handle, typ), nil
+}
+
+// NewDevice returns a Device populated from the given SMBIOS records
+func NewDevice(records []*Record) (*common.Device, error) {
+	if len(records) == 0 {
+		return nil, errNoRecords
+	}
+
+	d := common.NewDevice()
+
+	for _, r := range records {
+		switch r.Type {
+		case TypeBIOS:
+			setBIOS(&d, r)
+		case TypeSystem:
+			setSystem(&d, r)
+		case TypeBaseboard:
+			setBaseboard(&d, r)
+		case TypeChassis:
+			setChassis(&d, r)
+		case TypeProcessor:
+			addProcessor(&d, r)
+		case TypeMemoryDevice:
+			addMemoryDevice(&d, r)
+		}
+	}
+
+	// boards shipped in white box chassis leave the system manufacturer unset
+	if d.Vendor == "" && d.Mainboard != nil {
+		d.Vendor = d.Mainboard.Vendor
+	}
+
+	return &d, nil
+}
+
+func vendor(r *Record, key string) string {
+	if v := r.field(key); v != "" {
+		return common.FormatVendorName(v)
+	}
+
+	return ""
+}
+
+func firmware(version string) *common.Firmware {
+	if version == "" {
+		return nil
+	}
+
+	fw := common.NewFirmwareObj()
+	fw.Installed = version
+
+	return fw
+}
+
+// metadata returns the non empty record fields mapped to the given metadata keys
+func metadata(r *Record, keys map[string]string) map[string]string {
+	m := map[string]string{}
+
+	for field, key := range keys {
+		if v := r.field(field); v != "" {
+			m[key] = v
+		}
+	}
+
+	return m
+}
+
+func setBIOS(d *common.Device, r *Record) {
+	fw := firmware(r.field("Version"))
+	if fw != nil {
+		if date := r.field("Release Date"); date != "" {
+			fw.Metadata["release_date"] = date
+		}
+	}
+
+	d.BIOS = &common.BIOS{
+		Common: common.Common{
+			Vendor:   vendor(r, "Vendor"),
+			Firmware: fw,
+		},
+		SizeBytes:     parseSize(r.field("Runtime Size")),
+		CapacityBytes: parseSize(r.field("ROM Size")),
+	}
+}
+
+func setSystem(d *common.Device, r *Record) {
+	product := r.field("Product Name")
+
+	d.Vendor = vendor(r, "Manufacturer")
+	d.Model = common.FormatProductName(product)
+	d.ProductName = product
+	d.Serial = r.field("Serial Number")
+	d.Metadata = metadata(r, map[string]string{
+		"UUID":       "uuid",
+		"SKU Number": "sku",
+		"Family":     "family",
+		"Version":    "version",
+	})
+}
+
+func setBaseboard(d *common.Device, r *Record) {
+	product := r.field("Product Name")
+
+	d.Mainboard = &common.Mainboard{
+		Common: common.Common{
+			Vendor:      vendor(r, "Manufacturer"),
+			Model:       product,
+			ProductName: product,
+			Serial:      r.field("Serial Number"),
+			Metadata: metadata(r, map[string]string{
+				"Version":   "version",
+				"Asset Tag": "asset_tag",
+			}),
+		},
+		PhysicalID: r.Handle,
+	}
+}
+
+// chassisTypes maps the SMBIOS chassis types to the values lshw reports
+var chassisTypes = map[string]string{
+	"rack mount chassis":   "rackmount",
+	"main server chassis":  "server",
+	"multi-system chassis": "multi-system",
+	"blade":                "blade",
+	"blade enclosure":      "enclosure",
+	"desktop":              "desktop",
+	"low profile desktop":  "low-profile",
+	"tower":                "tower",
+	"mini tower":           "mini-tower",
+	"sealed-case pc":       "sealed",
+	"mini pc":              "mini",
+	"embedded pc":          "embedded",
+}
+
+func setChassis(d *common.Device, r *Record) {
+	typ := r.field("Type")
+
+	if v, exists := chassisTypes[strings.ToLower(typ)]; exists {
+		d.Chassis = v
+	} else {
+		d.Chassis = strings.ToLower(typ)
+	}
+
+	d.Enclosures = append(d.Enclosures, &common.Enclosure{
+		Common: common.Common{
+			Vendor: vendor(r, "Manufacturer"),
+			Serial: r.field("Serial Number"),
+			Metadata: metadata(r, map[string]string{
+				"Version":   "version",
+				"Asset Tag": "asset_tag",
+			}),
+		},
+		ID:          r.Handle,
+		ChassisType: typ,
+	})
+}
+
+func addProcessor(d *common.Device, r *Record) {
+	// skip empty sockets
+	if strings.HasPrefix(r.field("Status"), "Unpopulated") || r.field("Version") == "" {
+		return
+	}
+
+	model := r.field("Version")
+
+	cpu := &common.CPU{
+		Common: common.Common{
+			Vendor:      vendor(r, "Manufacturer"),
+			Model:       model,
+			ProductName: model,
+			Serial:      r.field("Serial Number"),
+			Metadata: metadata(r, map[string]string{
+				"Family":      "family",
+				"ID":          "id",
+				"Part Number": "part_number",
+			}),
+		},
+		ID:           r.Handle,
+		Slot:         r.field("Socket Designation"),
+		ClockSpeedHz: parseSpeed(r.field("Current Speed")),
+		Cores:        atoi(r.field("Core Count")),
+		Threads:      atoi(r.field("Thread Count")),
+	}
+
+	switch cpu.Vendor {
+	case common.VendorIntel, common.VendorAMD:
+		if r.hasListItem("Characteristics", "64-bit capable") {
+			cpu.Architecture = "x86_64"
+		}
+	}
+
+	d.CPUs = append(d.CPUs, cpu)
+}
+
+func addMemoryDevice(d *common.Device, r *Record) {
+	size := parseSize(r.field("Size"))
+
+	// skip empty slots
+	if size == 0 {
+		return
+	}
+
+	partNumber := r.field("Part Number")
+
+	speed := parseSpeed(r.field("Configured Memory Speed"))
+	if speed == 0 {
+		speed = parseSpeed(r.field("Speed"))
+	}
+
+	d.Memory = append(d.Memory, &common.Memory{
+		Common: common.Common{
+			Vendor:      vendor(r, "Manufacturer"),
+			Model:       partNumber,
+			ProductName: partNumber,
+			Serial:      r.field("Serial Number"),
+			Metadata: metadata(r, map[string]string{
+				"Bank Locator": "bank_locator",
+				"Rank":         "rank",
+			}),
+		},
+		ID:           r.Handle,
+		Slot:         r.field("Locator"),
+		Type:         r.field("Type"),
+		FormFactor:   r.field("Form Factor"),
+		SizeBytes:    size,
+		PartNumber:   partNumber,
+		ClockSpeedHz: speed,
+	})
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+var sizeUnits = map[string]int64{
+	"bytes": 1,
+	"kb":    1 << 10,
+	"mb":    1 << 20,
+	"gb":    1 << 30,
+	"tb":    1 << 40,
+}
+
+// parseSize returns the number of bytes for the given size, for example "32 MB" or "64 kB".
+func parseSize(s string) int64 {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0
+	}
+
+	unit, exists := sizeUnits[strings.ToLower(fields[1])]
+	if !exists {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return n * unit
+}
+
+// parseSpeed returns the frequency in Hz for the given speed, for example "2100 MHz" or "3200 MT/s".
+func parseSpeed(s string) int64 {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	switch strings.ToLower(fields[1]) {
+	case "mhz", "mt/s":
+		return n * 1000000
+	case "ghz", "gt/s":
+		return n * 1000000000
+	default:
+		return 0
+	}
+}
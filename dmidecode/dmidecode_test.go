@@ -0,0 +1,150 @@
+package dmidecode
+
+import (
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+)
+
+func TestParse(t *testing.T) {
+	f, err := os.Open("testdata/dell_r640.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	d, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Vendor != common.VendorDell || d.Model != "r640" || d.Serial != "9DZ2K33" || d.Chassis != "rackmount" {
+		t.Errorf("unexpected device attributes: vendor=%q model=%q serial=%q chassis=%q", d.Vendor, d.Model, d.Serial, d.Chassis)
+	}
+
+	if _, exists := d.Metadata["version"]; exists {
+		t.Error("expected placeholder system version to be dropped")
+	}
+
+	if d.BIOS.Firmware.Installed != "2.17.1" || d.BIOS.CapacityBytes != 32<<20 || d.BIOS.SizeBytes != 64<<10 {
+		t.Errorf("unexpected BIOS: %+v %+v", d.BIOS, d.BIOS.Firmware)
+	}
+
+	if d.Mainboard.Model != "0H28RR" || d.Mainboard.Metadata["version"] != "A06" {
+		t.Errorf("unexpected mainboard: %+v", d.Mainboard)
+	}
+
+	if len(d.CPUs) != 1 {
+		t.Fatalf("expected 1 CPU, got: %d", len(d.CPUs))
+	}
+
+	cpu := d.CPUs[0]
+	if cpu.Vendor != common.VendorIntel || cpu.Slot != "CPU1" || cpu.Cores != 14 || cpu.Threads != 28 ||
+		cpu.ClockSpeedHz != 2600000000 || cpu.Architecture != "x86_64" || cpu.Serial != "" {
+		t.Errorf("unexpected CPU: %+v", cpu)
+	}
+
+	if len(d.Memory) != 1 {
+		t.Fatalf("expected 1 memory module, got: %d", len(d.Memory))
+	}
+
+	mem := d.Memory[0]
+	if mem.SizeBytes != 32<<30 || mem.Slot != "A1" || mem.Type != "DDR4" || mem.FormFactor != "DIMM" ||
+		mem.ClockSpeedHz != 2666000000 || mem.PartNumber != "HMA84GR7AFR4N-VK" {
+		t.Errorf("unexpected memory module: %+v", mem)
+	}
+
+	if len(d.Enclosures) != 1 || d.Enclosures[0].ChassisType != "Rack Mount Chassis" {
+		t.Errorf("unexpected enclosures: %+v", d.Enclosures)
+	}
+}
+
+// smbiosStructure returns an SMBIOS structure with the given formatted area and strings
+func smbiosStructure(typ byte, handle uint16, formatted []byte, strs ...string) []byte {
+	b := append([]byte{typ, byte(4 + len(formatted)), byte(handle), byte(handle >> 8)}, formatted...)
+
+	if len(strs) == 0 {
+		return append(b, 0, 0)
+	}
+
+	for _, s := range strs {
+		b = append(append(b, s...), 0)
+	}
+
+	return append(b, 0)
+}
+
+func TestParseSMBIOS(t *testing.T) {
+	table := []byte{}
+
+	// BIOS: vendor, version, segment 0xF000, release date, ROM size 0xFF with 16MB extended size
+	bios := make([]byte, 0x1A-4)
+	bios[0x04-4], bios[0x05-4], bios[0x08-4] = 1, 2, 3
+	bios[0x06-4], bios[0x07-4] = 0x00, 0xF0
+	bios[0x09-4] = 0xFF
+	bios[0x18-4] = 16
+	table = append(table, smbiosStructure(0, 0x0000, bios, "American Megatrends Inc.", "L2.07B", "03/22/2022")...)
+
+	// System: placeholder manufacturer and product
+	system := make([]byte, 0x1B-4)
+	system[0x04-4], system[0x05-4], system[0x07-4] = 1, 1, 1
+	table = append(table, smbiosStructure(1, 0x0001, system, common.SystemManufacturerUndefined)...)
+
+	// Base board
+	board := make([]byte, 0x09-4)
+	board[0x04-4], board[0x05-4], board[0x07-4] = 1, 2, 3
+	table = append(table, smbiosStructure(2, 0x0002, board, "ASRockRack", "E3C246D4I-2T", "196937920000123")...)
+
+	// Processor: populated, 8 cores, 16 threads, 64-bit capable
+	cpu := make([]byte, 0x30-4)
+	cpu[0x04-4], cpu[0x07-4], cpu[0x10-4] = 1, 2, 3
+	cpu[0x16-4], cpu[0x17-4] = 0x48, 0x0D // 3400 MHz
+	cpu[0x18-4] = 0x41
+	cpu[0x23-4], cpu[0x25-4] = 8, 16
+	cpu[0x26-4] = 0x04
+	table = append(table, smbiosStructure(4, 0x0035, cpu, "CPU1", "Intel(R) Corporation", "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz")...)
+
+	// Memory device: 16384 MB SODIMM DDR4 2666 MT/s
+	mem := make([]byte, 0x22-4)
+	mem[0x0C-4], mem[0x0D-4] = 0x00, 0x40
+	mem[0x0E-4] = 0x0D
+	mem[0x10-4], mem[0x12-4] = 1, 0x1A
+	mem[0x15-4], mem[0x16-4] = 0x6A, 0x0A
+	mem[0x17-4], mem[0x18-4], mem[0x1A-4] = 2, 3, 4
+	table = append(table, smbiosStructure(17, 0x0040, mem, "DIMM_A1", "Micron Technology", "2107A1B2", "MTA18ASF2G72HZ-2G6E1")...)
+
+	// End of table
+	table = append(table, smbiosStructure(127, 0xFFFF, nil)...)
+
+	d, err := ParseSMBIOS(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Vendor != common.VendorAsrockrack || d.ProductName != "" {
+		t.Errorf("expected vendor from the mainboard and no product name, got: %q %q", d.Vendor, d.ProductName)
+	}
+
+	if d.BIOS.Vendor != common.VendorAmericanMegatrends || d.BIOS.Firmware.Installed != "L2.07B" ||
+		d.BIOS.CapacityBytes != 16<<20 || d.BIOS.SizeBytes != 64<<10 {
+		t.Errorf("unexpected BIOS: %+v", d.BIOS)
+	}
+
+	if len(d.CPUs) != 1 || d.CPUs[0].Cores != 8 || d.CPUs[0].Threads != 16 || d.CPUs[0].ClockSpeedHz != 3400000000 ||
+		d.CPUs[0].Vendor != common.VendorIntel || d.CPUs[0].Architecture != "x86_64" {
+		t.Errorf("unexpected CPUs: %+v", d.CPUs)
+	}
+
+	if len(d.Memory) != 1 || d.Memory[0].SizeBytes != 16<<30 || d.Memory[0].FormFactor != "SODIMM" ||
+		d.Memory[0].Type != "DDR4" || d.Memory[0].ClockSpeedHz != 2666000000 || d.Memory[0].Vendor != common.VendorMicron {
+		t.Errorf("unexpected memory: %+v", d.Memory)
+	}
+}
+
+func TestDecodeSMBIOSTruncated(t *testing.T) {
+	if _, err := DecodeSMBIOS([]byte{0, 26, 0}); err == nil {
+		t.Error("expected error decoding a truncated table")
+	}
+}
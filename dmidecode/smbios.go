@@ -0,0 +1,370 @@
+package dmidecode
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// SysfsSMBIOSTable is the path the kernel exposes the raw SMBIOS structure table at
+const SysfsSMBIOSTable = "/sys/firmware/dmi/tables/DMI"
+
+// typeEndOfTable marks the last structure in the table
+const typeEndOfTable = 127
+
+var errTruncatedTable = errors.New("truncated SMBIOS table")
+
+// ReadSMBIOS reads the raw SMBIOS structure table at the given path and returns the Device it describes,
+// SysfsSMBIOSTable is read when the path is empty.
+func ReadSMBIOS(path string) (*common.Device, error) {
+	if path == "" {
+		path = SysfsSMBIOSTable
+	}
+
+	table, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSMBIOS(table)
+}
+
+// ParseSMBIOS returns the Device described by the given raw SMBIOS structure table
+func ParseSMBIOS(table []byte) (*common.Device, error) {
+	records, err := DecodeSMBIOS(table)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDevice(records)
+}
+
+// structure is a raw SMBIOS structure
+type structure struct {
+	typ       int
+	handle    uint16
+	formatted []byte
+	strings   []string
+}
+
+// str returns the string referenced by the byte at the given offset of the formatted area
+func (s *structure) str(offset int) string {
+	idx := int(s.byte(offset))
+	if idx == 0 || idx > len(s.strings) {
+		return ""
+	}
+
+	return strings.TrimSpace(s.strings[idx-1])
+}
+
+func (s *structure) byte(offset int) byte {
+	if offset >= len(s.formatted) {
+		return 0
+	}
+
+	return s.formatted[offset]
+}
+
+func (s *structure) word(offset int) uint16 {
+	if offset+2 > len(s.formatted) {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint16(s.formatted[offset:])
+}
+
+func (s *structure) dword(offset int) uint32 {
+	if offset+4 > len(s.formatted) {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint32(s.formatted[offset:])
+}
+
+// DecodeSMBIOS decodes the raw SMBIOS structure table into records using the field names dmidecode prints,
+// only the structure types populated on the Device are decoded.
+func DecodeSMBIOS(table []byte) ([]*Record, error) {
+	records := []*Record{}
+
+	for len(table) > 0 {
+		s, n, err := nextStructure(table)
+		if err != nil {
+			return nil, err
+		}
+
+		table = table[n:]
+
+		if s.typ == typeEndOfTable {
+			break
+		}
+
+		if r := decodeStructure(s); r != nil {
+			records = append(records, r)
+		}
+	}
+
+	if len(records) == 0 {
+		return nil, errNoRecords
+	}
+
+	return records, nil
+}
+
+// nextStructure returns the structure at the start of the table and its size
+func nextStructure(table []byte) (*structure, int, error) {
+	if len(table) < 4 {
+		return nil, 0, errTruncatedTable
+	}
+
+	length := int(table[1])
+	if length < 4 || length > len(table) {
+		return nil, 0, errTruncatedTable
+	}
+
+	s := &structure{
+		typ:       int(table[0]),
+		handle:    binary.LittleEndian.Uint16(table[2:4]),
+		formatted: table[:length],
+	}
+
+	// the formatted area is followed by a set of null terminated strings, ending with a double null
+	pos := length
+	for {
+		if pos+1 >= len(table) {
+			return nil, 0, errTruncatedTable
+		}
+
+		if table[pos] == 0 && table[pos+1] == 0 {
+			return s, pos + 2, nil
+		}
+
+		end := pos
+		for end < len(table) && table[end] != 0 {
+			end++
+		}
+
+		if end >= len(table) {
+			return nil, 0, errTruncatedTable
+		}
+
+		s.strings = append(s.strings, string(table[pos:end]))
+
+		if end+1 < len(table) && table[end+1] == 0 {
+			return s, end + 2, nil
+		}
+
+		pos = end + 1
+	}
+}
+
+func decodeStructure(s *structure) *Record {
+	r := newRecord(fmt.Sprintf("0x%04X", s.handle), s.typ)
+
+	set := func(key, value string) {
+		if value != "" {
+			r.Fields[key] = value
+		}
+	}
+
+	switch s.typ {
+	case TypeBIOS:
+		r.Name = "BIOS Information"
+		set("Vendor", s.str(0x04))
+		set("Version", s.str(0x05))
+		set("Release Date", s.str(0x08))
+
+		if segment := s.word(0x06); segment != 0 {
+			set("Runtime Size", fmt.Sprintf("%d bytes", (0x10000-int64(segment))*16))
+		}
+
+		set("ROM Size", biosROMSize(s))
+	case TypeSystem:
+		r.Name = "System Information"
+		set("Manufacturer", s.str(0x04))
+		set("Product Name", s.str(0x05))
+		set("Version", s.str(0x06))
+		set("Serial Number", s.str(0x07))
+		set("UUID", systemUUID(s))
+		set("SKU Number", s.str(0x19))
+		set("Family", s.str(0x1A))
+	case TypeBaseboard:
+		r.Name = "Base Board Information"
+		set("Manufacturer", s.str(0x04))
+		set("Product Name", s.str(0x05))
+		set("Version", s.str(0x06))
+		set("Serial Number", s.str(0x07))
+		set("Asset Tag", s.str(0x08))
+	case TypeChassis:
+		r.Name = "Chassis Information"
+		set("Manufacturer", s.str(0x04))
+		set("Type", chassisTypeNames[s.byte(0x05)&0x7F])
+		set("Version", s.str(0x06))
+		set("Serial Number", s.str(0x07))
+		set("Asset Tag", s.str(0x08))
+	case TypeProcessor:
+		r.Name = "Processor Information"
+		decodeProcessor(s, r, set)
+	case TypeMemoryDevice:
+		r.Name = "Memory Device"
+		decodeMemoryDevice(s, r, set)
+	default:
+		return nil
+	}
+
+	return r
+}
+
+func biosROMSize(s *structure) string {
+	size := s.byte(0x09)
+
+	// an extended ROM size is specified when the size byte is 0xFF, SMBIOS 3.1+
+	if size == 0xFF && len(s.formatted) >= 0x1A {
+		ext := s.word(0x18)
+		if ext>>14 == 1 {
+			return fmt.Sprintf("%d GB", ext&0x3FFF)
+		}
+
+		return fmt.Sprintf("%d MB", ext&0x3FFF)
+	}
+
+	return fmt.Sprintf("%d kB", (int(size)+1)*64)
+}
+
+func systemUUID(s *structure) string {
+	if len(s.formatted) < 0x18 {
+		return ""
+	}
+
+	u := s.formatted[0x08:0x18]
+
+	// the first three fields are little endian since SMBIOS 2.6
+	return fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%02x%02x-%02x%02x%02x%02x%02x%02x",
+		u[3], u[2], u[1], u[0], u[5], u[4], u[7], u[6], u[8], u[9], u[10], u[11], u[12], u[13], u[14], u[15])
+}
+
+func decodeProcessor(s *structure, r *Record, set func(key, value string)) {
+	set("Socket Designation", s.str(0x04))
+	set("Manufacturer", s.str(0x07))
+	set("Version", s.str(0x10))
+
+	if speed := s.word(0x16); speed != 0 {
+		set("Current Speed", fmt.Sprintf("%d MHz", speed))
+	}
+
+	if speed := s.word(0x14); speed != 0 {
+		set("Max Speed", fmt.Sprintf("%d MHz", speed))
+	}
+
+	// bit 6 is set when the socket is populated
+	if s.byte(0x18)&0x40 == 0 {
+		set("Status", "Unpopulated")
+	} else {
+		set("Status", "Populated")
+	}
+
+	set("Serial Number", s.str(0x20))
+	set("Part Number", s.str(0x22))
+
+	cores, threads := int(s.byte(0x23)), int(s.byte(0x25))
+
+	// counts above 255 are held in the SMBIOS 3.0 fields
+	if cores == 0xFF {
+		cores = int(s.word(0x2A))
+	}
+
+	if threads == 0xFF {
+		threads = int(s.word(0x2E))
+	}
+
+	if cores != 0 {
+		set("Core Count", fmt.Sprint(cores))
+	}
+
+	if threads != 0 {
+		set("Thread Count", fmt.Sprint(threads))
+	}
+
+	if s.word(0x26)&0x04 != 0 {
+		r.Lists["Characteristics"] = []string{"64-bit capable"}
+	}
+}
+
+func decodeMemoryDevice(s *structure, r *Record, set func(key, value string)) {
+	switch size := s.word(0x0C); {
+	case size == 0:
+		set("Size", "No Module Installed")
+	case size == 0xFFFF:
+		// unknown
+	case size == 0x7FFF:
+		set("Size", fmt.Sprintf("%d MB", s.dword(0x1C)&0x7FFFFFFF))
+	case size&0x8000 != 0:
+		set("Size", fmt.Sprintf("%d kB", size&0x7FFF))
+	default:
+		set("Size", fmt.Sprintf("%d MB", size))
+	}
+
+	set("Form Factor", memoryFormFactors[s.byte(0x0E)])
+	set("Locator", s.str(0x10))
+	set("Bank Locator", s.str(0x11))
+	set("Type", memoryTypeNames[s.byte(0x12)])
+
+	if speed := s.word(0x15); speed != 0 {
+		set("Speed", fmt.Sprintf("%d MT/s", speed))
+	}
+
+	set("Manufacturer", s.str(0x17))
+	set("Serial Number", s.str(0x18))
+	set("Part Number", s.str(0x1A))
+
+	if speed := s.word(0x20); speed != 0 {
+		set("Configured Memory Speed", fmt.Sprintf("%d MT/s", speed))
+	}
+}
+
+var chassisTypeNames = map[byte]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x03: "Desktop",
+	0x04: "Low Profile Desktop",
+	0x06: "Mini Tower",
+	0x07: "Tower",
+	0x11: "Main Server Chassis",
+	0x17: "Rack Mount Chassis",
+	0x18: "Sealed-case PC",
+	0x19: "Multi-system Chassis",
+	0x1C: "Blade",
+	0x1D: "Blade Enclosure",
+	0x22: "Embedded PC",
+	0x23: "Mini PC",
+}
+
+var memoryFormFactors = map[byte]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x09: "DIMM",
+	0x0C: "RIMM",
+	0x0D: "SODIMM",
+	0x0F: "FB-DIMM",
+	0x10: "Die",
+}
+
+var memoryTypeNames = map[byte]string{
+	0x01: "Other",
+	0x02: "Unknown",
+	0x12: "DDR",
+	0x13: "DDR2",
+	0x18: "DDR3",
+	0x1A: "DDR4",
+	0x1B: "LPDDR",
+	0x1C: "LPDDR2",
+	0x1D: "LPDDR3",
+	0x1E: "LPDDR4",
+	0x20: "HBM",
+	0x21: "HBM2",
+	0x22: "DDR5",
+	0x23: "LPDDR5",
+}
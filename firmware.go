@@ -0,0 +1,216 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var errInvalidFirmwareVersion = errors.New("invalid firmware version")
+
+// FirmwareVersion is a parsed firmware version string that can be ordered.
+//
+// Versions are split into numeric and alphabetic segments at the separators and at the
+// boundaries between digits and letters, which covers the vendor formats seen on the Device:
+//
+//	dotted numeric  - 2.10.2 (Dell), 14.32.1010 (Mellanox)
+//	zero padded     - 01.00.25 (Supermicro), equal to 1.0.25
+//	hexadecimal     - 0x800010a8 (Intel NIC NVM), compared as a single number
+//	alphanumeric    - D3MU001 (drives), split into D, 3, MU, 1
+//
+// Numeric segments are compared as numbers and alphabetic segments case insensitively,
+// a numeric segment orders after an alphabetic segment.
+type FirmwareVersion struct {
+	raw      string
+	segments []versionSegment
+}
+
+type versionSegment struct {
+	numeric bool
+	num     uint64
+	str     string
+}
+
+// ParseFirmwareVersion parses the given firmware version string
+func ParseFirmwareVersion(s string) (*FirmwareVersion, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" || IsPlaceholder(raw) {
+		return nil, fmt.Errorf("%w : %q", errInvalidFirmwareVersion, s)
+	}
+
+	v := &FirmwareVersion{raw: raw}
+
+	// Intel NVM versions are the hexadecimal eTrackID
+	if lower := strings.ToLower(raw); strings.HasPrefix(lower, "0x") {
+		n, err := strconv.ParseUint(lower[2:], 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w : %q", errInvalidFirmwareVersion, s)
+		}
+
+		v.segments = []versionSegment{{numeric: true, num: n}}
+
+		return v, nil
+	}
+
+	// a leading v as in v2.54 is not part of the version
+	if len(raw) > 1 && (raw[0] == 'v' || raw[0] == 'V') && unicode.IsDigit(rune(raw[1])) {
+		raw = raw[1:]
+	}
+
+	var current []rune
+
+	flush := func() error {
+		if len(current) == 0 {
+			return nil
+		}
+
+		token := string(current)
+		current = current[:0]
+
+		if !unicode.IsDigit(rune(token[0])) {
+			v.segments = append(v.segments, versionSegment{str: strings.ToUpper(token)})
+			return nil
+		}
+
+		n, err := strconv.ParseUint(token, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w : %q", errInvalidFirmwareVersion, s)
+		}
+
+		v.segments = append(v.segments, versionSegment{numeric: true, num: n})
+
+		return nil
+	}
+
+	for _, r := range raw {
+		switch {
+		case unicode.IsDigit(r), unicode.IsLetter(r):
+			// a segment ends where digits and letters meet
+			if len(current) > 0 && unicode.IsDigit(current[len(current)-1]) != unicode.IsDigit(r) {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+
+			current = append(current, r)
+		default:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if len(v.segments) == 0 {
+		return nil, fmt.Errorf("%w : %q", errInvalidFirmwareVersion, s)
+	}
+
+	return v, nil
+}
+
+// String returns the version as given to ParseFirmwareVersion
+func (v *FirmwareVersion) String() string {
+	return v.raw
+}
+
+// Compare returns -1, 0 or +1 when the version is lower than, equal to or greater than the given version.
+//
+// Missing trailing segments are treated as zero so 1.0 equals 1.0.0. A version followed by a
+// pre-release segment, rc, alpha, beta or pre, orders before the version itself as 1.0-rc1 does
+// before 1.0, while a version followed by any other alphabetic segment is a later release as
+// 3.4a is of 3.4.
+func (v *FirmwareVersion) Compare(o *FirmwareVersion) int {
+	for i := 0; i < len(v.segments) || i < len(o.segments); i++ {
+		switch {
+		case i >= len(v.segments):
+			return -remainder(o.segments[i:])
+		case i >= len(o.segments):
+			return remainder(v.segments[i:])
+		}
+
+		if c := v.segments[i].compare(o.segments[i]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func (s versionSegment) compare(o versionSegment) int {
+	switch {
+	case s.numeric && o.numeric:
+		switch {
+		case s.num < o.num:
+			return -1
+		case s.num > o.num:
+			return 1
+		default:
+			return 0
+		}
+	case s.numeric:
+		return 1
+	case o.numeric:
+		return -1
+	default:
+		return strings.Compare(s.str, o.str)
+	}
+}
+
+// preReleaseSegments are the alphabetic segments marking a pre-release of a version
+var preReleaseSegments = map[string]bool{"RC": true, "ALPHA": true, "BETA": true, "PRE": true}
+
+// remainder returns the ordering of a version with the given trailing segments
+// against the same version without them.
+func remainder(segments []versionSegment) int {
+	for _, s := range segments {
+		if !s.numeric {
+			if preReleaseSegments[s.str] {
+				return -1
+			}
+
+			return 1
+		}
+
+		if s.num != 0 {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// CompareFirmwareVersions parses and compares the two firmware versions, see FirmwareVersion.Compare
+func CompareFirmwareVersions(a, b string) (int, error) {
+	va, err := ParseFirmwareVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	vb, err := ParseFirmwareVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return va.Compare(vb), nil
+}
+
+// UpdateAvailable returns true when the Available firmware version is greater than the Installed version.
+//
+// False is returned when either version is unset or cannot be parsed.
+func (f *Firmware) UpdateAvailable() bool {
+	if f == nil || f.Installed == "" || f.Available == "" {
+		return false
+	}
+
+	c, err := CompareFirmwareVersions(f.Available, f.Installed)
+	if err != nil {
+		return false
+	}
+
+	return c > 0
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"time"
+)
+
+// Firmware Metadata keys maintained by the firmware history
+const (
+	// FirmwareMetadataInstalledAt is when the firmware version was first seen installed, in RFC 3339 format
+	FirmwareMetadataInstalledAt = "installed_at"
+	// FirmwareMetadataReplacedAt is when the firmware version was replaced, set on the Previous entries
+	FirmwareMetadataReplacedAt = "replaced_at"
+	// FirmwareMetadataSource identifies what reported the firmware version, for example "redfish" or "lshw"
+	FirmwareMetadataSource = "source"
+)
+
+// RecordInstalled sets the given version as installed and pushes the previously installed version onto Previous,
+// returns false when the version is already installed or the Firmware is nil.
+//
+// The source and time are stored in the Metadata of the installed firmware, the previous entry is
+// stamped with the time it was replaced at. Previous is ordered oldest first.
+func (f *Firmware) RecordInstalled(version, source string, at time.Time) bool {
+	if f == nil || version == "" || version == f.Installed {
+		return false
+	}
+
+	if f.Metadata == nil {
+		f.Metadata = map[string]string{}
+	}
+
+	timestamp := at.UTC().Format(time.RFC3339)
+
+	if f.Installed != "" {
+		prev := &Firmware{
+			Installed:  f.Installed,
+			SoftwareID: f.SoftwareID,
+			Metadata:   copyMetadata(f.Metadata),
+		}
+
+		prev.Metadata[FirmwareMetadataReplacedAt] = timestamp
+		f.Previous = append(f.Previous, prev)
+	}
+
+	f.Installed = version
+	f.Metadata[FirmwareMetadataInstalledAt] = timestamp
+
+	if source != "" {
+		f.Metadata[FirmwareMetadataSource] = source
+	} else {
+		delete(f.Metadata, FirmwareMetadataSource)
+	}
+
+	return true
+}
+
+// RecordFirmware records the given version as installed on the referenced component,
+// see Firmware.RecordInstalled.
+func (r *ComponentRef) RecordFirmware(version, source string, at time.Time) bool {
+	fw := r.Firmware()
+	if fw == nil {
+		fw = NewFirmwareObj()
+		r.SetFirmware(fw)
+	}
+
+	return fw.RecordInstalled(version, source, at)
+}
+
+// MergeFirmwareHistory carries the firmware history of the previous Device inventory forward
+// onto the current inventory replacing it.
+//
+// Components are matched by their type and identity as in Diff. When the installed version
+// changed, the previous version is added to the history with the time given, and the
+// current version recorded as installed from the source given. The installed time and source
+// of unchanged versions are carried over. Components with a history already set on the
+// current inventory are left as is.
+func MergeFirmwareHistory(previous, current *Device, source string, at time.Time) {
+	if previous == nil || current == nil {
+		return
+	}
+
+	old := map[string]*ComponentRef{}
+	for _, ref := range previous.Components() {
+		old[ref.Slug+"|"+ref.Identity] = ref
+	}
+
+	for _, ref := range current.Components() {
+		fw := ref.Firmware()
+		if fw == nil || fw.Installed == "" || len(fw.Previous) > 0 {
+			continue
+		}
+
+		prevRef, exists := old[ref.Slug+"|"+ref.Identity]
+		if !exists || prevRef.Firmware() == nil {
+			continue
+		}
+
+		merged := copyFirmware(prevRef.Firmware())
+		merged.RecordInstalled(fw.Installed, source, at)
+
+		fw.Previous = merged.Previous
+
+		if fw.Metadata == nil {
+			fw.Metadata = map[string]string{}
+		}
+
+		for _, key := range []string{FirmwareMetadataInstalledAt, FirmwareMetadataSource} {
+			if v, exists := merged.Metadata[key]; exists && fw.Metadata[key] == "" {
+				fw.Metadata[key] = v
+			}
+		}
+	}
+}
+
+func copyFirmware(f *Firmware) *Firmware {
+	c := &Firmware{
+		Installed:  f.Installed,
+		Available:  f.Available,
+		SoftwareID: f.SoftwareID,
+		Metadata:   copyMetadata(f.Metadata),
+	}
+
+	for _, p := range f.Previous {
+		if p != nil {
+			c.Previous = append(c.Previous, copyFirmware(p))
+		}
+	}
+
+	return c
+}
+
+func copyMetadata(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+
+	return c
+}
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFirmwareRecordInstalled(t *testing.T) {
+	t1 := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+
+	fw := &Firmware{Installed: "2.10.2", SoftwareID: "159"}
+
+	if fw.RecordInstalled("2.10.2", "redfish", t1) {
+		t.Error("expected no change recording the installed version")
+	}
+
+	if !fw.RecordInstalled("2.17.1", "redfish", t1) {
+		t.Fatal("expected the version to be recorded")
+	}
+
+	if !fw.RecordInstalled("2.18.0", "", t2) {
+		t.Fatal("expected the version to be recorded")
+	}
+
+	if fw.Installed != "2.18.0" || fw.Metadata[FirmwareMetadataInstalledAt] != "2023-01-03T03:04:05Z" {
+		t.Errorf("unexpected firmware: %+v", fw)
+	}
+
+	if _, exists := fw.Metadata[FirmwareMetadataSource]; exists {
+		t.Error("expected the source of the replaced version to be cleared")
+	}
+
+	if len(fw.Previous) != 2 {
+		t.Fatalf("expected 2 previous versions, got %d", len(fw.Previous))
+	}
+
+	first, second := fw.Previous[0], fw.Previous[1]
+	if first.Installed != "2.10.2" || first.SoftwareID != "159" || first.Metadata[FirmwareMetadataReplacedAt] != "2023-01-02T03:04:05Z" {
+		t.Errorf("unexpected previous firmware: %+v", first)
+	}
+
+	if second.Installed != "2.17.1" || second.Metadata[FirmwareMetadataSource] != "redfish" ||
+		second.Metadata[FirmwareMetadataInstalledAt] != "2023-01-02T03:04:05Z" || second.Metadata[FirmwareMetadataReplacedAt] != "2023-01-03T03:04:05Z" {
+		t.Errorf("unexpected previous firmware: %+v", second)
+	}
+}
+
+func TestFirmwareRecordInstalledNil(t *testing.T) {
+	var fw *Firmware
+	if fw.RecordInstalled("2.17.1", "redfish", time.Now()) {
+		t.Error("expected no change recording on a nil Firmware")
+	}
+}
+
+func TestComponentRefRecordFirmware(t *testing.T) {
+	d := NewDevice()
+	d.Drives = append(d.Drives, &Drive{Common: Common{Serial: "abc"}})
+
+	for _, ref := range d.Components() {
+		if ref.Slug == SlugDrive && !ref.RecordFirmware("D3MU001", "smartctl", time.Now()) {
+			t.Error("expected the firmware to be recorded")
+		}
+	}
+
+	if d.Drives[0].Firmware == nil || d.Drives[0].Firmware.Installed != "D3MU001" {
+		t.Errorf("unexpected drive firmware: %+v", d.Drives[0].Firmware)
+	}
+}
+
+func TestMergeFirmwareHistory(t *testing.T) {
+	t1 := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	newDevice := func(bios, bmc, drive string) *Device {
+		d := NewDevice()
+		d.BIOS = &BIOS{Common: Common{Firmware: &Firmware{Installed: bios}}}
+		d.BMC = &BMC{Common: Common{Firmware: &Firmware{Installed: bmc}}}
+		d.Drives = append(d.Drives, &Drive{Common: Common{Serial: "drive-1", Firmware: &Firmware{Installed: drive}}})
+		d.NICs = append(d.NICs, &NIC{ID: "NIC.Slot.1", Common: Common{Firmware: &Firmware{Installed: "14.31.2006"}}})
+
+		return &d
+	}
+
+	old := newDevice("2.10.2", "5.00.00.00", "D3MU001")
+	old.BIOS.Firmware.RecordInstalled("2.12.0", "redfish", t1)
+
+	current := newDevice("2.17.1", "5.00.00.00", "D3MU001")
+	current.NICs[0].Firmware.Installed = "14.32.1010"
+
+	// the drive was replaced, its history does not carry over
+	current.Drives[0].Serial = "drive-2"
+
+	MergeFirmwareHistory(old, current, "inventory", t2)
+
+	bios := current.BIOS.Firmware
+	if len(bios.Previous) != 2 || bios.Previous[0].Installed != "2.10.2" || bios.Previous[1].Installed != "2.12.0" {
+		t.Fatalf("unexpected BIOS history: %+v", bios.Previous)
+	}
+
+	if bios.Metadata[FirmwareMetadataSource] != "inventory" || bios.Metadata[FirmwareMetadataInstalledAt] != t2.Format(time.RFC3339) {
+		t.Errorf("unexpected BIOS metadata: %+v", bios.Metadata)
+	}
+
+	// the old inventory is not modified
+	if len(old.BIOS.Firmware.Previous) != 1 {
+		t.Errorf("expected the previous inventory to be unchanged, got %+v", old.BIOS.Firmware.Previous)
+	}
+
+	if bmc := current.BMC.Firmware; len(bmc.Previous) != 0 {
+		t.Errorf("expected no BMC history, got %+v", bmc.Previous)
+	}
+
+	if nic := current.NICs[0].Firmware; len(nic.Previous) != 1 || nic.Previous[0].Installed != "14.31.2006" {
+		t.Errorf("unexpected NIC history: %+v", nic.Previous)
+	}
+
+	if drive := current.Drives[0].Firmware; len(drive.Previous) != 0 {
+		t.Errorf("expected no history for a replaced drive, got %+v", drive.Previous)
+	}
+
+	// merging the same inventory again keeps the history as is
+	next := newDevice("2.17.1", "5.00.00.00", "D3MU001")
+	MergeFirmwareHistory(current, next, "inventory", t2.Add(time.Hour))
+
+	if len(next.BIOS.Firmware.Previous) != 2 || next.BIOS.Firmware.Metadata[FirmwareMetadataInstalledAt] != t2.Format(time.RFC3339) {
+		t.Errorf("unexpected BIOS firmware: %+v", next.BIOS.Firmware)
+	}
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompareFirmwareVersions(t *testing.T) {
+	testcases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"2.10.2", "2.9.4", 1},
+		{"2.10.2", "2.17.1", -1},
+		{"01.00.25", "1.0.25", 0},
+		{"01.00.25", "01.01.02", -1},
+		{"0x800010a8", "0x80001112", -1},
+		{"0x800010A8", "0x800010a8", 0},
+		{"14.32.1010", "14.31.2006", 1},
+		{"14.32.1010", "14.32.1010", 0},
+		{"D3MU001", "D3MU002", -1},
+		{"D1MU020", "d1mu020", 0},
+		{"D3MU001", "D3MV001", -1},
+		{"1.0", "1.0.0", 0},
+		{"1.0.1", "1.0", 1},
+		{"1.0-rc1", "1.0", -1},
+		{"2.1beta2", "2.1", -1},
+		{"1.0.pre", "1.0.0", -1},
+		{"3.4a", "3.4", 1},
+		{"3.4a", "3.4b", -1},
+		{"3.4a", "3.5", -1},
+		{"v2.54", "2.54", 0},
+		{"5.00.00.00", "4.40.40.00", 1},
+		{"1.a", "1.1", -1},
+	}
+
+	for _, tc := range testcases {
+		got, err := CompareFirmwareVersions(tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("%s vs %s: %s", tc.a, tc.b, err)
+		}
+
+		if got != tc.expected {
+			t.Errorf("%s vs %s: expected %d, got %d", tc.a, tc.b, tc.expected, got)
+		}
+
+		// the ordering is symmetric
+		if reverse, _ := CompareFirmwareVersions(tc.b, tc.a); reverse != -tc.expected {
+			t.Errorf("%s vs %s: expected %d, got %d", tc.b, tc.a, -tc.expected, reverse)
+		}
+	}
+}
+
+func TestParseFirmwareVersionInvalid(t *testing.T) {
+	for _, s := range []string{"", " ", "Unknown", "Not Specified", "0xzz", "..."} {
+		if _, err := ParseFirmwareVersion(s); !errors.Is(err, errInvalidFirmwareVersion) {
+			t.Errorf("%q: expected errInvalidFirmwareVersion, got %v", s, err)
+		}
+	}
+}
+
+func TestFirmwareUpdateAvailable(t *testing.T) {
+	testcases := []struct {
+		installed string
+		available string
+		expected  bool
+	}{
+		{"2.10.2", "2.17.1", true},
+		{"2.17.1", "2.17.1", false},
+		{"2.17.1", "2.10.2", false},
+		{"D3MU001", "D3MU002", true},
+		{"3.4", "3.4a", true},
+		{"3.4a", "3.4", false},
+		{"", "1.0", false},
+		{"1.0", "", false},
+		{"1.0", "unknown", false},
+	}
+
+	for _, tc := range testcases {
+		fw := &Firmware{Installed: tc.installed, Available: tc.available}
+		if got := fw.UpdateAvailable(); got != tc.expected {
+			t.Errorf("installed %q available %q: expected %v, got %v", tc.installed, tc.available, tc.expected, got)
+		}
+	}
+
+	var fw *Firmware
+	if fw.UpdateAvailable() {
+		t.Error("expected no update available on a nil Firmware")
+	}
+}
@@ -2,6 +2,8 @@ module github.com/bmc-toolbox/common
 
 go 1.17
 
-require golang.org/x/net v0.25.0
-
-require golang.org/x/text v0.15.0 // indirect
+require (
+	golang.org/x/net v0.25.0
+	golang.org/x/text v0.15.0
+	gopkg.in/yaml.v3 v3.0.1
+)
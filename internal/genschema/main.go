@@ -0,0 +1,24 @@
+// Command genschema writes the Device JSON Schema document.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/bmc-toolbox/common"
+)
+
+func main() {
+	output := flag.String("o", "device.schema.json", "file to write the JSON Schema document to")
+	flag.Parse()
+
+	schema, err := common.DeviceJSONSchema()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*output, append(schema, '\n'), 0o600); err != nil {
+		log.Fatal(err)
+	}
+}
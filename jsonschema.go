@@ -0,0 +1,128 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	jsonSchemaID    = "https://github.com/bmc-toolbox/common/device.schema.json"
+)
+
+//go:generate go run ./internal/genschema -o device.schema.json
+
+// DeviceJSONSchema returns the JSON Schema document describing the serialized Device
+// at the current DeviceSchemaVersion.
+func DeviceJSONSchema() ([]byte, error) {
+	g := &schemaGenerator{defs: map[string]interface{}{}}
+
+	root := g.object(reflect.TypeOf(Device{}))
+
+	properties := root["properties"].(map[string]interface{})
+	properties[deviceSchemaVersionKey] = map[string]interface{}{"const": DeviceSchemaVersion}
+
+	root["$schema"] = jsonSchemaDraft
+	root["$id"] = jsonSchemaID
+	root["title"] = "Device"
+	root["$defs"] = g.defs
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+// schema returns the schema for the given type, struct types other than Device are
+// added to the definitions and referenced.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		name := t.Name()
+		if _, exists := g.defs[name]; !exists {
+			// placeholder to break reference cycles
+			g.defs[name] = nil
+			g.defs[name] = g.object(t)
+		}
+
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  []string{"array", "null"},
+			"items": g.schema(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 []string{"object", "null"},
+			"additionalProperties": g.schema(t.Elem()),
+		}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// object returns the schema for a struct type, embedded struct fields are
+// flattened following the encoding/json rules.
+func (g *schemaGenerator) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+
+	g.fields(t, properties)
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+func (g *schemaGenerator) fields(t reflect.Type, properties map[string]interface{}) {
+	embedded := []reflect.Type{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			embedded = append(embedded, f.Type)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		properties[name] = g.schema(f.Type)
+	}
+
+	// fields on the outer struct take precedence over those of embedded structs
+	for _, e := range embedded {
+		inner := map[string]interface{}{}
+		g.fields(e, inner)
+
+		for name, s := range inner {
+			if _, exists := properties[name]; !exists {
+				properties[name] = s
+			}
+		}
+	}
+}
@@ -0,0 +1,659 @@
+// Package lshw imports the JSON output of lshw into a common.Device.
+//
+// The output is expected to be that of `lshw -json`, optionally combined with `-numeric`
+// in which case the PCI vendor and product IDs are populated on the PCI components.
+package lshw
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+var errNoSystemNode = errors.New("lshw output has no system node")
+
+// lshw classes of interest
+const (
+	classSystem    = "system"
+	classBus       = "bus"
+	classMemory    = "memory"
+	classProcessor = "processor"
+	classNetwork   = "network"
+	classStorage   = "storage"
+	classDisk      = "disk"
+	classDisplay   = "display"
+)
+
+// node is an entry in the lshw device tree
+type node struct {
+	ID            string                 `json:"id"`
+	Class         string                 `json:"class"`
+	Claimed       bool                   `json:"claimed"`
+	Disabled      bool                   `json:"disabled"`
+	Description   string                 `json:"description"`
+	Product       string                 `json:"product"`
+	Vendor        string                 `json:"vendor"`
+	PhysID        string                 `json:"physid"`
+	BusInfo       string                 `json:"businfo"`
+	LogicalName   logicalName            `json:"logicalname"`
+	Version       string                 `json:"version"`
+	Serial        string                 `json:"serial"`
+	Slot          string                 `json:"slot"`
+	Units         string                 `json:"units"`
+	Size          number                 `json:"size"`
+	Capacity      number                 `json:"capacity"`
+	Width         number                 `json:"width"`
+	Clock         number                 `json:"clock"`
+	Configuration map[string]interface{} `json:"configuration"`
+	Capabilities  map[string]interface{} `json:"capabilities"`
+	Children      []*node                `json:"children"`
+}
+
+// logicalName is either a single string or a list of strings in the lshw output
+type logicalName []string
+
+func (l *logicalName) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = logicalName{s}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	*l = list
+
+	return nil
+}
+
+func (l logicalName) first() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	return l[0]
+}
+
+// number accepts numeric values encoded as JSON numbers or strings
+type number int64
+
+func (n *number) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+
+	*n = number(f)
+
+	return nil
+}
+
+func (n *node) config(key string) string {
+	v, exists := n.Configuration[key]
+	if !exists || v == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprint(v))
+}
+
+func (n *node) configInt(key string) int {
+	i, _ := strconv.Atoi(n.config(key))
+	return i
+}
+
+func (n *node) hasCapability(name string) bool {
+	_, exists := n.Capabilities[name]
+	return exists
+}
+
+// pciIDs matches the [vendor:product] and [vendor] suffixes lshw appends to names when run with -numeric
+var pciIDs = regexp.MustCompile(`\s*\[([0-9a-fA-F]{4})(?::([0-9a-fA-F]{4}))?\]$`)
+
+// name returns the given value with the numeric PCI IDs suffix removed
+func name(s string) string {
+	return strings.TrimSpace(pciIDs.ReplaceAllString(s, ""))
+}
+
+// ids returns the PCI IDs found in the given product name suffix
+func ids(s string) (vendorID, productID string) {
+	m := pciIDs.FindStringSubmatch(s)
+	if m == nil {
+		return "", ""
+	}
+
+	return strings.ToLower(m[1]), strings.ToLower(m[2])
+}
+
+// clean returns the given value with placeholder strings reported by the firmware treated as empty
+func clean(s string) string {
+	if common.IsPlaceholder(s) {
+		return ""
+	}
+
+	return strings.TrimSpace(s)
+}
+
+// vendor returns the normalized vendor name, falling back to a vendor identified in the product name
+func vendor(vendorName, product string) string {
+	if v := clean(name(vendorName)); v != "" {
+		return common.FormatVendorName(v)
+	}
+
+	return common.VendorFromString(product)
+}
+
+func capabilities(n *node) []*common.Capability {
+	if len(n.Capabilities) == 0 {
+		return nil
+	}
+
+	caps := make([]*common.Capability, 0, len(n.Capabilities))
+
+	for k, v := range n.Capabilities {
+		description, _ := v.(string)
+		caps = append(caps, &common.Capability{Name: k, Description: description, Enabled: true})
+	}
+
+	sort.Slice(caps, func(i, j int) bool { return caps[i].Name < caps[j].Name })
+
+	return caps
+}
+
+func firmware(version string) *common.Firmware {
+	version = clean(version)
+	if version == "" {
+		return nil
+	}
+
+	fw := common.NewFirmwareObj()
+	fw.Installed = version
+
+	return fw
+}
+
+// commonAttributes returns the Common attributes populated from the node
+func commonAttributes(n *node) common.Common {
+	vendorID, productID := ids(n.Product)
+
+	product := clean(name(n.Product))
+
+	return common.Common{
+		Description:  clean(name(n.Description)),
+		Vendor:       vendor(n.Vendor, product),
+		Model:        product,
+		ProductName:  product,
+		Serial:       clean(n.Serial),
+		LogicalName:  n.LogicalName.first(),
+		PCIVendorID:  vendorID,
+		PCIProductID: productID,
+		Capabilities: capabilities(n),
+	}
+}
+
+// Parse reads the JSON output of lshw and returns the Device it describes
+func Parse(r io.Reader) (*common.Device, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBytes(data)
+}
+
+// ParseBytes returns the Device described by the given lshw JSON output
+func ParseBytes(data []byte) (*common.Device, error) {
+	root, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &importer{
+		device: common.NewDevice(),
+		nics:   map[string]*common.NIC{},
+	}
+
+	i.system(root)
+	i.walk(root, "")
+
+	return &i.device, nil
+}
+
+// decode returns the system node, lshw releases differ in wrapping the output in a list
+func decode(data []byte) (*node, error) {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '[' {
+		nodes := []*node{}
+		if err := json.Unmarshal(data, &nodes); err != nil {
+			return nil, err
+		}
+
+		for _, n := range nodes {
+			if n.Class == classSystem {
+				return n, nil
+			}
+		}
+
+		return nil, errNoSystemNode
+	}
+
+	root := &node{}
+	if err := json.Unmarshal(data, root); err != nil {
+		return nil, err
+	}
+
+	if root.Class != classSystem {
+		return nil, errNoSystemNode
+	}
+
+	return root, nil
+}
+
+type importer struct {
+	device common.Device
+	// nics indexes NICs by their PCI device address, the ports are the PCI functions
+	nics map[string]*common.NIC
+}
+
+func (i *importer) system(n *node) {
+	i.device.Common = commonAttributes(n)
+
+	product := clean(productModel(name(n.Product)))
+	i.device.Model = common.FormatProductName(product)
+	i.device.ProductName = product
+	i.device.Chassis = n.config("chassis")
+}
+
+// productModel strips the SKU details lshw includes in the system product name,
+// "PowerEdge R6515 (SKU=NotProvided;ModelName=PowerEdge R6515)" is returned as "PowerEdge R6515".
+func productModel(product string) string {
+	if idx := strings.Index(product, " ("); idx > 0 {
+		return product[:idx]
+	}
+
+	return product
+}
+
+// walk visits the node children, controller is the ID of the storage controller the nodes are attached to.
+func (i *importer) walk(n *node, controller string) {
+	for _, child := range n.Children {
+		childController := controller
+
+		switch child.Class {
+		case classBus:
+			if child.ID == "core" || strings.EqualFold(child.Description, "motherboard") {
+				i.mainboard(child)
+			}
+		case classMemory:
+			i.memory(child)
+		case classProcessor:
+			i.cpu(child)
+		case classNetwork:
+			i.network(child)
+		case classStorage:
+			childController = i.storageController(child)
+		case classDisk:
+			i.drive(child, n, controller)
+		case classDisplay:
+			i.gpu(child)
+		}
+
+		i.walk(child, childController)
+	}
+}
+
+func (i *importer) mainboard(n *node) {
+	c := commonAttributes(n)
+
+	// the version is the board revision
+	if version := clean(n.Version); version != "" {
+		c.Metadata = map[string]string{"version": version}
+	}
+
+	i.device.Mainboard = &common.Mainboard{
+		Common:     c,
+		PhysicalID: n.PhysID,
+	}
+
+	// boards shipped in white box chassis leave the system vendor unset
+	if i.device.Vendor == "" {
+		i.device.Vendor = c.Vendor
+	}
+}
+
+func (i *importer) memory(n *node) {
+	switch {
+	// system firmware
+	case n.ID == "firmware" || strings.EqualFold(n.Description, "BIOS"):
+		c := commonAttributes(n)
+		c.Firmware = firmware(n.Version)
+
+		i.device.BIOS = &common.BIOS{
+			Common:        c,
+			SizeBytes:     int64(n.Size),
+			CapacityBytes: int64(n.Capacity),
+		}
+	// memory modules are listed as banks of the system memory node
+	case strings.HasPrefix(n.ID, "bank"):
+		i.memoryModule(n)
+	}
+}
+
+var memoryTypes = regexp.MustCompile(`\b(LP)?DDR\d?\b`)
+
+func (i *importer) memoryModule(n *node) {
+	c := commonAttributes(n)
+
+	// skip unpopulated slots
+	if n.Size == 0 && c.Serial == "" {
+		return
+	}
+
+	m := &common.Memory{
+		Common:       c,
+		ID:           n.ID,
+		Slot:         clean(n.Slot),
+		SizeBytes:    int64(n.Size),
+		PartNumber:   c.Model,
+		ClockSpeedHz: int64(n.Clock),
+		Type:         memoryTypes.FindString(n.Description),
+	}
+
+	// "DIMM DDR4 Synchronous Registered (Buffered) 3200 MHz (0.3 ns)"
+	if fields := strings.Fields(n.Description); len(fields) > 0 && strings.Contains(fields[0], "DIMM") {
+		m.FormFactor = fields[0]
+	}
+
+	i.device.Memory = append(i.device.Memory, m)
+}
+
+func (i *importer) cpu(n *node) {
+	// skip empty sockets
+	if n.Disabled || clean(n.Product) == "" {
+		return
+	}
+
+	c := commonAttributes(n)
+	c.Firmware = firmware(n.config("microcode"))
+
+	cpu := &common.CPU{
+		Common:       c,
+		ID:           n.ID,
+		Slot:         clean(n.Slot),
+		ClockSpeedHz: int64(n.Size),
+		Cores:        n.configInt("cores"),
+		Threads:      n.configInt("threads"),
+	}
+
+	if n.hasCapability("x86-64") {
+		cpu.Architecture = "x86_64"
+	}
+
+	i.device.CPUs = append(i.device.CPUs, cpu)
+}
+
+// pciDevice returns the PCI device address without the function, "pci@0000:41:00.1" is returned as "0000:41:00".
+func pciDevice(busInfo string) string {
+	addr := strings.TrimPrefix(busInfo, "pci@")
+	if idx := strings.LastIndex(addr, "."); idx > 0 {
+		addr = addr[:idx]
+	}
+
+	return addr
+}
+
+func (i *importer) network(n *node) {
+	// skip virtual and usb interfaces, for example the bonds, vlans and BMC usb network interface
+	if !strings.HasPrefix(n.BusInfo, "pci@") {
+		return
+	}
+
+	c := commonAttributes(n)
+
+	// the NIC firmware is reported as "14.32.1010 (MT_2420110034)"
+	fw := n.config("firmware")
+	if fields := strings.Fields(fw); len(fields) > 0 {
+		fw = fields[0]
+	}
+
+	addr := pciDevice(n.BusInfo)
+
+	nic, exists := i.nics[addr]
+	if !exists {
+		nic = &common.NIC{
+			Common: common.Common{
+				Description:  c.Description,
+				Vendor:       c.Vendor,
+				Model:        c.Model,
+				ProductName:  c.ProductName,
+				PCIVendorID:  c.PCIVendorID,
+				PCIProductID: c.PCIProductID,
+				Firmware:     firmware(fw),
+				Metadata:     map[string]string{},
+			},
+			ID: addr,
+		}
+
+		if driver := n.config("driver"); driver != "" {
+			nic.Metadata["driver"] = driver
+		}
+
+		i.nics[addr] = nic
+		i.device.NICs = append(i.device.NICs, nic)
+	}
+
+	port := &common.NICPort{
+		Common: common.Common{
+			Description:  c.Description,
+			Vendor:       c.Vendor,
+			Model:        c.Model,
+			ProductName:  c.ProductName,
+			LogicalName:  c.LogicalName,
+			PCIVendorID:  c.PCIVendorID,
+			PCIProductID: c.PCIProductID,
+			Capabilities: c.Capabilities,
+			Firmware:     firmware(fw),
+		},
+		ID:         c.LogicalName,
+		PhysicalID: n.PhysID,
+		BusInfo:    n.BusInfo,
+		MacAddress: strings.ToLower(c.Serial),
+		AutoNeg:    n.config("autonegotiation") == "on",
+		SpeedBits:  int64(n.Size),
+	}
+
+	if port.ID == "" {
+		port.ID = n.BusInfo
+	}
+
+	if port.SpeedBits == 0 {
+		port.SpeedBits = int64(n.Capacity)
+	}
+
+	switch n.config("link") {
+	case "yes":
+		port.LinkStatus = "up"
+	case "no":
+		port.LinkStatus = "down"
+	}
+
+	if n.hasCapability("ethernet") {
+		port.ActiveLinkTechnology = "Ethernet"
+	}
+
+	nic.NICPorts = append(nic.NICPorts, port)
+}
+
+// nvmeController returns true for NVMe controllers, their namespaces are listed as disk children.
+func nvmeController(n *node) bool {
+	return n.Class == classStorage && (n.ID == "nvme" || strings.HasPrefix(n.ID, "nvme:") || n.config("driver") == "nvme")
+}
+
+// storageController adds the StorageController and returns its ID,
+// NVMe controllers are not added, their attributes are set on the namespace drives.
+func (i *importer) storageController(n *node) string {
+	if nvmeController(n) {
+		return ""
+	}
+
+	c := commonAttributes(n)
+	c.Firmware = firmware(n.config("firmware"))
+
+	sc := &common.StorageController{
+		Common:     c,
+		ID:         n.BusInfo,
+		PhysicalID: n.PhysID,
+		BusInfo:    n.BusInfo,
+	}
+
+	if sc.ID == "" {
+		sc.ID = n.ID
+	}
+
+	if strings.HasPrefix(n.BusInfo, "pci@") {
+		sc.SupportedControllerProtocols = "PCIe"
+	}
+
+	description := strings.ToLower(n.ID + " " + n.Description + " " + n.Product)
+
+	switch {
+	case strings.Contains(description, "sas"), strings.Contains(description, "raid"):
+		sc.SupportedDeviceProtocols = "SAS, SATA"
+	case strings.Contains(description, "sata"), strings.Contains(description, "ahci"):
+		sc.SupportedDeviceProtocols = "SATA"
+	}
+
+	if driver := n.config("driver"); driver != "" {
+		sc.Metadata = map[string]string{"driver": driver}
+	}
+
+	i.device.StorageControllers = append(i.device.StorageControllers, sc)
+
+	return sc.ID
+}
+
+func (i *importer) drive(n, parent *node, controller string) {
+	// skip optical and removable drives
+	if !strings.HasPrefix(n.ID, "disk") && !strings.HasPrefix(n.ID, "namespace") {
+		return
+	}
+
+	c := commonAttributes(n)
+	c.Firmware = firmware(n.Version)
+
+	drive := &common.Drive{
+		Common:            c,
+		ID:                c.LogicalName,
+		BusInfo:           n.BusInfo,
+		StorageController: controller,
+		CapacityBytes:     int64(n.Size),
+		BlockSizeBytes:    int64(n.configInt("logicalsectorsize")),
+	}
+
+	if drive.ID == "" {
+		drive.ID = n.BusInfo
+	}
+
+	if nvmeController(parent) {
+		// namespaces inherit the model, serial and firmware of the controller
+		pc := commonAttributes(parent)
+		if drive.Model == "" {
+			drive.Model, drive.ProductName = pc.Model, pc.ProductName
+		}
+
+		if drive.Serial == "" {
+			drive.Serial = pc.Serial
+		}
+
+		if drive.Vendor == "" {
+			drive.Vendor = pc.Vendor
+		}
+
+		if drive.Firmware == nil {
+			drive.Firmware = firmware(parent.Version)
+		}
+
+		drive.PCIVendorID, drive.PCIProductID = pc.PCIVendorID, pc.PCIProductID
+		drive.BusInfo = parent.BusInfo
+		drive.Protocol = "NVMe"
+		drive.Type = common.SlugDriveTypePCIeNVMEeSSD
+	} else {
+		drive.Protocol = driveProtocol(n)
+		drive.Type = driveType(n)
+	}
+
+	i.device.Drives = append(i.device.Drives, drive)
+}
+
+func driveProtocol(n *node) string {
+	description := strings.ToLower(n.Description)
+
+	switch {
+	case strings.HasPrefix(n.BusInfo, "nvme@"):
+		return "NVMe"
+	case strings.Contains(description, "ata"):
+		return "SATA"
+	case strings.HasPrefix(n.BusInfo, "scsi@"):
+		return "SCSI"
+	default:
+		return ""
+	}
+}
+
+// driveType identifies SATA SSDs and HDDs from the attributes lshw reports
+func driveType(n *node) string {
+	product := strings.ToLower(n.Product + " " + n.Description)
+
+	switch {
+	case strings.HasPrefix(n.BusInfo, "nvme@"):
+		return common.SlugDriveTypePCIeNVMEeSSD
+	case driveProtocol(n) != "SATA":
+		return ""
+	case strings.Contains(product, "ssd"), n.hasCapability("ssd"), strings.HasPrefix(strings.ToLower(n.Product), "micron_"):
+		return common.SlugDriveTypeSATASSD
+	case rotational(n):
+		return common.SlugDriveTypeSATAHDD
+	default:
+		return ""
+	}
+}
+
+// rotational returns true when lshw reports the drive rotation speed, for example the "7200rpm" capability
+func rotational(n *node) bool {
+	for k := range n.Capabilities {
+		if strings.HasSuffix(k, "rpm") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *importer) gpu(n *node) {
+	c := commonAttributes(n)
+
+	// skip the BMC integrated VGA controllers
+	v := strings.ToLower(n.Vendor)
+	if strings.Contains(v, "aspeed") || strings.Contains(v, "matrox") {
+		return
+	}
+
+	i.device.GPUs = append(i.device.GPUs, &common.GPU{Common: c})
+}
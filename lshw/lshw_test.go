@@ -0,0 +1,223 @@
+package lshw
+
+import (
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+)
+
+func parseFixture(t *testing.T, name string) *common.Device {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	device, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return device
+}
+
+func TestParse(t *testing.T) {
+	testcases := []struct {
+		fixture            string
+		vendor             string
+		model              string
+		serial             string
+		mainboard          string
+		bios               string
+		cpus               int
+		threads            int
+		memory             int
+		nics               int
+		nicPorts           int
+		nicFirmware        string
+		drives             int
+		storageControllers int
+		gpus               int
+	}{
+		{
+			fixture:            "dell_r6515.json",
+			vendor:             common.VendorDell,
+			model:              "r6515",
+			serial:             "7XKQ3C3",
+			mainboard:          "07PXPY",
+			bios:               "2.10.2",
+			cpus:               1,
+			threads:            64,
+			memory:             2,
+			nics:               1,
+			nicPorts:           2,
+			nicFirmware:        "14.32.1010",
+			drives:             2,
+			storageControllers: 1,
+			gpus:               0,
+		},
+		{
+			fixture:            "supermicro_x11dph-t.json",
+			vendor:             common.VendorSupermicro,
+			model:              "x11dph-t",
+			serial:             "S412345X9A01234",
+			mainboard:          "X11DPH-T",
+			bios:               "3.4",
+			cpus:               2,
+			threads:            40,
+			memory:             2,
+			nics:               1,
+			nicPorts:           2,
+			nicFirmware:        "8.30",
+			drives:             2,
+			storageControllers: 1,
+			gpus:               1,
+		},
+		{
+			fixture:            "asrockrack_e3c246d4i-2t.json",
+			vendor:             common.VendorAsrockrack,
+			model:              "",
+			serial:             "",
+			mainboard:          "E3C246D4I-2T",
+			bios:               "L2.07B",
+			cpus:               1,
+			threads:            16,
+			memory:             2,
+			nics:               1,
+			nicPorts:           2,
+			nicFirmware:        "0x800010a8",
+			drives:             1,
+			storageControllers: 0,
+			gpus:               0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			d := parseFixture(t, tc.fixture)
+
+			if d.Vendor != tc.vendor {
+				t.Errorf("expected vendor %q, got: %q", tc.vendor, d.Vendor)
+			}
+
+			if d.Model != tc.model {
+				t.Errorf("expected model %q, got: %q", tc.model, d.Model)
+			}
+
+			if d.Serial != tc.serial {
+				t.Errorf("expected serial %q, got: %q", tc.serial, d.Serial)
+			}
+
+			if d.Mainboard.Model != tc.mainboard {
+				t.Errorf("expected mainboard %q, got: %q", tc.mainboard, d.Mainboard.Model)
+			}
+
+			if d.BIOS.Firmware == nil || d.BIOS.Firmware.Installed != tc.bios {
+				t.Errorf("expected BIOS firmware %q, got: %v", tc.bios, d.BIOS.Firmware)
+			}
+
+			if len(d.CPUs) != tc.cpus {
+				t.Fatalf("expected %d CPUs, got: %d", tc.cpus, len(d.CPUs))
+			}
+
+			if d.CPUs[0].Threads != tc.threads || d.CPUs[0].Architecture != "x86_64" {
+				t.Errorf("expected %d threads x86_64 CPU, got: %+v", tc.threads, d.CPUs[0])
+			}
+
+			if len(d.Memory) != tc.memory {
+				t.Errorf("expected %d memory modules, got: %d", tc.memory, len(d.Memory))
+			}
+
+			if len(d.NICs) != tc.nics {
+				t.Fatalf("expected %d NICs, got: %d", tc.nics, len(d.NICs))
+			}
+
+			if len(d.NICs[0].NICPorts) != tc.nicPorts {
+				t.Errorf("expected %d NIC ports, got: %d", tc.nicPorts, len(d.NICs[0].NICPorts))
+			}
+
+			if d.NICs[0].Firmware == nil || d.NICs[0].Firmware.Installed != tc.nicFirmware {
+				t.Errorf("expected NIC firmware %q, got: %v", tc.nicFirmware, d.NICs[0].Firmware)
+			}
+
+			if len(d.Drives) != tc.drives {
+				t.Errorf("expected %d drives, got: %d", tc.drives, len(d.Drives))
+			}
+
+			if len(d.StorageControllers) != tc.storageControllers {
+				t.Errorf("expected %d storage controllers, got: %d", tc.storageControllers, len(d.StorageControllers))
+			}
+
+			if len(d.GPUs) != tc.gpus {
+				t.Errorf("expected %d GPUs, got: %d", tc.gpus, len(d.GPUs))
+			}
+
+			if violations := common.Validate(d); len(violations) > 0 {
+				// memory vendors reported as JEDEC codes are not normalized
+				for _, v := range violations {
+					if v.Rule != "vendor-normalized" || v.Component != common.SlugPhysicalMem {
+						t.Errorf("unexpected violation: %s", v)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseDellR6515(t *testing.T) {
+	d := parseFixture(t, "dell_r6515.json")
+
+	cpu := d.CPUs[0]
+	if cpu.Vendor != common.VendorAMD || cpu.Cores != 32 || cpu.ClockSpeedHz != 2500000000 {
+		t.Errorf("unexpected CPU: %+v", cpu)
+	}
+
+	port := d.NICs[0].NICPorts[0]
+	if port.MacAddress != "b8:59:9f:c7:1a:02" || port.SpeedBits != 25000000000 || port.LinkStatus != "up" || !port.AutoNeg {
+		t.Errorf("unexpected NIC port: %+v", port)
+	}
+
+	if d.NICs[0].Vendor != common.VendorMellanox || d.NICs[0].ID != "0000:41:00" {
+		t.Errorf("unexpected NIC: %+v", d.NICs[0])
+	}
+
+	mem := d.Memory[1]
+	if mem.Type != "DDR4" || mem.FormFactor != "DIMM" || mem.Vendor != common.VendorSamsung || mem.Slot != "A2" {
+		t.Errorf("unexpected memory module: %+v", mem)
+	}
+
+	sata := d.Drives[0]
+	if sata.Vendor != common.VendorMicron || sata.Type != common.SlugDriveTypeSATASSD || sata.StorageController != "pci@0000:43:00.0" {
+		t.Errorf("unexpected SATA drive: %+v", sata)
+	}
+
+	nvme := d.Drives[1]
+	if nvme.Type != common.SlugDriveTypePCIeNVMEeSSD || nvme.Serial != "S6FANA0T100123" || nvme.Firmware.Installed != "2.1.8" || nvme.Vendor != common.VendorSamsung {
+		t.Errorf("unexpected NVMe drive: %+v", nvme)
+	}
+}
+
+func TestParseSupermicroNumeric(t *testing.T) {
+	d := parseFixture(t, "supermicro_x11dph-t.json")
+
+	nic := d.NICs[0]
+	if nic.PCIVendorID != "8086" || nic.PCIProductID != "1572" || nic.Model != "Ethernet Controller X710 for 10GbE SFP+" {
+		t.Errorf("unexpected NIC: %+v", nic)
+	}
+
+	if d.StorageControllers[0].Vendor != common.VendorBroadcom {
+		t.Errorf("expected storage controller vendor %q, got: %q", common.VendorBroadcom, d.StorageControllers[0].Vendor)
+	}
+
+	if d.Drives[0].Vendor != common.VendorHGST || d.Drives[0].Type != common.SlugDriveTypeSATAHDD {
+		t.Errorf("unexpected HDD: %+v", d.Drives[0])
+	}
+
+	if d.GPUs[0].PCIVendorID != "10de" {
+		t.Errorf("expected GPU PCI vendor 10de, got: %q", d.GPUs[0].PCIVendorID)
+	}
+}
@@ -0,0 +1,277 @@
+package common
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	errModelName   = errors.New("invalid model name")
+	errModelExists = errors.New("a model is already registered with the key")
+)
+
+// Form factors of a catalog Model
+const (
+	FormFactor1U = "1U"
+	FormFactor2U = "2U"
+	// FormFactorSled is a node in a multi node chassis
+	FormFactorSled = "sled"
+)
+
+// Model is a catalog entry mapping the chassis SKUs, marketing names and board identifiers of a
+// server onto its canonical mainboard model and vendor.
+type Model struct {
+	// Vendor is the normalized vendor name, for example VendorSupermicro
+	Vendor string `json:"vendor"`
+	// Model is the canonical model name, the mainboard model where the vendor
+	// builds several chassis SKUs around the board, for example x11scm-f
+	Model string `json:"model"`
+	// ProductNames are the chassis SKUs and marketing names reported for the model, matched case insensitively
+	ProductNames []string `json:"product_names,omitempty"`
+	// BoardIDs are the vendor board identifiers, as the Supermicro BoardID in /redfish/v1/Chassis/1
+	BoardIDs []string `json:"board_ids,omitempty"`
+	// PCISubsystems are the PCI subsystem "vendor:device" IDs reported by the mainboard chipset
+	PCISubsystems []string `json:"pci_subsystems,omitempty"`
+
+	// FormFactor is the chassis form factor, one of the FormFactor constants, empty when unknown
+	FormFactor string `json:"form_factor,omitempty"`
+	// CPUSockets and DIMMSlots are the mainboard CPU socket and DIMM slot counts, 0 when unknown
+	CPUSockets int `json:"cpu_sockets,omitempty"`
+	DIMMSlots  int `json:"dimm_slots,omitempty"`
+}
+
+//go:embed models.json
+var builtinModels []byte
+
+var (
+	builtinModelsOnce   sync.Once
+	modelsMu            sync.RWMutex
+	models              []*Model
+	modelsByProductName = map[string]*Model{}
+	modelsByBoardID     = map[string]*Model{}
+	modelsByPCISubsys   = map[string]*Model{}
+)
+
+// loadBuiltinModels registers the builtin catalog on first use, after the vendor registry
+// used to normalize the model vendors is initialized.
+func loadBuiltinModels() {
+	builtinModelsOnce.Do(func() {
+		list := []Model{}
+		if err := json.Unmarshal(builtinModels, &list); err != nil {
+			panic(err)
+		}
+
+		for idx := range list {
+			if err := registerModel(list[idx]); err != nil {
+				panic(err)
+			}
+		}
+	})
+}
+
+// LoadModels registers the models listed in the given JSON file, see ParseModels
+func LoadModels(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := ParseModels(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ParseModels registers the models in the given JSON document, a list of Model entries
+// in the format of the builtin catalog models.json.
+func ParseModels(r io.Reader) error {
+	list := []Model{}
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return err
+	}
+
+	for idx := range list {
+		if err := RegisterModel(list[idx]); err != nil {
+			return fmt.Errorf("model %d: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// RegisterModel adds the Model to the catalog used by FormatProductName, SupermicroModelFromBoardID and
+// the ModelBy lookup functions. An error is returned if a product name, board ID or PCI subsystem
+// of the Model is already registered.
+func RegisterModel(m Model) error {
+	loadBuiltinModels()
+
+	return registerModel(m)
+}
+
+func registerModel(m Model) error {
+	m.Vendor = FormatVendorName(strings.TrimSpace(m.Vendor))
+	m.Model = strings.TrimSpace(m.Model)
+
+	if m.Model == "" {
+		return fmt.Errorf("%w : %q", errModelName, m.Model)
+	}
+
+	productNames := normalizeKeys(m.ProductNames, productNameKey)
+	boardIDs := normalizeKeys(m.BoardIDs, func(id string) string { return boardIDKey(m.Vendor, id) })
+	pciSubsystems := normalizeKeys(m.PCISubsystems, pciSubsystemKey)
+
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+
+	for _, check := range []struct {
+		keys  []string
+		index map[string]*Model
+	}{
+		{productNames, modelsByProductName},
+		{boardIDs, modelsByBoardID},
+		{pciSubsystems, modelsByPCISubsys},
+	} {
+		for _, key := range check.keys {
+			if _, exists := check.index[key]; exists {
+				return fmt.Errorf("%w : %s", errModelExists, key)
+			}
+		}
+	}
+
+	entry := &m
+	models = append(models, entry)
+
+	for _, key := range productNames {
+		modelsByProductName[key] = entry
+	}
+
+	for _, key := range boardIDs {
+		modelsByBoardID[key] = entry
+	}
+
+	for _, key := range pciSubsystems {
+		modelsByPCISubsys[key] = entry
+	}
+
+	return nil
+}
+
+// Models returns the catalog entries sorted by vendor and model
+func Models() []Model {
+	loadBuiltinModels()
+
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+
+	list := make([]Model, 0, len(models))
+	for _, m := range models {
+		list = append(list, *m)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Vendor != list[j].Vendor {
+			return list[i].Vendor < list[j].Vendor
+		}
+
+		return list[i].Model < list[j].Model
+	})
+
+	return list
+}
+
+// ModelByProductName returns the catalog Model listing the given chassis SKU or marketing name
+func ModelByProductName(name string) (Model, bool) {
+	return lookupModel(modelsByProductName, productNameKey(name))
+}
+
+// ModelByBoardID returns the catalog Model with the given vendor board identifier, for example
+// the Supermicro BoardID 0x1b09
+func ModelByBoardID(vendor, id string) (Model, bool) {
+	return lookupModel(modelsByBoardID, boardIDKey(FormatVendorName(vendor), id))
+}
+
+// ModelByPCISubsystem returns the catalog Model with the given PCI subsystem vendor and device IDs,
+// for example 15d9 and 1b09
+func ModelByPCISubsystem(subVendorID, subDeviceID string) (Model, bool) {
+	return lookupModel(modelsByPCISubsys, pciSubsystemKey(subVendorID+":"+subDeviceID))
+}
+
+func lookupModel(index map[string]*Model, key string) (Model, bool) {
+	loadBuiltinModels()
+
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+
+	m, exists := index[key]
+	if !exists {
+		return Model{}, false
+	}
+
+	return *m, true
+}
+
+func normalizeKeys(values []string, key func(string) string) []string {
+	keys := make([]string, 0, len(values))
+
+	for _, v := range values {
+		if k := key(v); k != "" {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+func productNameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// boardIDKey returns the vendor and board ID key, hexadecimal board IDs are compared as numbers
+// so 0x89a and 0x089A are equal.
+func boardIDKey(vendor, id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id == "" {
+		return ""
+	}
+
+	if strings.HasPrefix(id, "0x") {
+		if n, err := strconv.ParseUint(id[2:], 16, 64); err == nil {
+			id = "0x" + strconv.FormatUint(n, 16)
+		}
+	}
+
+	return vendor + ":" + id
+}
+
+// pciSubsystemKey returns the "vendor:device" PCI subsystem key with 4 digit IDs
+func pciSubsystemKey(s string) string {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(s)), ":")
+	if len(parts) != 2 {
+		return ""
+	}
+
+	for i, p := range parts {
+		p = strings.TrimPrefix(strings.TrimSpace(p), "0x")
+		if p == "" {
+			return ""
+		}
+
+		if len(p) < 4 {
+			p = strings.Repeat("0", 4-len(p)) + p
+		}
+
+		parts[i] = p
+	}
+
+	return parts[0] + ":" + parts[1]
+}
@@ -0,0 +1,143 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var productNameTestStrings = []string{
+	"", "PowerEdge R6515", "PowerEdge R640", "PowerEdge R6415", "PowerEdge R750", "PowerEdge C6320", "PowerEdge R740xd",
+	"PIO-519C-MR-PH004", "SYS-5019C-MR-PH004", "SYS-5019C-MR", "SYS-5039MS-H12TRF", "SYS-510T-MR-EI018", "SYS-510T-MR1-EI018",
+	"SYS-510T-MR2-EI018", "SSG-6029P-E1CR12L-PH004", "SSG-6029P-E1CR12L", "SSG-110P-NTR10", "SSG-110P-NTR10-EI018",
+	"SSG-110P-NTR10-2-EI018", "Micron_5200_MTFDDAK480TDN", "SYS-221H-TN24R", "x11scm-f", "*connectx*",
+	"poweredge r640", "POWEREDGE R640", " PowerEdge R640", "sys-5019c-mr",
+}
+
+func TestModelCatalogMatchesLegacy(t *testing.T) {
+	for _, s := range productNameTestStrings {
+		if got, expected := FormatProductName(s), legacyFormatProductName(s); got != expected {
+			t.Errorf("FormatProductName(%q) = %q, expected %q", s, got, expected)
+		}
+	}
+
+	for _, id := range []string{"0x89a", "0x1B09", "0x1b0a", ""} {
+		if got, expected := SupermicroModelFromBoardID(id), legacySupermicroModelFromBoardID(id); got != expected {
+			t.Errorf("SupermicroModelFromBoardID(%q) = %q, expected %q", id, got, expected)
+		}
+	}
+}
+
+func TestModelLookups(t *testing.T) {
+	m, ok := ModelByProductName("poweredge r640")
+	if !ok || m.Vendor != VendorDell || m.Model != "r640" || m.CPUSockets != 2 || m.DIMMSlots != 24 || m.FormFactor != FormFactor1U {
+		t.Errorf("ModelByProductName = %+v, %v", m, ok)
+	}
+
+	if m, ok := ModelByBoardID("SUPERMICRO", "0x089A"); !ok || m.Model != "x11ssl-f" {
+		t.Errorf("ModelByBoardID = %+v, %v", m, ok)
+	}
+
+	if _, ok := ModelByBoardID(VendorDell, "0x89a"); ok {
+		t.Error("expected board IDs to be matched by vendor")
+	}
+
+	if m, ok := ModelByPCISubsystem("0x15d9", "1B09"); !ok || m.Model != "x11scz-f" {
+		t.Errorf("ModelByPCISubsystem = %+v, %v", m, ok)
+	}
+
+	if _, ok := ModelByPCISubsystem("15d9", "ffff"); ok {
+		t.Error("expected no match for an unknown PCI subsystem")
+	}
+}
+
+func TestRegisterModel(t *testing.T) {
+	if err := RegisterModel(Model{Vendor: VendorDell}); !errors.Is(err, errModelName) {
+		t.Errorf("expected errModelName, got %v", err)
+	}
+
+	if err := RegisterModel(Model{Vendor: VendorDell, Model: "r640", ProductNames: []string{"PowerEdge R640"}}); !errors.Is(err, errModelExists) {
+		t.Errorf("expected errModelExists, got %v", err)
+	}
+
+	err := ParseModels(strings.NewReader(`[{
+		"vendor": "Dell Inc.",
+		"model": "r740xd",
+		"product_names": ["PowerEdge R740xd"],
+		"form_factor": "2U",
+		"cpu_sockets": 2,
+		"dimm_slots": 24
+	}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FormatProductName("PowerEdge R740xd"); got != "r740xd" {
+		t.Errorf("expected r740xd, got %s", got)
+	}
+
+	found := false
+
+	for _, m := range Models() {
+		found = found || (m.Vendor == VendorDell && m.Model == "r740xd")
+	}
+
+	if !found {
+		t.Error("expected r740xd to be listed in the catalog")
+	}
+
+	if err := LoadModels("testdata/does-not-exist.json"); err == nil {
+		t.Error("expected an error loading a missing file")
+	}
+}
+
+// The switch statements replaced by the model catalog, retained to verify the catalog returns the same results.
+
+// legacyFormatProductName returns a normalized product name given a product name
+//
+// nolint:gocyclo // This list is expected to be long.
+func legacyFormatProductName(s string) string {
+	switch s {
+	case "PowerEdge R6515":
+		return "r6515"
+	case "PowerEdge R640":
+		return "r640"
+	case "PowerEdge R6415":
+		return "r6415"
+	case "PowerEdge R750":
+		return "r750"
+	case "PowerEdge C6320":
+		return "c6320"
+	case "PIO-519C-MR-PH004":
+		return "x11sch-f"
+	case "SYS-5019C-MR-PH004", "SYS-5019C-MR":
+		return "x11scm-f"
+	case "SYS-5039MS-H12TRF":
+		return "x11sse-f"
+	case "SYS-510T-MR-EI018", "SYS-510T-MR1-EI018", "SYS-510T-MR2-EI018":
+		return "x12sth-sys"
+	case "SSG-6029P-E1CR12L-PH004", "SSG-6029P-E1CR12L":
+		return "x11dph-t"
+	case "SSG-110P-NTR10", "SSG-110P-NTR10-EI018", "SSG-110P-NTR10-2-EI018":
+		return "x12spo-ntf"
+	case "Micron_5200_MTFDDAK480TDN":
+		return "5200MAX"
+	case "SYS-221H-TN24R":
+		return "x13dem"
+	default:
+		return s
+	}
+}
+
+// legacySupermicroModelFromBoardID returns the Supermicro motherboard model for the given BoardID identifier
+// from /redfish/v1/Chassis/1
+func legacySupermicroModelFromBoardID(id string) string {
+	switch strings.ToLower(id) {
+	case "0x89a":
+		return "x11ssl-f"
+	case "0x1b09": // 15d9:1b09 is the Q370 Chipset LPC/eSPI Controller on the board
+		return "x11scz-f"
+	default:
+		return ""
+	}
+}
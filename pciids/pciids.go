@@ -0,0 +1,362 @@
+// Package pciids resolves PCI vendor, device and subsystem names from the IDs listed in the pci.ids database.
+//
+// A subset of the database covering common server components is embedded, the complete database
+// shipped with the OS can be loaded with Load and set as the default with SetDefault.
+package pciids
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// Common.Metadata keys read by Enrich for the PCI subsystem IDs
+const (
+	MetadataSubsystemVendorID = "pci_subsystem_vendor_id"
+	MetadataSubsystemID       = "pci_subsystem_id"
+)
+
+// SystemPaths are the locations of the pci.ids database distributed with common Linux distributions
+var SystemPaths = []string{
+	"/usr/share/hwdata/pci.ids",
+	"/usr/share/misc/pci.ids",
+	"/usr/share/pci.ids",
+}
+
+var errInvalidLine = errors.New("invalid pci.ids line")
+
+//go:embed pci.ids
+var embedded []byte
+
+// Database holds the PCI vendors, devices and subsystems parsed from a pci.ids file
+type Database struct {
+	vendors map[string]*vendor
+}
+
+type vendor struct {
+	name    string
+	devices map[string]*device
+}
+
+type device struct {
+	name string
+	// subsystems are keyed by "subvendor:subdevice"
+	subsystems map[string]string
+}
+
+var (
+	defaultMu sync.RWMutex
+	defaultDB *Database
+)
+
+// Default returns the default Database, the embedded subset unless replaced with SetDefault
+func Default() *Database {
+	defaultMu.RLock()
+	db := defaultDB
+	defaultMu.RUnlock()
+
+	if db != nil {
+		return db
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultDB == nil {
+		var err error
+
+		defaultDB, err = Parse(bytes.NewReader(embedded))
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return defaultDB
+}
+
+// SetDefault replaces the Database returned by Default
+func SetDefault(db *Database) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultDB = db
+}
+
+// LoadDefault loads the first pci.ids file found in SystemPaths and sets it as the default Database,
+// the embedded subset remains the default when none is found.
+func LoadDefault() error {
+	for _, path := range SystemPaths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		db, err := Load(path)
+		if err != nil {
+			return err
+		}
+
+		SetDefault(db)
+
+		return nil
+	}
+
+	return os.ErrNotExist
+}
+
+// Load parses the pci.ids file at the given path
+func Load(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	db, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return db, nil
+}
+
+// Parse parses the vendors, devices and subsystems listed in the pci.ids format,
+// the device class list following them is ignored.
+func Parse(r io.Reader) (*Database, error) {
+	db := &Database{vendors: map[string]*vendor{}}
+
+	var (
+		v *vendor
+		d *device
+	)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// the device classes are listed last
+		if strings.HasPrefix(line, "C ") {
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(line, "\t\t"):
+			if d == nil {
+				return nil, fmt.Errorf("%w : %d: subsystem without a device", errInvalidLine, lineNum)
+			}
+
+			fields := strings.SplitN(strings.TrimSpace(line), " ", 3)
+			if len(fields) != 3 || !isID(fields[0]) || !isID(fields[1]) {
+				return nil, fmt.Errorf("%w : %d: %q", errInvalidLine, lineNum, line)
+			}
+
+			d.subsystems[normalizeID(fields[0])+":"+normalizeID(fields[1])] = strings.TrimSpace(fields[2])
+		case strings.HasPrefix(line, "\t"):
+			if v == nil {
+				return nil, fmt.Errorf("%w : %d: device without a vendor", errInvalidLine, lineNum)
+			}
+
+			id, name, err := splitEntry(line[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%w : %d: %q", errInvalidLine, lineNum, line)
+			}
+
+			d = &device{name: name, subsystems: map[string]string{}}
+			v.devices[id] = d
+		default:
+			id, name, err := splitEntry(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w : %d: %q", errInvalidLine, lineNum, line)
+			}
+
+			v = &vendor{name: name, devices: map[string]*device{}}
+			d = nil
+			db.vendors[id] = v
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// splitEntry splits a "id  name" entry
+func splitEntry(s string) (id, name string, err error) {
+	fields := strings.SplitN(s, " ", 2)
+	if len(fields) != 2 || !isID(fields[0]) {
+		return "", "", errInvalidLine
+	}
+
+	return normalizeID(fields[0]), strings.TrimSpace(fields[1]), nil
+}
+
+func isID(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+
+	for _, r := range strings.ToLower(s) {
+		if !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeID returns the lower cased ID without a 0x prefix
+func normalizeID(id string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(id)), "0x")
+}
+
+// Vendor returns the name of the vendor assigned the PCI vendor ID, for example "8086" or "0x8086"
+func (db *Database) Vendor(vendorID string) (string, bool) {
+	v, exists := db.vendors[normalizeID(vendorID)]
+	if !exists {
+		return "", false
+	}
+
+	return v.name, true
+}
+
+// Device returns the name of the device with the given PCI vendor and device IDs
+func (db *Database) Device(vendorID, deviceID string) (string, bool) {
+	d := db.device(vendorID, deviceID)
+	if d == nil {
+		return "", false
+	}
+
+	return d.name, true
+}
+
+// Subsystem returns the name of the subsystem, as the card built around the device by a vendor,
+// with the given PCI vendor, device, subsystem vendor and subsystem device IDs
+func (db *Database) Subsystem(vendorID, deviceID, subVendorID, subDeviceID string) (string, bool) {
+	d := db.device(vendorID, deviceID)
+	if d == nil {
+		return "", false
+	}
+
+	name, exists := d.subsystems[normalizeID(subVendorID)+":"+normalizeID(subDeviceID)]
+
+	return name, exists
+}
+
+func (db *Database) device(vendorID, deviceID string) *device {
+	v, exists := db.vendors[normalizeID(vendorID)]
+	if !exists {
+		return nil
+	}
+
+	return v.devices[normalizeID(deviceID)]
+}
+
+// VendorName returns the normalized vendor name, one of the common.Vendor* constants where known,
+// for the PCI vendor ID. Vendors missing from the common vendor registry are identified
+// by their pci.ids name.
+func (db *Database) VendorName(vendorID string) (string, bool) {
+	if m, ok := common.MatchVendorPCIID(vendorID); ok {
+		return m.Vendor, true
+	}
+
+	name, exists := db.Vendor(vendorID)
+	if !exists {
+		return "", false
+	}
+
+	return common.FormatVendorName(name), true
+}
+
+// Enrich fills the empty Vendor, Model and ProductName of the Device NICs, GPUs, StorageControllers
+// and NVMe drives from their PCI IDs.
+//
+// The Model is set to the device name and the ProductName to the subsystem name when the
+// subsystem IDs are set in the component Metadata, otherwise to the device name. nil components are skipped.
+func (db *Database) Enrich(d *common.Device) {
+	if d == nil {
+		return
+	}
+
+	for _, nic := range d.NICs {
+		if nic != nil {
+			db.enrich(&nic.Common)
+		}
+	}
+
+	for _, gpu := range d.GPUs {
+		if gpu != nil {
+			db.enrich(&gpu.Common)
+		}
+	}
+
+	for _, controller := range d.StorageControllers {
+		if controller != nil {
+			db.enrich(&controller.Common)
+		}
+	}
+
+	for _, drive := range d.Drives {
+		if drive != nil && drive.Type == common.SlugDriveTypePCIeNVMEeSSD {
+			db.enrich(&drive.Common)
+		}
+	}
+}
+
+func (db *Database) enrich(c *common.Common) {
+	if c.PCIVendorID == "" {
+		return
+	}
+
+	if c.Vendor == "" {
+		if name, ok := db.VendorName(c.PCIVendorID); ok {
+			c.Vendor = name
+		}
+	}
+
+	if c.PCIProductID == "" {
+		return
+	}
+
+	deviceName, ok := db.Device(c.PCIVendorID, c.PCIProductID)
+	if !ok {
+		return
+	}
+
+	if c.Model == "" {
+		c.Model = deviceName
+	}
+
+	if c.ProductName == "" {
+		c.ProductName = deviceName
+
+		if subsystem, ok := db.Subsystem(
+			c.PCIVendorID,
+			c.PCIProductID,
+			c.Metadata[MetadataSubsystemVendorID],
+			c.Metadata[MetadataSubsystemID],
+		); ok {
+			c.ProductName = subsystem
+		}
+	}
+}
+
+// Enrich fills the empty Device component names using the default Database, see Database.Enrich
+func Enrich(d *common.Device) {
+	Default().Enrich(d)
+}
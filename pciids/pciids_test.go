@@ -0,0 +1,154 @@
+package pciids
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+)
+
+func TestLoad(t *testing.T) {
+	db, err := Load("testdata/pci.ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name, _ := db.Vendor("ABCD"); name != "Example Vendor, Inc." {
+		t.Errorf("vendor = %q", name)
+	}
+
+	if name, _ := db.Device("0xabcd", "0002"); name != "Widget Controller v2" {
+		t.Errorf("device = %q", name)
+	}
+
+	if name, _ := db.Subsystem("abcd", "0001", "8086", "0001"); name != "Widget for Intel" {
+		t.Errorf("subsystem = %q", name)
+	}
+
+	if _, ok := db.Subsystem("abcd", "0002", "8086", "0001"); ok {
+		t.Error("expected no subsystem on device 0002")
+	}
+
+	// the class list is not parsed as vendors
+	if _, ok := db.Vendor("0200"); ok {
+		t.Error("expected the device classes to be skipped")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := []string{
+		"\t0001  device without a vendor\n",
+		"abcd  vendor\n\t\tabcd 0001  subsystem without a device\n",
+		"xyz  invalid id\n",
+		"abcd  vendor\n\t0001\n",
+	}
+
+	for _, c := range cases {
+		if _, err := Parse(strings.NewReader(c)); !errors.Is(err, errInvalidLine) {
+			t.Errorf("Parse(%q) error = %v, expected %v", c, err, errInvalidLine)
+		}
+	}
+}
+
+func TestVendorName(t *testing.T) {
+	db, err := Load("testdata/pci.ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"8086": common.VendorIntel,
+		// listed in the registry, not in the database
+		"15b3": common.VendorMellanox,
+		// listed in the database, not in the registry
+		"abcd": "Example Vendor, Inc.",
+	}
+
+	for id, expected := range cases {
+		if got, _ := db.VendorName(id); got != expected {
+			t.Errorf("VendorName(%q) = %q, expected %q", id, got, expected)
+		}
+	}
+
+	if _, ok := db.VendorName("ffff"); ok {
+		t.Error("expected unknown vendor ID ffff")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	db := Default()
+
+	if name, _ := db.Device("15b3", "1015"); name != "MT27710 Family [ConnectX-4 Lx]" {
+		t.Errorf("device = %q", name)
+	}
+
+	if name, _ := db.Subsystem("1000", "005d", "1028", "1f47"); name != "PERC H730P Mini" {
+		t.Errorf("subsystem = %q", name)
+	}
+
+	custom, err := Load("testdata/pci.ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetDefault(custom)
+	defer SetDefault(db)
+
+	if Default() != custom {
+		t.Error("expected SetDefault to replace the default database")
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	d := common.NewDevice()
+	d.NICs = []*common.NIC{
+		{Common: common.Common{PCIVendorID: "8086", PCIProductID: "1572", Metadata: map[string]string{
+			MetadataSubsystemVendorID: "1028",
+			MetadataSubsystemID:       "1f9c",
+		}}},
+		{Common: common.Common{Vendor: "Mellanox Technologies", Model: "CX4121A", PCIVendorID: "15b3", PCIProductID: "1015"}},
+	}
+	d.GPUs = []*common.GPU{{Common: common.Common{PCIVendorID: "10de", PCIProductID: "1eb8"}}}
+	d.StorageControllers = []*common.StorageController{{Common: common.Common{PCIVendorID: "1000", PCIProductID: "ffff"}}}
+	d.Drives = []*common.Drive{
+		{Common: common.Common{PCIVendorID: "144d", PCIProductID: "a808"}, Type: common.SlugDriveTypePCIeNVMEeSSD},
+		{Common: common.Common{PCIVendorID: "8086", PCIProductID: "a352"}, Type: common.SlugDriveTypeSATASSD},
+	}
+
+	Enrich(&d)
+
+	cases := []struct {
+		name                       string
+		got                        *common.Common
+		vendor, model, productName string
+	}{
+		{"NIC with subsystem", &d.NICs[0].Common, common.VendorIntel, "Ethernet Controller X710 for 10GbE SFP+", "Ethernet 10G 4P X710 SFP+ rNDC"},
+		{"NIC already named", &d.NICs[1].Common, "Mellanox Technologies", "CX4121A", "MT27710 Family [ConnectX-4 Lx]"},
+		{"GPU vendor from database", &d.GPUs[0].Common, "NVIDIA Corporation", "TU104GL [Tesla T4]", "TU104GL [Tesla T4]"},
+		{"unknown device", &d.StorageControllers[0].Common, common.VendorLSI, "", ""},
+		{"NVMe drive", &d.Drives[0].Common, common.VendorSamsung, "NVMe SSD Controller SM981/PM981/PM983", "NVMe SSD Controller SM981/PM981/PM983"},
+		{"SATA drive", &d.Drives[1].Common, "", "", ""},
+	}
+
+	for _, c := range cases {
+		if c.got.Vendor != c.vendor || c.got.Model != c.model || c.got.ProductName != c.productName {
+			t.Errorf("%s: got %q %q %q, expected %q %q %q", c.name,
+				c.got.Vendor, c.got.Model, c.got.ProductName, c.vendor, c.model, c.productName)
+		}
+	}
+}
+
+func TestEnrichNilComponents(t *testing.T) {
+	d := common.NewDevice()
+	d.NICs = []*common.NIC{nil, {Common: common.Common{PCIVendorID: "8086", PCIProductID: "1572"}}}
+	d.GPUs = []*common.GPU{nil}
+	d.StorageControllers = []*common.StorageController{nil}
+	d.Drives = []*common.Drive{nil}
+
+	Enrich(&d)
+
+	if d.NICs[1].Vendor != common.VendorIntel {
+		t.Errorf("expected the NIC after a nil entry to be enriched, got %q", d.NICs[1].Vendor)
+	}
+}
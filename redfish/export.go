@@ -0,0 +1,765 @@
+package redfish
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// Redfish ChassisType values, the Device Chassis is matched against these in lower case
+var chassisTypes = []string{
+	"Rack", "RackMount", "Blade", "Enclosure", "StandAlone", "Card", "Cartridge", "Row", "Pod",
+	"Expansion", "Sidecar", "Zone", "Sled", "Shelf", "Drawer", "Module", "Component", "IPBasedDrive",
+	"RackGroup", "StorageEnclosure",
+}
+
+var errNilDevice = errors.New("redfish export of a nil device")
+
+// resourceIDChars matches the component IDs usable as the Id of a Redfish resource
+var resourceIDChars = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+// Export returns the Redfish resources describing the Device, keyed by their @odata.id.
+//
+// The Device is exported as a single ComputerSystem, "/redfish/v1/Systems/1", linked to a
+// Chassis for each Enclosure and a Manager for the BMC. The component firmware is listed in the
+// UpdateService FirmwareInventory with a RelatedItem link to the component resource, so the
+// resources import back into an equivalent Device.
+func Export(d *common.Device) (MapSource, error) {
+	if d == nil {
+		return nil, errNilDevice
+	}
+
+	e := &exporter{device: d, resources: map[string]interface{}{}}
+	e.run()
+
+	src := MapSource{}
+
+	for id, r := range e.resources {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return nil, fmt.Errorf("encoding redfish resource %s: %w", id, err)
+		}
+
+		src[id] = data
+	}
+
+	return src, nil
+}
+
+// WriteDir writes the resources to the given directory in the layout read by DirSource
+func (m MapSource) WriteDir(dir string) error {
+	for id, data := range m {
+		path := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(id, "/")))
+
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(path, "index.json"), data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type exporter struct {
+	device    *common.Device
+	resources map[string]interface{}
+
+	// inventory holds the FirmwareInventory members
+	inventory []*SoftwareInventory
+}
+
+func (e *exporter) add(id string, r interface{}) {
+	e.resources[id] = r
+}
+
+// collection adds the resource collection with the given members and returns the link to it
+func (e *exporter) collection(id, typ, name string, members []string) *Link {
+	c := &Collection{
+		Resource: Resource{
+			ODataID:   id,
+			ODataType: "#" + typ + "Collection." + typ + "Collection",
+			Name:      name,
+		},
+		Members:      make([]Link, 0, len(members)),
+		MembersCount: len(members),
+	}
+
+	for _, m := range members {
+		c.Members = append(c.Members, Link{ODataID: m})
+	}
+
+	e.add(id, c)
+
+	return &Link{ODataID: id}
+}
+
+// firmware lists the component firmware in the FirmwareInventory
+func (e *exporter) firmware(id, name string, fw *common.Firmware, related string) {
+	if fw == nil || fw.Installed == "" {
+		return
+	}
+
+	sw := &SoftwareInventory{
+		Resource: Resource{
+			ODataID:   ServiceRootID + "/UpdateService/FirmwareInventory/" + id,
+			ODataType: "#SoftwareInventory.v1_2_0.SoftwareInventory",
+			ID:        id,
+			Name:      name,
+		},
+		Version:    fw.Installed,
+		SoftwareID: fw.SoftwareID,
+		Updateable: true,
+	}
+
+	if related != "" {
+		sw.RelatedItem = []Link{{ODataID: related}}
+	}
+
+	e.inventory = append(e.inventory, sw)
+}
+
+func (e *exporter) run() {
+	const (
+		systemsID  = ServiceRootID + "/Systems"
+		chassisID  = ServiceRootID + "/Chassis"
+		managersID = ServiceRootID + "/Managers"
+		updateID   = ServiceRootID + "/UpdateService"
+	)
+
+	systemID := systemsID + "/1"
+
+	chassis := e.chassis(chassisID, systemID)
+	manager := e.manager(managersID, systemID, chassis)
+
+	e.system(systemID, chassis, manager)
+
+	e.collection(systemsID, "ComputerSystem", "Computer System Collection", []string{systemID})
+	e.collection(chassisID, "Chassis", "Chassis Collection", chassis)
+	e.collection(managersID, "Manager", "Manager Collection", []string{manager})
+
+	inventoryMembers := make([]string, 0, len(e.inventory))
+	for _, sw := range e.inventory {
+		inventoryMembers = append(inventoryMembers, sw.ODataID)
+		e.add(sw.ODataID, sw)
+	}
+
+	e.add(updateID, &UpdateService{
+		Resource: Resource{
+			ODataID:   updateID,
+			ODataType: "#UpdateService.v1_8_0.UpdateService",
+			ID:        "UpdateService",
+			Name:      "Update Service",
+		},
+		ServiceEnabled:    true,
+		FirmwareInventory: e.collection(updateID+"/FirmwareInventory", "SoftwareInventory", "Firmware Inventory Collection", inventoryMembers),
+	})
+
+	root := &ServiceRoot{
+		Resource: Resource{
+			ODataID:   ServiceRootID,
+			ODataType: "#ServiceRoot.v1_5_0.ServiceRoot",
+			ID:        "RootService",
+			Name:      "Root Service",
+		},
+		RedfishVersion: "1.9.0",
+		UUID:           e.device.Metadata["uuid"],
+		Systems:        &Link{ODataID: systemsID},
+		Chassis:        &Link{ODataID: chassisID},
+		Managers:       &Link{ODataID: managersID},
+		UpdateService:  &Link{ODataID: updateID},
+	}
+
+	e.add(ServiceRootID, root)
+}
+
+func (e *exporter) system(id string, chassis []string, manager string) {
+	d := e.device
+
+	s := &ComputerSystem{
+		Resource: Resource{
+			ODataID:   id,
+			ODataType: "#ComputerSystem.v1_10_0.ComputerSystem",
+			ID:        "1",
+			Name:      "System",
+		},
+		Manufacturer: d.Vendor,
+		Model:        d.ProductName,
+		SerialNumber: d.Serial,
+		SKU:          d.Metadata["sku"],
+		PartNumber:   d.Metadata["part_number"],
+		UUID:         d.Metadata["uuid"],
+		PowerState:   d.Metadata["power_state"],
+		SystemType:   "Physical",
+		Status:       redfishStatus(d.Status),
+	}
+
+	if s.Model == "" {
+		s.Model = d.Model
+	}
+
+	for _, c := range chassis {
+		s.Links.Chassis = append(s.Links.Chassis, Link{ODataID: c})
+	}
+
+	s.Links.ManagedBy = []Link{{ODataID: manager}}
+
+	if d.BIOS != nil && d.BIOS.Firmware != nil {
+		s.BiosVersion = d.BIOS.Firmware.Installed
+
+		s.Bios = &Link{ODataID: id + "/Bios"}
+		e.add(s.Bios.ODataID, &Resource{
+			ODataID:   s.Bios.ODataID,
+			ODataType: "#Bios.v1_1_0.Bios",
+			ID:        "Bios",
+			Name:      "BIOS Configuration Current Settings",
+		})
+
+		e.firmware("BIOS", "BIOS", d.BIOS.Firmware, s.Bios.ODataID)
+	}
+
+	s.Processors = e.processors(id + "/Processors")
+	s.Memory = e.memory(id + "/Memory")
+	s.EthernetInterfaces = e.ethernetInterfaces(id + "/EthernetInterfaces")
+	s.Storage = e.storage(id + "/Storage")
+
+	e.add(id, s)
+}
+
+func (e *exporter) processors(id string) *Link {
+	members := []string{}
+
+	for idx, c := range e.device.CPUs {
+		if c == nil {
+			continue
+		}
+
+		p := &Processor{
+			Resource:          resource(id, e.resourceID(id, c.ID, "CPU."+strconv.Itoa(idx+1)), "#Processor.v1_5_0.Processor", "Processor"),
+			Socket:            c.Slot,
+			ProcessorType:     "CPU",
+			Manufacturer:      c.Vendor,
+			Model:             c.Model,
+			SerialNumber:      c.Serial,
+			PartNumber:        c.Metadata["part_number"],
+			OperatingSpeedMHz: c.ClockSpeedHz / 1000000,
+			TotalCores:        c.Cores,
+			TotalThreads:      c.Threads,
+			Status:            redfishStatus(c.Status),
+		}
+
+		if c.Architecture == "x86_64" {
+			p.ProcessorArchitecture, p.InstructionSet = "x86", "x86-64"
+		} else {
+			p.ProcessorArchitecture = c.Architecture
+		}
+
+		members = append(members, p.ODataID)
+		e.add(p.ODataID, p)
+		e.firmware("CPU."+p.ID, p.Name, c.Firmware, p.ODataID)
+	}
+
+	for idx, g := range e.device.GPUs {
+		if g == nil {
+			continue
+		}
+
+		p := &Processor{
+			Resource:      resource(id, "GPU."+strconv.Itoa(idx+1), "#Processor.v1_5_0.Processor", "GPU"),
+			ProcessorType: "GPU",
+			Manufacturer:  g.Vendor,
+			Model:         g.Model,
+			SerialNumber:  g.Serial,
+			Status:        redfishStatus(g.Status),
+		}
+
+		members = append(members, p.ODataID)
+		e.add(p.ODataID, p)
+		e.firmware(p.ID, p.Name, g.Firmware, p.ODataID)
+	}
+
+	return e.collection(id, "Processor", "Processors Collection", members)
+}
+
+func (e *exporter) memory(id string) *Link {
+	members := []string{}
+
+	for idx, m := range e.device.Memory {
+		if m == nil {
+			continue
+		}
+
+		r := &Memory{
+			Resource:          resource(id, e.resourceID(id, m.ID, "DIMM."+strconv.Itoa(idx+1)), "#Memory.v1_7_1.Memory", "Memory"),
+			DeviceLocator:     m.Slot,
+			MemoryDeviceType:  m.Type,
+			BaseModuleType:    m.FormFactor,
+			CapacityMiB:       m.SizeBytes >> 20,
+			OperatingSpeedMhz: m.ClockSpeedHz / 1000000,
+			Manufacturer:      m.Vendor,
+			SerialNumber:      m.Serial,
+			PartNumber:        m.PartNumber,
+			Status:            redfishStatus(m.Status),
+		}
+
+		members = append(members, r.ODataID)
+		e.add(r.ODataID, r)
+	}
+
+	return e.collection(id, "Memory", "Memory Collection", members)
+}
+
+func (e *exporter) ethernetInterfaces(id string) *Link {
+	members := []string{}
+
+	for nicIdx, nic := range e.device.NICs {
+		if nic == nil {
+			continue
+		}
+
+		// the NIC firmware is related to the first port, the NIC has no resource of its own
+		related := ""
+
+		for portIdx, port := range nic.NICPorts {
+			if port == nil {
+				continue
+			}
+
+			r := e.ethernetInterface(id, e.resourceID(id, port.ID, fmt.Sprintf("NIC.%d-%d-1", nicIdx+1, portIdx+1)), port)
+
+			if related == "" {
+				related = r.ODataID
+			}
+
+			members = append(members, r.ODataID)
+			e.add(r.ODataID, r)
+		}
+
+		if related != "" {
+			e.firmware("NIC."+strconv.Itoa(nicIdx+1), "NIC "+nic.ID, nic.Firmware, related)
+		}
+	}
+
+	return e.collection(id, "EthernetInterface", "Ethernet Network Interface Collection", members)
+}
+
+func (e *exporter) ethernetInterface(collection, id string, p *common.NICPort) *EthernetInterface {
+	r := &EthernetInterface{
+		MACAddress:          p.MacAddress,
+		PermanentMACAddress: p.MacAddress,
+		SpeedMbps:           p.SpeedBits / 1000000,
+		AutoNeg:             p.AutoNeg,
+		MTUSize:             p.MTUSize,
+		LinkStatus:          p.LinkStatus,
+		Status:              redfishStatus(p.Status),
+	}
+
+	r.Resource = resource(collection, id, "#EthernetInterface.v1_4_1.EthernetInterface", "Ethernet Interface")
+	r.Description = p.Description
+
+	return r
+}
+
+// storage adds a Storage resource for each storage controller with the drives and hardware RAID volumes
+// attached, drives not attached to a storage controller are listed in a Storage resource without controllers.
+func (e *exporter) storage(id string) *Link {
+	members := []string{}
+
+	attached := map[*common.Drive]bool{}
+
+	for idx, sc := range e.device.StorageControllers {
+		if sc == nil {
+			continue
+		}
+
+		storage := &Storage{
+			Resource: resource(id, e.resourceID(id, sc.ID, "Storage."+strconv.Itoa(idx+1)), "#Storage.v1_7_1.Storage", "Storage"),
+		}
+
+		storage.StorageControllers = []StorageController{{
+			ODataID:                      storage.ODataID + "#/StorageControllers/0",
+			MemberID:                     "0",
+			Name:                         sc.Description,
+			Manufacturer:                 sc.Vendor,
+			Model:                        sc.Model,
+			SerialNumber:                 sc.Serial,
+			SpeedGbps:                    float64(sc.SpeedGbps),
+			SupportedControllerProtocols: split(sc.SupportedControllerProtocols),
+			SupportedDeviceProtocols:     split(sc.SupportedDeviceProtocols),
+			SupportedRAIDTypes:           split(sc.SupportedRAIDTypes),
+			Status:                       redfishStatus(sc.Status),
+		}}
+
+		if sc.Firmware != nil {
+			storage.StorageControllers[0].FirmwareVersion = sc.Firmware.Installed
+		}
+
+		var drives []*common.Drive
+
+		for _, d := range e.device.Drives {
+			if d != nil && d.StorageController != "" && d.StorageController == sc.ID {
+				drives = append(drives, d)
+				attached[d] = true
+			}
+		}
+
+		driveLinks := e.drives(storage, drives)
+		storage.Volumes = e.volumes(storage.ODataID+"/Volumes", sc.ID, driveLinks)
+
+		members = append(members, storage.ODataID)
+		e.add(storage.ODataID, storage)
+		e.firmware("StorageController."+storage.ID, sc.Model, sc.Firmware, storage.ODataID)
+	}
+
+	var unattached []*common.Drive
+
+	for _, d := range e.device.Drives {
+		if d != nil && !attached[d] {
+			unattached = append(unattached, d)
+		}
+	}
+
+	if len(unattached) > 0 {
+		storage := &Storage{
+			Resource: resource(id, "Storage.Unattached", "#Storage.v1_7_1.Storage", "Storage"),
+		}
+
+		e.drives(storage, unattached)
+
+		members = append(members, storage.ODataID)
+		e.add(storage.ODataID, storage)
+	}
+
+	return e.collection(id, "Storage", "Storage Collection", members)
+}
+
+// drives adds the Drive resources of the storage and returns their links by Drive ID
+func (e *exporter) drives(storage *Storage, drives []*common.Drive) map[string]string {
+	links := map[string]string{}
+
+	for idx, d := range drives {
+		r := &Drive{
+			Resource:           resource(storage.ODataID+"/Drives", e.resourceID(storage.ODataID+"/Drives", d.ID, "Disk."+strconv.Itoa(idx)), "#Drive.v1_9_0.Drive", "Drive"),
+			Manufacturer:       d.Vendor,
+			Model:              d.Model,
+			SerialNumber:       d.Serial,
+			CapacityBytes:      d.CapacityBytes,
+			BlockSizeBytes:     d.BlockSizeBytes,
+			Protocol:           d.Protocol,
+			MediaType:          mediaType(d),
+			CapableSpeedGbs:    float64(d.CapableSpeedGbps),
+			NegotiatedSpeedGbs: float64(d.NegotiatedSpeedGbps),
+			Status:             redfishStatus(d.Status),
+		}
+
+		if r.Protocol == "" && d.Type == common.SlugDriveTypePCIeNVMEeSSD {
+			r.Protocol = "NVMe"
+		}
+
+		if d.Firmware != nil {
+			r.Revision = d.Firmware.Installed
+		}
+
+		switch d.SmartStatus {
+		case common.SmartStatusOK:
+			r.FailurePredicted = new(bool)
+		case common.SmartStatusFailed:
+			failed := true
+			r.FailurePredicted = &failed
+		}
+
+		if d.WWN != "" {
+			format := "NAA"
+			if strings.HasPrefix(d.WWN, "eui.") {
+				format = "EUI"
+			}
+
+			r.Identifiers = []ident{{DurableName: d.WWN, DurableNameFormat: format}}
+		}
+
+		storage.Drives = append(storage.Drives, Link{ODataID: r.ODataID})
+		links[d.ID] = r.ODataID
+
+		e.add(r.ODataID, r)
+		e.firmware("Drive."+storage.ID+"."+r.ID, d.Model, d.Firmware, r.ODataID)
+	}
+
+	return links
+}
+
+// volumes adds the hardware RAID volumes of the storage controller
+func (e *exporter) volumes(id, controllerID string, drives map[string]string) *Link {
+	members := []string{}
+
+	for idx, vd := range e.device.VirtualDisks {
+		if vd == nil {
+			continue
+		}
+
+		if vd.StorageController == "" || vd.StorageController != controllerID {
+			continue
+		}
+
+		v := &Volume{
+			Resource:      resource(id, e.resourceID(id, vd.ID, "Volume."+strconv.Itoa(idx)), "#Volume.v1_5_0.Volume", vd.Name),
+			RAIDType:      vd.RaidType,
+			CapacityBytes: vd.SizeBytes,
+		}
+
+		if vd.Status != "" {
+			v.Status = &Status{Health: vd.Status}
+		}
+
+		for _, driveID := range vd.DriveIDs {
+			if link, exists := drives[driveID]; exists {
+				v.Links.Drives = append(v.Links.Drives, Link{ODataID: link})
+			}
+		}
+
+		members = append(members, v.ODataID)
+		e.add(v.ODataID, v)
+	}
+
+	return e.collection(id, "Volume", "Volume Collection", members)
+}
+
+// chassis adds a Chassis resource for each Enclosure and returns their @odata.ids,
+// the first is the chassis enclosing the system.
+func (e *exporter) chassis(id, systemID string) []string {
+	d := e.device
+
+	enclosures := d.Enclosures
+	if len(enclosures) == 0 {
+		enclosures = []*common.Enclosure{{Common: common.Common{Vendor: d.Vendor, Model: d.ProductName, Serial: d.Serial}}}
+	}
+
+	members := []string{}
+
+	for idx, enc := range enclosures {
+		if enc == nil {
+			continue
+		}
+
+		c := &Chassis{
+			Resource:     resource(id, e.resourceID(id, enc.ID, strconv.Itoa(idx+1)), "#Chassis.v1_9_1.Chassis", "Chassis"),
+			ChassisType:  enc.ChassisType,
+			Manufacturer: enc.Vendor,
+			Model:        enc.Model,
+			SerialNumber: enc.Serial,
+			Status:       redfishStatus(enc.Status),
+		}
+
+		c.Links.ComputerSystems = []Link{{ODataID: systemID}}
+
+		if idx == 0 {
+			c.ChassisType = chassisType(d.Chassis, enc.ChassisType)
+			c.Oem = supermicroOem(d)
+			c.Power = e.power(c.ODataID + "/Power")
+		}
+
+		members = append(members, c.ODataID)
+		e.add(c.ODataID, c)
+		e.firmware("Enclosure."+c.ID, "Enclosure "+c.ID, enc.Firmware, c.ODataID)
+	}
+
+	return members
+}
+
+// chassisType returns the Redfish ChassisType for the Device Chassis
+func chassisType(chassis, fallback string) string {
+	for _, t := range chassisTypes {
+		if strings.EqualFold(t, chassis) {
+			return t
+		}
+	}
+
+	if fallback != "" {
+		return fallback
+	}
+
+	return "Other"
+}
+
+// supermicroOem returns the Supermicro Chassis OEM properties with the mainboard BoardID
+func supermicroOem(d *common.Device) json.RawMessage {
+	if d.Mainboard == nil || d.Mainboard.Metadata["board_id"] == "" {
+		return nil
+	}
+
+	oem := &supermicroChassisOem{}
+	oem.Supermicro.BoardID = d.Mainboard.Metadata["board_id"]
+
+	data, _ := json.Marshal(oem)
+
+	return data
+}
+
+func (e *exporter) power(id string) *Link {
+	if len(e.device.PSUs) == 0 {
+		return nil
+	}
+
+	p := &Power{Resource: resource(strings.TrimSuffix(id, "/Power"), "Power", "#Power.v1_5_4.Power", "Power")}
+
+	for idx, psu := range e.device.PSUs {
+		if psu == nil {
+			continue
+		}
+
+		ps := PowerSupply{
+			ODataID:            id + "#/PowerSupplies/" + strconv.Itoa(idx),
+			MemberID:           psu.ID,
+			Name:               psu.Description,
+			Manufacturer:       psu.Vendor,
+			Model:              psu.Model,
+			SerialNumber:       psu.Serial,
+			PowerCapacityWatts: float64(psu.PowerCapacityWatts),
+			Status:             redfishStatus(psu.Status),
+		}
+
+		if ps.MemberID == "" {
+			ps.MemberID = strconv.Itoa(idx)
+		}
+
+		if psu.Firmware != nil {
+			ps.FirmwareVersion = psu.Firmware.Installed
+		}
+
+		p.PowerSupplies = append(p.PowerSupplies, ps)
+	}
+
+	e.add(id, p)
+
+	return &Link{ODataID: id}
+}
+
+func (e *exporter) manager(id, systemID string, chassis []string) string {
+	d := e.device
+
+	m := &Manager{
+		Resource:    resource(id, "1", "#Manager.v1_5_1.Manager", "Manager"),
+		ManagerType: "BMC",
+	}
+
+	m.Links.ManagerForServers = []Link{{ODataID: systemID}}
+	for _, c := range chassis {
+		m.Links.ManagerForChassis = append(m.Links.ManagerForChassis, Link{ODataID: c})
+	}
+
+	members := []string{}
+
+	if bmc := d.BMC; bmc != nil {
+		m.Resource = resource(id, e.resourceID(id, bmc.ID, "1"), m.ODataType, m.Name)
+		m.Manufacturer = bmc.Vendor
+		m.Model = bmc.Model
+		m.SerialNumber = bmc.Serial
+		m.Status = redfishStatus(bmc.Status)
+
+		if bmc.Firmware != nil {
+			m.FirmwareVersion = bmc.Firmware.Installed
+		}
+
+		if bmc.NIC != nil {
+			for idx, port := range bmc.NIC.NICPorts {
+				if port == nil {
+					continue
+				}
+
+				r := e.ethernetInterface(m.ODataID+"/EthernetInterfaces", e.resourceID(m.ODataID+"/EthernetInterfaces", port.ID, strconv.Itoa(idx+1)), port)
+
+				members = append(members, r.ODataID)
+				e.add(r.ODataID, r)
+			}
+		}
+
+		e.firmware("BMC", "BMC", bmc.Firmware, m.ODataID)
+	}
+
+	m.EthernetInterfaces = e.collection(m.ODataID+"/EthernetInterfaces", "EthernetInterface", "Ethernet Network Interface Collection", members)
+
+	e.add(m.ODataID, m)
+
+	for idx, cpld := range d.CPLDs {
+		if cpld == nil {
+			continue
+		}
+
+		name := cpld.Description
+		if name == "" {
+			name = "CPLD"
+		}
+
+		// the CPLD has no resource of its own and is related to the chassis enclosing the system
+		related := ""
+		if len(chassis) > 0 {
+			related = chassis[0]
+		}
+
+		e.firmware("CPLD."+strconv.Itoa(idx+1), name, cpld.Firmware, related)
+	}
+
+	return m.ODataID
+}
+
+// resource returns the Resource with the given Id in the collection
+func resource(collection, id, odataType, name string) Resource {
+	return Resource{
+		ODataID:   collection + "/" + id,
+		ODataType: odataType,
+		ID:        id,
+		Name:      name,
+	}
+}
+
+// resourceID returns the component ID when usable as the Id of a Redfish resource not yet in the
+// collection, otherwise the fallback. Component IDs such as the NIC port IDs are not unique across
+// the components of a Device.
+func (e *exporter) resourceID(collection, id, fallback string) string {
+	if _, exists := e.resources[collection+"/"+id]; !exists && resourceIDChars.MatchString(id) {
+		return id
+	}
+
+	return fallback
+}
+
+func mediaType(d *common.Drive) string {
+	switch d.Type {
+	case common.SlugDriveTypePCIeNVMEeSSD, common.SlugDriveTypeSATASSD:
+		return "SSD"
+	case common.SlugDriveTypeSATAHDD:
+		return "HDD"
+	default:
+		return ""
+	}
+}
+
+// split returns the values of the comma separated list
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	values := strings.Split(s, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	return values
+}
+
+func redfishStatus(s *common.Status) *Status {
+	if s == nil {
+		return nil
+	}
+
+	return &Status{Health: s.Health, State: s.State}
+}
@@ -0,0 +1,321 @@
+package redfish
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	testcases := []struct {
+		name string
+		src  func(t *testing.T) Source
+	}{
+		{"dell", func(t *testing.T) Source { return mapSourceFixture(t, "dell_r640.json") }},
+		{"supermicro", func(t *testing.T) Source { return DirSource("testdata/supermicro_x11scz-f") }},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			device, err := Import(tc.src(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			exported, err := Export(device)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dir := t.TempDir()
+			if err := exported.WriteDir(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, src := range []Source{exported, DirSource(dir)} {
+				imported, err := Import(src)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if changes := common.Diff(device, imported); !changes.Empty() {
+					for _, c := range changes.Changes {
+						t.Error(c)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestExportLinks verifies every @odata.id linked from an exported resource is exported
+func TestExportLinks(t *testing.T) {
+	device, err := Import(mapSourceFixture(t, "dell_r640.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := Export(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var walk func(id string, v interface{})
+	walk = func(id string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				if link, ok := value.(string); ok && key == "@odata.id" {
+					if _, err := exported.Get(link); err != nil {
+						t.Errorf("%s links to %s: %s", id, link, err)
+					}
+
+					continue
+				}
+
+				walk(id, value)
+			}
+		case []interface{}:
+			for _, value := range v {
+				walk(id, value)
+			}
+		}
+	}
+
+	for id, data := range exported {
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+
+		if v.(map[string]interface{})["@odata.id"] != id {
+			t.Errorf("resource %s has @odata.id %v", id, v.(map[string]interface{})["@odata.id"])
+		}
+
+		walk(id, v)
+	}
+}
+
+func TestExportMinimalDevice(t *testing.T) {
+	d := common.NewDevice()
+	d.Vendor = common.VendorSupermicro
+	d.Chassis = "rackmount"
+	d.Mainboard = &common.Mainboard{Common: common.Common{Metadata: map[string]string{"board_id": "0x89a"}}}
+
+	exported, err := Export(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chassis := &Chassis{}
+	if err := get(exported, "/redfish/v1/Chassis/1", chassis); err != nil {
+		t.Fatal(err)
+	}
+
+	if chassis.ChassisType != "RackMount" {
+		t.Errorf("expected chassis type RackMount, got %s", chassis.ChassisType)
+	}
+
+	imported, err := Import(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported.Mainboard == nil || imported.Mainboard.Model != "x11ssl-f" {
+		t.Errorf("expected mainboard x11ssl-f from the exported BoardID, got %+v", imported.Mainboard)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	device, err := ImportDir("testdata/supermicro_x11scz-f")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := Export(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(Handler(exported))
+	defer srv.Close()
+
+	testcases := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/redfish/v1", http.StatusOK},
+		{http.MethodGet, "/redfish/v1/Systems/1/", http.StatusOK},
+		{http.MethodGet, "/redfish/v1/Systems/2", http.StatusNotFound},
+		{http.MethodPost, "/redfish/v1/Systems", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range testcases {
+		req, err := http.NewRequest(tc.method, srv.URL+tc.path, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestExportNil(t *testing.T) {
+	if _, err := Export(nil); !errors.Is(err, errNilDevice) {
+		t.Errorf("expected errNilDevice, got %v", err)
+	}
+
+	d := common.NewDevice()
+	d.CPUs = []*common.CPU{nil}
+	d.GPUs = []*common.GPU{nil}
+	d.Memory = []*common.Memory{nil}
+	d.NICs = []*common.NIC{nil, {ID: "NIC.Slot.1", NICPorts: []*common.NICPort{nil}}}
+	d.StorageControllers = []*common.StorageController{nil}
+	d.Drives = []*common.Drive{nil}
+	d.VirtualDisks = []*common.VirtualDisk{nil}
+	d.Enclosures = []*common.Enclosure{nil, {ID: "System.Embedded.1"}}
+	d.PSUs = []*common.PSU{nil}
+	d.CPLDs = []*common.CPLD{nil}
+	d.BMC = &common.BMC{NIC: &common.NIC{NICPorts: []*common.NICPort{nil}}}
+
+	if _, err := Export(&d); err != nil {
+		t.Fatal(err)
+	}
+
+	d = common.NewDevice()
+	d.Enclosures = []*common.Enclosure{nil}
+	d.CPLDs = []*common.CPLD{{Common: common.Common{Firmware: &common.Firmware{Installed: "1.0.6"}}}}
+
+	exported, err := Export(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sw := &SoftwareInventory{}
+	if err := get(exported, "/redfish/v1/UpdateService/FirmwareInventory/CPLD.1", sw); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sw.RelatedItem) != 0 {
+		t.Errorf("expected no related item without a chassis, got %v", sw.RelatedItem)
+	}
+}
+
+func TestExportDuplicateIDs(t *testing.T) {
+	d := common.NewDevice()
+	d.NICs = []*common.NIC{
+		{ID: "NIC.Slot.1", NICPorts: []*common.NICPort{{ID: "1", MacAddress: "e4:43:4b:00:00:01"}}},
+		{ID: "NIC.Slot.2", NICPorts: []*common.NICPort{{ID: "1", MacAddress: "e4:43:4b:00:00:02"}}},
+	}
+	d.CPUs = []*common.CPU{{ID: "CPU"}, {ID: "CPU"}}
+
+	exported, err := Export(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"/redfish/v1/Systems/1/EthernetInterfaces", "/redfish/v1/Systems/1/Processors"} {
+		c := &Collection{}
+		if err := get(exported, id, c); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(c.Members) != 2 || c.Members[0] == c.Members[1] {
+			t.Errorf("expected 2 distinct members of %s, got %v", id, c.Members)
+		}
+	}
+
+	imported, err := Import(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	macs := map[string]bool{}
+
+	for _, nic := range imported.NICs {
+		for _, p := range nic.NICPorts {
+			macs[p.MacAddress] = true
+		}
+	}
+
+	if !macs["e4:43:4b:00:00:01"] || !macs["e4:43:4b:00:00:02"] {
+		t.Errorf("expected the MAC addresses of both NIC ports, got %v", macs)
+	}
+
+	if len(imported.CPUs) != 2 {
+		t.Errorf("expected 2 CPUs, got %d", len(imported.CPUs))
+	}
+}
+
+func TestExportFirmwareRoundTrip(t *testing.T) {
+	fw := func(version string) *common.Firmware {
+		f := common.NewFirmwareObj()
+		f.Installed = version
+
+		return f
+	}
+
+	d := common.NewDevice()
+	d.Vendor = common.VendorDell
+	d.NICs = []*common.NIC{
+		{
+			ID:     "NIC.Integrated.1",
+			Common: common.Common{Firmware: fw("22.31.6")},
+			NICPorts: []*common.NICPort{
+				{ID: "NIC.Integrated.1-1-1", MacAddress: "e4:43:4b:00:00:01"},
+				{ID: "NIC.Integrated.1-2-1", MacAddress: "e4:43:4b:00:00:02"},
+			},
+		},
+	}
+	d.CPLDs = []*common.CPLD{{Common: common.Common{Description: "System CPLD", Vendor: common.VendorDell, Firmware: fw("1.0.6")}}}
+
+	exported, err := Export(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, related := range map[string]string{
+		"NIC.1":  "/redfish/v1/Systems/1/EthernetInterfaces/NIC.Integrated.1-1-1",
+		"CPLD.1": "/redfish/v1/Chassis/1",
+	} {
+		sw := &SoftwareInventory{}
+		if err := get(exported, "/redfish/v1/UpdateService/FirmwareInventory/"+id, sw); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(sw.RelatedItem) != 1 || sw.RelatedItem[0].ODataID != related {
+			t.Errorf("expected %s related to %s, got %v", id, related, sw.RelatedItem)
+		}
+	}
+
+	imported, err := Import(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(imported.NICs) != 1 || imported.NICs[0].Firmware == nil || imported.NICs[0].Firmware.Installed != "22.31.6" {
+		t.Errorf("expected the NIC firmware 22.31.6, got %+v", imported.NICs)
+	}
+
+	if len(imported.CPLDs) != 1 || imported.CPLDs[0].Firmware == nil || imported.CPLDs[0].Firmware.Installed != "1.0.6" {
+		t.Errorf("expected the CPLD firmware 1.0.6, got %+v", imported.CPLDs)
+	}
+
+	if len(imported.NICs[0].NICPorts) != 2 || imported.NICs[0].NICPorts[0].Firmware != nil {
+		t.Errorf("expected 2 NIC ports without firmware, got %+v", imported.NICs[0].NICPorts)
+	}
+}
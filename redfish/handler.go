@@ -0,0 +1,40 @@
+package redfish
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Handler returns an http.Handler serving the Redfish resources of the Source by their @odata.id,
+// for example to serve the resources returned by Export as a Redfish mock.
+func Handler(src Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		data, err := src.Get(r.URL.Path)
+		if err != nil {
+			if errors.Is(err, errResourceNotFound) {
+				http.NotFound(w, r)
+				return
+			}
+
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("OData-Version", "4.0")
+
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		_, _ = w.Write(data)
+	})
+}
@@ -0,0 +1,839 @@
+package redfish
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// ServiceRootID is the @odata.id of the Redfish service root
+const ServiceRootID = "/redfish/v1"
+
+var errNoSystems = errors.New("redfish service lists no computer systems")
+
+// Import returns the Device described by the Redfish resources in the given Source.
+//
+// The first ComputerSystem listed under the service root is imported along with the Chassis and
+// Managers it links to, and the UpdateService FirmwareInventory. Linked collections missing from
+// the Source are skipped, so partial captures of a BMC still import.
+func Import(src Source) (*common.Device, error) {
+	i := &importer{
+		src:        src,
+		components: map[string]*common.Common{},
+		driveIDs:   map[string]string{},
+		storages:   map[string]string{},
+	}
+
+	return i.run()
+}
+
+// ImportDir returns the Device described by the Redfish resources saved to the given directory,
+// see DirSource for the expected layout.
+func ImportDir(dir string) (*common.Device, error) {
+	return Import(DirSource(dir))
+}
+
+type importer struct {
+	src    Source
+	device *common.Device
+
+	// components maps the @odata.id of the imported resources to the component populated,
+	// to set the firmware listed in the FirmwareInventory with a RelatedItem link.
+	components map[string]*common.Common
+
+	// driveIDs maps the Drive @odata.id to the Drive ID, for the Volume drive links
+	driveIDs map[string]string
+
+	// storages maps the Storage @odata.id to the ID of its first StorageController, for the
+	// StorageController of the drives and volumes of the Storage.
+	storages map[string]string
+}
+
+func (i *importer) run() (*common.Device, error) {
+	root := &ServiceRoot{}
+	if err := get(i.src, ServiceRootID, root); err != nil {
+		return nil, err
+	}
+
+	systems, err := i.members(root.Systems)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(systems) == 0 {
+		return nil, errNoSystems
+	}
+
+	system := &ComputerSystem{}
+	if err := get(i.src, systems[0], system); err != nil {
+		return nil, err
+	}
+
+	d := common.NewDevice()
+	i.device = &d
+
+	i.setSystem(system)
+
+	steps := []func(*ComputerSystem) error{
+		i.addProcessors,
+		i.addMemory,
+		i.addEthernetInterfaces,
+		i.addStorage,
+	}
+
+	for _, step := range steps {
+		if err := step(system); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := i.addChassis(root, system); err != nil {
+		return nil, err
+	}
+
+	if err := i.setManager(root, system); err != nil {
+		return nil, err
+	}
+
+	if err := i.setFirmwareInventory(root); err != nil {
+		return nil, err
+	}
+
+	return i.device, nil
+}
+
+// members returns the member @odata.ids of the linked collection,
+// a nil link or a collection missing from the Source has no members.
+func (i *importer) members(link *Link) ([]string, error) {
+	if link == nil || link.ODataID == "" {
+		return nil, nil
+	}
+
+	collection := &Collection{}
+	if err := get(i.src, link.ODataID, collection); err != nil {
+		if errors.Is(err, errResourceNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(collection.Members))
+	for _, m := range collection.Members {
+		ids = append(ids, m.ODataID)
+	}
+
+	return ids, nil
+}
+
+// each decodes the members of the linked collection into a new value returned by newFn,
+// and calls fn with it.
+func (i *importer) each(link *Link, newFn func() interface{}, fn func(v interface{})) error {
+	ids, err := i.members(link)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		v := newFn()
+		if err := get(i.src, id, v); err != nil {
+			if errors.Is(err, errResourceNotFound) {
+				continue
+			}
+
+			return err
+		}
+
+		fn(v)
+	}
+
+	return nil
+}
+
+func (i *importer) setSystem(s *ComputerSystem) {
+	d := i.device
+
+	d.Vendor = vendor(s.Manufacturer)
+	d.Model = common.FormatProductName(value(s.Model))
+	d.ProductName = value(s.Model)
+	d.Serial = value(s.SerialNumber)
+	d.Status = status(s.Status)
+	d.Metadata = map[string]string{}
+
+	for key, v := range map[string]string{
+		"uuid":        s.UUID,
+		"sku":         s.SKU,
+		"part_number": s.PartNumber,
+		"power_state": s.PowerState,
+	} {
+		if v = value(v); v != "" {
+			d.Metadata[key] = v
+		}
+	}
+
+	d.BIOS = &common.BIOS{
+		Common: common.Common{
+			Vendor:   d.Vendor,
+			Firmware: firmware(s.BiosVersion),
+		},
+	}
+
+	if s.Bios != nil {
+		i.components[cleanODataID(s.Bios.ODataID)] = &d.BIOS.Common
+	}
+}
+
+func (i *importer) addProcessors(s *ComputerSystem) error {
+	return i.each(s.Processors, func() interface{} { return &Processor{} }, func(v interface{}) {
+		p := v.(*Processor)
+
+		if isAbsent(p.Status) {
+			return
+		}
+
+		c := common.Common{
+			Vendor:      vendor(p.Manufacturer),
+			Model:       value(p.Model),
+			ProductName: value(p.Model),
+			Serial:      value(p.SerialNumber),
+			Status:      status(p.Status),
+			Metadata:    map[string]string{},
+		}
+
+		if pn := value(p.PartNumber); pn != "" {
+			c.Metadata["part_number"] = pn
+		}
+
+		switch strings.ToUpper(p.ProcessorType) {
+		case "", "CPU":
+			cpu := &common.CPU{
+				Common:       c,
+				ID:           p.ID,
+				Slot:         value(p.Socket),
+				Architecture: architecture(p),
+				ClockSpeedHz: p.OperatingSpeedMHz * 1000000,
+				Cores:        p.TotalCores,
+				Threads:      p.TotalThreads,
+			}
+
+			if cpu.ClockSpeedHz == 0 {
+				cpu.ClockSpeedHz = p.MaxSpeedMHz * 1000000
+			}
+
+			i.device.CPUs = append(i.device.CPUs, cpu)
+			i.components[cleanODataID(p.ODataID)] = &cpu.Common
+		case "GPU":
+			gpu := &common.GPU{Common: c}
+
+			i.device.GPUs = append(i.device.GPUs, gpu)
+			i.components[cleanODataID(p.ODataID)] = &gpu.Common
+		}
+	})
+}
+
+func architecture(p *Processor) string {
+	switch strings.ToLower(p.InstructionSet) {
+	case "x86-64":
+		return "x86_64"
+	case "":
+		return strings.ToLower(p.ProcessorArchitecture)
+	default:
+		return strings.ToLower(p.InstructionSet)
+	}
+}
+
+func (i *importer) addMemory(s *ComputerSystem) error {
+	return i.each(s.Memory, func() interface{} { return &Memory{} }, func(v interface{}) {
+		m := v.(*Memory)
+
+		if isAbsent(m.Status) || m.CapacityMiB == 0 {
+			return
+		}
+
+		pn := value(m.PartNumber)
+
+		mem := &common.Memory{
+			Common: common.Common{
+				Vendor:      vendor(m.Manufacturer),
+				Model:       pn,
+				ProductName: pn,
+				Serial:      value(m.SerialNumber),
+				Status:      status(m.Status),
+			},
+			ID:           m.ID,
+			Slot:         value(m.DeviceLocator),
+			Type:         m.MemoryDeviceType,
+			FormFactor:   m.BaseModuleType,
+			SizeBytes:    m.CapacityMiB << 20,
+			PartNumber:   pn,
+			ClockSpeedHz: m.OperatingSpeedMhz * 1000000,
+		}
+
+		i.device.Memory = append(i.device.Memory, mem)
+		i.components[cleanODataID(m.ODataID)] = &mem.Common
+	})
+}
+
+// dellNICPort matches the Dell FQDD of a NIC port, "NIC.Integrated.1-2-1" is port 2 of NIC.Integrated.1
+var dellNICPort = regexp.MustCompile(`^(NIC\.[A-Za-z]+\.\d+)-\d+`)
+
+func (i *importer) addEthernetInterfaces(s *ComputerSystem) error {
+	nics := map[string]*common.NIC{}
+
+	return i.each(s.EthernetInterfaces, func() interface{} { return &EthernetInterface{} }, func(v interface{}) {
+		e := v.(*EthernetInterface)
+
+		// ports of the same adapter are grouped on the NIC when the port IDs identify the adapter,
+		// otherwise each interface is listed as a NIC with a single port.
+		nicID := e.ID
+		if m := dellNICPort.FindStringSubmatch(e.ID); m != nil {
+			nicID = m[1]
+		}
+
+		nic, exists := nics[nicID]
+		if !exists {
+			nic = &common.NIC{
+				Common: common.Common{Description: e.Description},
+				ID:     nicID,
+			}
+
+			nics[nicID] = nic
+			i.device.NICs = append(i.device.NICs, nic)
+		}
+
+		nic.NICPorts = append(nic.NICPorts, nicPort(e))
+
+		// the firmware related to an interface is the firmware of the adapter
+		i.components[cleanODataID(e.ODataID)] = &nic.Common
+	})
+}
+
+func nicPort(e *EthernetInterface) *common.NICPort {
+	mac := e.PermanentMACAddress
+	if mac == "" {
+		mac = e.MACAddress
+	}
+
+	return &common.NICPort{
+		Common: common.Common{
+			Description: e.Description,
+			Status:      status(e.Status),
+		},
+		ID:         e.ID,
+		MacAddress: strings.ToLower(mac),
+		SpeedBits:  e.SpeedMbps * 1000000,
+		LinkStatus: e.LinkStatus,
+		AutoNeg:    e.AutoNeg,
+		MTUSize:    e.MTUSize,
+	}
+}
+
+func (i *importer) addStorage(s *ComputerSystem) error {
+	ids, err := i.members(s.Storage)
+	if err != nil {
+		return err
+	}
+
+	storages := []*Storage{}
+
+	for _, id := range ids {
+		storage := &Storage{}
+		if err := get(i.src, id, storage); err != nil {
+			if errors.Is(err, errResourceNotFound) {
+				continue
+			}
+
+			return err
+		}
+
+		storages = append(storages, storage)
+	}
+
+	// the controllers are listed first, a Storage may list the drives of another Storage
+	for _, storage := range storages {
+		i.addStorageControllers(storage)
+	}
+
+	for _, storage := range storages {
+		if err := i.addStorageResource(storage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *importer) addStorageControllers(s *Storage) {
+	for idx := range s.StorageControllers {
+		sc := &s.StorageControllers[idx]
+
+		id := sc.MemberID
+		if id == "" || len(s.StorageControllers) == 1 {
+			id = s.ID
+		}
+
+		if _, exists := i.storages[cleanODataID(s.ODataID)]; !exists {
+			i.storages[cleanODataID(s.ODataID)] = id
+		}
+
+		controller := &common.StorageController{
+			Common: common.Common{
+				Vendor:      vendor(sc.Manufacturer),
+				Model:       value(sc.Model),
+				ProductName: value(sc.Model),
+				Description: value(sc.Name),
+				Serial:      value(sc.SerialNumber),
+				Firmware:    firmware(sc.FirmwareVersion),
+				Status:      status(sc.Status),
+			},
+			ID:                           id,
+			SupportedControllerProtocols: strings.Join(sc.SupportedControllerProtocols, ", "),
+			SupportedDeviceProtocols:     strings.Join(sc.SupportedDeviceProtocols, ", "),
+			SupportedRAIDTypes:           strings.Join(sc.SupportedRAIDTypes, ", "),
+			SpeedGbps:                    int64(sc.SpeedGbps),
+		}
+
+		i.device.StorageControllers = append(i.device.StorageControllers, controller)
+
+		if sc.ODataID != "" {
+			i.components[cleanODataID(sc.ODataID)] = &controller.Common
+		}
+	}
+}
+
+// storageController returns the ID of the StorageController of the Storage the resource belongs to,
+// the linked Storage or the Storage holding the resource, defaulting to the Storage listing the resource.
+func (i *importer) storageController(odataID string, storage *Link, listedBy *Storage) string {
+	if storage != nil {
+		if id, exists := i.storages[cleanODataID(storage.ODataID)]; exists {
+			return id
+		}
+	}
+
+	parent := ""
+
+	for storageID := range i.storages {
+		if strings.HasPrefix(cleanODataID(odataID), storageID+"/") && len(storageID) > len(parent) {
+			parent = storageID
+		}
+	}
+
+	if parent != "" {
+		return i.storages[parent]
+	}
+
+	return i.storages[cleanODataID(listedBy.ODataID)]
+}
+
+func (i *importer) addStorageResource(s *Storage) error {
+	for _, link := range s.Drives {
+		// drives listed by several Storage resources are imported once
+		if _, exists := i.driveIDs[cleanODataID(link.ODataID)]; exists {
+			continue
+		}
+
+		drive := &Drive{}
+		if err := get(i.src, link.ODataID, drive); err != nil {
+			if errors.Is(err, errResourceNotFound) {
+				continue
+			}
+
+			return err
+		}
+
+		if isAbsent(drive.Status) {
+			continue
+		}
+
+		var storage *Link
+		if drive.Links != nil {
+			storage = drive.Links.Storage
+		}
+
+		d := newDrive(drive)
+		d.StorageController = i.storageController(drive.ODataID, storage, s)
+
+		i.device.Drives = append(i.device.Drives, d)
+		i.components[cleanODataID(drive.ODataID)] = &d.Common
+		i.driveIDs[cleanODataID(drive.ODataID)] = d.ID
+	}
+
+	return i.each(s.Volumes, func() interface{} { return &Volume{} }, func(v interface{}) {
+		vol := v.(*Volume)
+
+		vd := &common.VirtualDisk{
+			ID:                 vol.ID,
+			Name:               vol.Name,
+			RaidType:           vol.RAIDType,
+			RaidImplementation: common.SlugRAIDImplHardware,
+			StorageController:  i.storageController(vol.ODataID, nil, s),
+			SizeBytes:          vol.CapacityBytes,
+			DriveIDs:           []string{},
+		}
+
+		if vol.Status != nil {
+			vd.Status = vol.Status.Health
+		}
+
+		for _, link := range vol.Links.Drives {
+			if id, exists := i.driveIDs[cleanODataID(link.ODataID)]; exists {
+				vd.DriveIDs = append(vd.DriveIDs, id)
+			}
+		}
+
+		i.device.VirtualDisks = append(i.device.VirtualDisks, vd)
+	})
+}
+
+func newDrive(r *Drive) *common.Drive {
+	d := &common.Drive{
+		Common: common.Common{
+			Vendor:      vendor(r.Manufacturer),
+			Model:       value(r.Model),
+			ProductName: value(r.Model),
+			Serial:      value(r.SerialNumber),
+			Firmware:    firmware(r.Revision),
+			Status:      status(r.Status),
+		},
+		ID:                  r.ID,
+		Protocol:            r.Protocol,
+		CapacityBytes:       r.CapacityBytes,
+		BlockSizeBytes:      r.BlockSizeBytes,
+		CapableSpeedGbps:    int64(r.CapableSpeedGbs),
+		NegotiatedSpeedGbps: int64(r.NegotiatedSpeedGbs),
+		SmartStatus:         common.SmartStatusUnknown,
+	}
+
+	// the manufacturer is commonly left empty or reported as "ATA" for SATA drives
+	if d.Vendor == "" || strings.EqualFold(d.Vendor, "ata") {
+		d.Vendor = common.VendorFromString(d.Model)
+	}
+
+	if r.FailurePredicted != nil {
+		d.SmartStatus = common.SmartStatusOK
+		if *r.FailurePredicted {
+			d.SmartStatus = common.SmartStatusFailed
+		}
+	}
+
+	for _, id := range r.Identifiers {
+		if strings.EqualFold(id.DurableNameFormat, "NAA") || strings.EqualFold(id.DurableNameFormat, "EUI") {
+			d.WWN = strings.ToLower(id.DurableName)
+			break
+		}
+	}
+
+	switch {
+	case strings.EqualFold(r.Protocol, "NVMe"):
+		d.Type = common.SlugDriveTypePCIeNVMEeSSD
+	case strings.EqualFold(r.Protocol, "SATA") && strings.EqualFold(r.MediaType, "SSD"):
+		d.Type = common.SlugDriveTypeSATASSD
+	case strings.EqualFold(r.Protocol, "SATA") && strings.EqualFold(r.MediaType, "HDD"):
+		d.Type = common.SlugDriveTypeSATAHDD
+	}
+
+	return d
+}
+
+// addChassis populates the chassis linked to the system, or listed under the service root
+// when the system does not link any.
+func (i *importer) addChassis(root *ServiceRoot, s *ComputerSystem) error {
+	ids := make([]string, 0, len(s.Links.Chassis))
+	for _, link := range s.Links.Chassis {
+		ids = append(ids, link.ODataID)
+	}
+
+	if len(ids) == 0 {
+		var err error
+
+		ids, err = i.members(root.Chassis)
+		if err != nil {
+			return err
+		}
+	}
+
+	for idx, id := range ids {
+		chassis := &Chassis{}
+		if err := get(i.src, id, chassis); err != nil {
+			if errors.Is(err, errResourceNotFound) {
+				continue
+			}
+
+			return err
+		}
+
+		// the first chassis is the one enclosing the system
+		if idx == 0 {
+			i.setChassis(chassis)
+		}
+
+		i.device.Enclosures = append(i.device.Enclosures, &common.Enclosure{
+			Common: common.Common{
+				Vendor:      vendor(chassis.Manufacturer),
+				Model:       value(chassis.Model),
+				ProductName: value(chassis.Model),
+				Serial:      value(chassis.SerialNumber),
+				Status:      status(chassis.Status),
+			},
+			ID:          chassis.ID,
+			ChassisType: chassis.ChassisType,
+		})
+
+		if err := i.addPowerSupplies(chassis); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *importer) setChassis(c *Chassis) {
+	i.device.Chassis = strings.ToLower(c.ChassisType)
+
+	oem := &supermicroChassisOem{}
+	if len(c.Oem) == 0 || json.Unmarshal(c.Oem, oem) != nil || oem.Supermicro.BoardID == "" {
+		return
+	}
+
+	model := common.SupermicroModelFromBoardID(oem.Supermicro.BoardID)
+	if model == "" {
+		model = oem.Supermicro.BoardID
+	}
+
+	i.device.Mainboard = &common.Mainboard{
+		Common: common.Common{
+			Vendor:      common.VendorSupermicro,
+			Model:       model,
+			ProductName: model,
+			Serial:      value(c.SerialNumber),
+			Metadata:    map[string]string{"board_id": oem.Supermicro.BoardID},
+		},
+	}
+
+	// white box systems leave the system manufacturer unset
+	if i.device.Vendor == "" {
+		i.device.Vendor = common.VendorSupermicro
+	}
+}
+
+func (i *importer) addPowerSupplies(c *Chassis) error {
+	if c.Power == nil {
+		return nil
+	}
+
+	power := &Power{}
+	if err := get(i.src, c.Power.ODataID, power); err != nil {
+		if errors.Is(err, errResourceNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	for idx := range power.PowerSupplies {
+		p := &power.PowerSupplies[idx]
+
+		if isAbsent(p.Status) {
+			continue
+		}
+
+		id := p.MemberID
+		if id == "" {
+			id = p.Name
+		}
+
+		psu := &common.PSU{
+			Common: common.Common{
+				Description: value(p.Name),
+				Vendor:      vendor(p.Manufacturer),
+				Model:       value(p.Model),
+				ProductName: value(p.Model),
+				Serial:      value(p.SerialNumber),
+				Firmware:    firmware(p.FirmwareVersion),
+				Status:      status(p.Status),
+			},
+			ID:                 id,
+			PowerCapacityWatts: int64(p.PowerCapacityWatts),
+		}
+
+		i.device.PSUs = append(i.device.PSUs, psu)
+
+		if p.ODataID != "" {
+			i.components[cleanODataID(p.ODataID)] = &psu.Common
+		}
+	}
+
+	return nil
+}
+
+// setManager populates the BMC from the manager of the system
+func (i *importer) setManager(root *ServiceRoot, s *ComputerSystem) error {
+	var id string
+
+	if len(s.Links.ManagedBy) > 0 {
+		id = s.Links.ManagedBy[0].ODataID
+	} else {
+		ids, err := i.members(root.Managers)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		id = ids[0]
+	}
+
+	m := &Manager{}
+	if err := get(i.src, id, m); err != nil {
+		if errors.Is(err, errResourceNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	bmcVendor := vendor(m.Manufacturer)
+	if bmcVendor == "" {
+		bmcVendor = i.device.Vendor
+	}
+
+	bmc := &common.BMC{
+		Common: common.Common{
+			Vendor:      bmcVendor,
+			Model:       value(m.Model),
+			ProductName: value(m.Model),
+			Serial:      value(m.SerialNumber),
+			Firmware:    firmware(m.FirmwareVersion),
+			Status:      status(m.Status),
+		},
+		ID: m.ID,
+	}
+
+	i.device.BMC = bmc
+	i.components[cleanODataID(m.ODataID)] = &bmc.Common
+
+	return i.each(m.EthernetInterfaces, func() interface{} { return &EthernetInterface{} }, func(v interface{}) {
+		e := v.(*EthernetInterface)
+
+		if bmc.NIC == nil {
+			bmc.NIC = &common.NIC{ID: m.ID}
+		}
+
+		bmc.NIC.NICPorts = append(bmc.NIC.NICPorts, nicPort(e))
+	})
+}
+
+// setFirmwareInventory sets the component firmware listed in the UpdateService FirmwareInventory
+func (i *importer) setFirmwareInventory(root *ServiceRoot) error {
+	if root.UpdateService == nil {
+		return nil
+	}
+
+	us := &UpdateService{}
+	if err := get(i.src, root.UpdateService.ODataID, us); err != nil {
+		if errors.Is(err, errResourceNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	return i.each(us.FirmwareInventory, func() interface{} { return &SoftwareInventory{} }, func(v interface{}) {
+		i.setSoftwareInventory(v.(*SoftwareInventory))
+	})
+}
+
+func (i *importer) setSoftwareInventory(sw *SoftwareInventory) {
+	version := value(sw.Version)
+	if version == "" {
+		return
+	}
+
+	// Dell lists the previous and available firmware alongside the installed firmware,
+	// identified by the prefix of the inventory ID.
+	if strings.HasPrefix(sw.ID, "Previous-") || strings.HasPrefix(sw.ID, "Available-") {
+		return
+	}
+
+	fw := firmware(version)
+	fw.SoftwareID = sw.SoftwareID
+
+	name := strings.ToLower(sw.ID + " " + sw.Name)
+
+	// the CPLD has no resource of its own, the RelatedItem links to the enclosing chassis
+	if strings.Contains(name, "cpld") {
+		i.device.CPLDs = append(i.device.CPLDs, &common.CPLD{
+			Common: common.Common{
+				Description: sw.Name,
+				Vendor:      i.device.Vendor,
+				Firmware:    fw,
+			},
+		})
+
+		return
+	}
+
+	for _, link := range sw.RelatedItem {
+		if c, exists := i.components[cleanODataID(link.ODataID)]; exists {
+			c.Firmware = fw
+			return
+		}
+	}
+
+	switch {
+	case strings.Contains(name, "bios"):
+		if i.device.BIOS != nil {
+			i.device.BIOS.Firmware = fw
+		}
+	case strings.Contains(name, "bmc"), strings.Contains(name, "remote access controller"), strings.Contains(name, "ilo"):
+		if i.device.BMC != nil {
+			i.device.BMC.Firmware = fw
+		}
+	}
+}
+
+// value returns the property value with placeholder values treated as empty
+func value(s string) string {
+	s = strings.TrimSpace(s)
+	if common.IsPlaceholder(s) {
+		return ""
+	}
+
+	return s
+}
+
+func vendor(s string) string {
+	if s = value(s); s != "" {
+		return common.FormatVendorName(s)
+	}
+
+	return ""
+}
+
+func firmware(version string) *common.Firmware {
+	if version = value(version); version == "" {
+		return nil
+	}
+
+	fw := common.NewFirmwareObj()
+	fw.Installed = version
+
+	return fw
+}
+
+func status(s *Status) *common.Status {
+	if s == nil {
+		return nil
+	}
+
+	return &common.Status{Health: s.Health, State: s.State}
+}
+
+func isAbsent(s *Status) bool {
+	return s != nil && strings.EqualFold(s.State, "Absent")
+}
@@ -0,0 +1,341 @@
+package redfish
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// mapSourceFixture returns a MapSource of the resources saved in the fixture, keyed by their @odata.id
+func mapSourceFixture(t *testing.T, name string) MapSource {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &resources); err != nil {
+		t.Fatal(err)
+	}
+
+	src := MapSource{}
+	for id, r := range resources {
+		src[id] = r
+	}
+
+	return src
+}
+
+func TestImportDirSupermicro(t *testing.T) {
+	device, err := ImportDir("testdata/supermicro_x11scz-f")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if device.Vendor != common.VendorSupermicro || device.Model != "x11scm-f" || device.Serial != "S348084X1A03158" {
+		t.Errorf("unexpected device vendor, model, serial: %s, %s, %s", device.Vendor, device.Model, device.Serial)
+	}
+
+	if device.Chassis != "rackmount" {
+		t.Errorf("expected chassis rackmount, got %s", device.Chassis)
+	}
+
+	if _, exists := device.Metadata["sku"]; exists {
+		t.Error("expected placeholder SKU to be skipped")
+	}
+
+	// the mainboard model is identified by the Chassis OEM BoardID
+	if device.Mainboard == nil || device.Mainboard.Model != "x11scz-f" || device.Mainboard.Metadata["board_id"] != "0x1b09" {
+		t.Errorf("unexpected mainboard: %+v", device.Mainboard)
+	}
+
+	if len(device.CPUs) != 1 || len(device.GPUs) != 1 {
+		t.Fatalf("expected 1 CPU and 1 GPU, got %d, %d", len(device.CPUs), len(device.GPUs))
+	}
+
+	cpu := device.CPUs[0]
+	if cpu.Vendor != common.VendorIntel || cpu.Cores != 8 || cpu.Threads != 16 || cpu.Architecture != "x86_64" || cpu.ClockSpeedHz != 5000000000 {
+		t.Errorf("unexpected CPU: %+v", cpu)
+	}
+
+	// absent DIMMs are skipped
+	if len(device.Memory) != 2 {
+		t.Fatalf("expected 2 DIMMs, got %d", len(device.Memory))
+	}
+
+	if m := device.Memory[0]; m.Slot != "DIMMA2" || m.SizeBytes != 16<<30 || m.ClockSpeedHz != 2666000000 || m.Vendor != common.VendorMicron {
+		t.Errorf("unexpected memory: %+v", m)
+	}
+
+	if len(device.NICs) != 2 || device.NICs[0].NICPorts[0].MacAddress != "3c:ec:ef:4a:1b:2e" {
+		t.Errorf("unexpected NICs: %+v", device.NICs)
+	}
+
+	if len(device.StorageControllers) != 1 || device.StorageControllers[0].ID != "HA-RAID" {
+		t.Fatalf("unexpected storage controllers: %+v", device.StorageControllers)
+	}
+
+	if len(device.Drives) != 1 {
+		t.Fatalf("expected 1 drive, got %d", len(device.Drives))
+	}
+
+	drive := device.Drives[0]
+	if drive.Vendor != common.VendorMicron || drive.Type != common.SlugDriveTypeSATASSD || drive.SmartStatus != common.SmartStatusOK ||
+		drive.StorageController != "HA-RAID" || drive.Firmware.Installed != "D1MU020" {
+		t.Errorf("unexpected drive: %+v", drive)
+	}
+
+	if len(device.PSUs) != 1 || device.PSUs[0].PowerCapacityWatts != 350 || device.PSUs[0].Firmware.Installed != "1.2" {
+		t.Errorf("unexpected PSUs: %+v", device.PSUs)
+	}
+
+	if device.BMC == nil || device.BMC.Firmware.Installed != "1.73.12" || device.BMC.NIC.NICPorts[0].MacAddress != "3c:ec:ef:4a:1b:2d" {
+		t.Errorf("unexpected BMC: %+v", device.BMC)
+	}
+
+	if device.BIOS.Firmware.Installed != "1.4" {
+		t.Errorf("expected BIOS firmware 1.4, got %s", device.BIOS.Firmware.Installed)
+	}
+
+	if len(device.CPLDs) != 1 || device.CPLDs[0].Firmware.Installed != "F1.02.0B" {
+		t.Errorf("unexpected CPLDs: %+v", device.CPLDs)
+	}
+}
+
+func TestImportMapSourceDell(t *testing.T) {
+	device, err := Import(mapSourceFixture(t, "dell_r640.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if device.Vendor != common.VendorDell || device.Model != "r640" || device.ProductName != "PowerEdge R640" {
+		t.Errorf("unexpected device vendor, model: %s, %s", device.Vendor, device.Model)
+	}
+
+	if device.Mainboard.Model != "" {
+		t.Errorf("expected no mainboard without a Supermicro BoardID, got %+v", device.Mainboard)
+	}
+
+	if len(device.CPUs) != 2 || device.CPUs[0].ClockSpeedHz != 2100000000 {
+		t.Errorf("unexpected CPUs: %+v", device.CPUs)
+	}
+
+	if len(device.Memory) != 2 || device.Memory[0].Vendor != common.VendorHynix {
+		t.Errorf("unexpected memory: %+v", device.Memory)
+	}
+
+	// ports are grouped by the NIC FQDD
+	if len(device.NICs) != 2 {
+		t.Fatalf("expected 2 NICs, got %d", len(device.NICs))
+	}
+
+	if device.NICs[0].ID != "NIC.Integrated.1" || len(device.NICs[0].NICPorts) != 2 || device.NICs[0].NICPorts[1].SpeedBits != 10000000000 {
+		t.Errorf("unexpected NIC: %+v", device.NICs[0])
+	}
+
+	if device.NICs[1].ID != "NIC.Slot.3" || len(device.NICs[1].NICPorts) != 1 {
+		t.Errorf("unexpected NIC: %+v", device.NICs[1])
+	}
+
+	if len(device.StorageControllers) != 1 {
+		t.Fatalf("expected 1 storage controller, got %d", len(device.StorageControllers))
+	}
+
+	sc := device.StorageControllers[0]
+	if sc.ID != "RAID.Integrated.1-1" || sc.SupportedDeviceProtocols != "SAS, SATA" || sc.SpeedGbps != 12 || sc.Firmware.Installed != "25.5.9.0001" {
+		t.Errorf("unexpected storage controller: %+v", sc)
+	}
+
+	if len(device.Drives) != 2 {
+		t.Fatalf("expected 2 drives, got %d", len(device.Drives))
+	}
+
+	if d := device.Drives[0]; d.WWN != "58ce38ee2050b270" || d.SmartStatus != common.SmartStatusOK || d.Vendor != common.VendorToshiba {
+		t.Errorf("unexpected drive: %+v", d)
+	}
+
+	// firmware with a RelatedItem link is set on the linked component
+	if d := device.Drives[0]; d.Firmware.SoftwareID != "104259" {
+		t.Errorf("expected drive firmware from the FirmwareInventory, got %+v", d.Firmware)
+	}
+
+	if device.Drives[1].SmartStatus != common.SmartStatusFailed {
+		t.Errorf("expected drive with a predicted failure to be failed, got %s", device.Drives[1].SmartStatus)
+	}
+
+	if len(device.VirtualDisks) != 1 {
+		t.Fatalf("expected 1 virtual disk, got %d", len(device.VirtualDisks))
+	}
+
+	vd := device.VirtualDisks[0]
+	if vd.RaidType != "RAID1" || vd.RaidImplementation != common.SlugRAIDImplHardware || vd.StorageController != sc.ID || len(vd.DriveIDs) != 2 {
+		t.Errorf("unexpected virtual disk: %+v", vd)
+	}
+
+	// only the chassis linked to the system are imported
+	if len(device.Enclosures) != 1 || device.Enclosures[0].ChassisType != "RackMount" {
+		t.Errorf("unexpected enclosures: %+v", device.Enclosures)
+	}
+
+	if len(device.PSUs) != 2 || device.PSUs[0].ID != "PSU.Slot.1" {
+		t.Errorf("unexpected PSUs: %+v", device.PSUs)
+	}
+
+	// the previous BIOS version listed in the inventory is not taken as installed
+	if device.BIOS.Firmware.Installed != "2.10.2" || device.BIOS.Firmware.SoftwareID != "159" {
+		t.Errorf("unexpected BIOS firmware: %+v", device.BIOS.Firmware)
+	}
+
+	if device.BMC.Firmware.Installed != "5.00.00.00" || device.BMC.ID != "iDRAC.Embedded.1" {
+		t.Errorf("unexpected BMC: %+v", device.BMC)
+	}
+
+	if len(device.CPLDs) != 1 || device.CPLDs[0].Firmware.Installed != "1.0.6" {
+		t.Errorf("unexpected CPLDs: %+v", device.CPLDs)
+	}
+}
+
+func TestImportStorageControllers(t *testing.T) {
+	src := mapSourceFixture(t, "dell_r640.json")
+
+	const (
+		storages = "/redfish/v1/Systems/System.Embedded.1/Storage"
+		raid     = storages + "/RAID.Integrated.1-1"
+		ahci     = storages + "/AHCI.Embedded.1-1"
+		boss     = ahci + "/Drives/Disk.Direct.0-0:AHCI.Embedded.1-1"
+		linked   = "/redfish/v1/Chassis/System.Embedded.1/Drives/Disk.Direct.1-1:AHCI.Embedded.1-1"
+	)
+
+	storage := map[string]interface{}{}
+	if err := json.Unmarshal(src[raid], &storage); err != nil {
+		t.Fatal(err)
+	}
+
+	// the RAID controller Storage lists the drives of the onboard controller
+	storage["Drives"] = append(storage["Drives"].([]interface{}), map[string]string{"@odata.id": boss}, map[string]string{"@odata.id": linked})
+
+	data, err := json.Marshal(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src[raid] = data
+	src[storages] = []byte(`{"Members": [{"@odata.id": "` + raid + `"}, {"@odata.id": "` + ahci + `"}]}`)
+	src[ahci] = []byte(`{"@odata.id": "` + ahci + `", "Id": "AHCI.Embedded.1-1",
+		"StorageControllers": [{"MemberId": "AHCI.Embedded.1-1", "Model": "C620 Series Chipset"}],
+		"Drives": [{"@odata.id": "` + boss + `"}],
+		"Volumes": {"@odata.id": "` + ahci + `/Volumes"}}`)
+	src[boss] = []byte(`{"@odata.id": "` + boss + `", "Id": "Disk.Direct.0-0:AHCI.Embedded.1-1", "SerialNumber": "S1"}`)
+	src[linked] = []byte(`{"@odata.id": "` + linked + `", "Id": "Disk.Direct.1-1:AHCI.Embedded.1-1", "SerialNumber": "S2",
+		"Links": {"Storage": {"@odata.id": "` + ahci + `"}}}`)
+	src[ahci+"/Volumes"] = []byte(`{"Members": [{"@odata.id": "` + ahci + `/Volumes/Disk.Virtual.0:AHCI.Embedded.1-1"}]}`)
+	src[ahci+"/Volumes/Disk.Virtual.0:AHCI.Embedded.1-1"] = []byte(`{"@odata.id": "` + ahci + `/Volumes/Disk.Virtual.0:AHCI.Embedded.1-1",
+		"Id": "Disk.Virtual.0:AHCI.Embedded.1-1", "RAIDType": "RAID1"}`)
+
+	device, err := Import(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(device.StorageControllers) != 2 || len(device.Drives) != 4 || len(device.VirtualDisks) != 2 {
+		t.Fatalf("unexpected storage: %d controllers, %d drives, %d virtual disks",
+			len(device.StorageControllers), len(device.Drives), len(device.VirtualDisks))
+	}
+
+	expected := map[string]string{
+		"Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1": "RAID.Integrated.1-1",
+		"Disk.Bay.1:Enclosure.Internal.0-1:RAID.Integrated.1-1": "RAID.Integrated.1-1",
+		"Disk.Direct.0-0:AHCI.Embedded.1-1":                     "AHCI.Embedded.1-1",
+		"Disk.Direct.1-1:AHCI.Embedded.1-1":                     "AHCI.Embedded.1-1",
+		"Disk.Virtual.0:RAID.Integrated.1-1":                    "RAID.Integrated.1-1",
+		"Disk.Virtual.0:AHCI.Embedded.1-1":                      "AHCI.Embedded.1-1",
+	}
+
+	for _, d := range device.Drives {
+		if d.StorageController != expected[d.ID] {
+			t.Errorf("expected drive %s on %s, got: %s", d.ID, expected[d.ID], d.StorageController)
+		}
+	}
+
+	for _, vd := range device.VirtualDisks {
+		if vd.StorageController != expected[vd.ID] {
+			t.Errorf("expected virtual disk %s on %s, got: %s", vd.ID, expected[vd.ID], vd.StorageController)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	if _, err := Import(MapSource{}); !errors.Is(err, errResourceNotFound) {
+		t.Errorf("expected errResourceNotFound, got %v", err)
+	}
+
+	src := MapSource{
+		"/redfish/v1":         []byte(`{"@odata.id": "/redfish/v1", "Systems": {"@odata.id": "/redfish/v1/Systems"}}`),
+		"/redfish/v1/Systems": []byte(`{"@odata.id": "/redfish/v1/Systems", "Members": []}`),
+	}
+
+	if _, err := Import(src); !errors.Is(err, errNoSystems) {
+		t.Errorf("expected errNoSystems, got %v", err)
+	}
+
+	src["/redfish/v1/Systems"] = []byte(`{"Members": [{"@odata.id": "/redfish/v1/Systems/1"}]}`)
+	src["/redfish/v1/Systems/1"] = []byte(`{`)
+
+	if _, err := Import(src); err == nil {
+		t.Error("expected error decoding an invalid resource")
+	}
+}
+
+func TestCleanODataID(t *testing.T) {
+	testcases := map[string]string{
+		"/redfish/v1/": "/redfish/v1",
+		"/redfish/v1/Chassis/1/Power#/PowerSupplies/0": "/redfish/v1/Chassis/1/Power",
+		"/": "/",
+	}
+
+	for in, expected := range testcases {
+		if got := cleanODataID(in); got != expected {
+			t.Errorf("cleanODataID(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
+
+func TestDirSourceOutsideRoot(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "mockup")
+
+	if err := os.MkdirAll(filepath.Join(root, "redfish", "v1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, data := range map[string]string{
+		filepath.Join(root, "redfish", "v1", "index.json"): `{"@odata.id": "/redfish/v1"}`,
+		filepath.Join(dir, "secret.json"):                  `{}`,
+	} {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src := DirSource(root)
+
+	if _, err := src.Get("/redfish/v1/Systems/../"); err != nil {
+		t.Errorf("expected the resource within the directory, got %v", err)
+	}
+
+	for _, id := range []string{"/redfish/v1/../../../secret", "/../secret", ".."} {
+		if _, err := src.Get(id); !errors.Is(err, errInvalidODataID) {
+			t.Errorf("expected errInvalidODataID for %s, got %v", id, err)
+		}
+	}
+
+	if _, err := src.Get("/redfish/v1/../../secret"); !errors.Is(err, errResourceNotFound) {
+		t.Errorf("expected errResourceNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+// Package redfish maps Redfish resources to and from a common.Device.
+//
+// The resource types in this package hold the subset of the DMTF Redfish schema properties
+// populated on the Device.
+package redfish
+
+import (
+	"encoding/json"
+)
+
+// Link is a reference to a Redfish resource
+type Link struct {
+	ODataID string `json:"@odata.id"`
+}
+
+// Resource holds the properties common to all Redfish resources
+type Resource struct {
+	ODataContext string `json:"@odata.context,omitempty"`
+	ODataID      string `json:"@odata.id"`
+	ODataType    string `json:"@odata.type,omitempty"`
+	ID           string `json:"Id,omitempty"`
+	Name         string `json:"Name,omitempty"`
+	Description  string `json:"Description,omitempty"`
+}
+
+// Collection is a Redfish resource collection
+type Collection struct {
+	Resource
+
+	Members      []Link `json:"Members"`
+	MembersCount int    `json:"Members@odata.count"`
+}
+
+// Status is the Redfish resource status
+type Status struct {
+	Health string `json:"Health,omitempty"`
+	State  string `json:"State,omitempty"`
+}
+
+// ServiceRoot is the /redfish/v1 resource
+type ServiceRoot struct {
+	Resource
+
+	RedfishVersion string `json:"RedfishVersion,omitempty"`
+	UUID           string `json:"UUID,omitempty"`
+	Systems        *Link  `json:"Systems,omitempty"`
+	Chassis        *Link  `json:"Chassis,omitempty"`
+	Managers       *Link  `json:"Managers,omitempty"`
+	UpdateService  *Link  `json:"UpdateService,omitempty"`
+}
+
+// ComputerSystem is a Redfish ComputerSystem resource
+type ComputerSystem struct {
+	Resource
+
+	Manufacturer       string  `json:"Manufacturer,omitempty"`
+	Model              string  `json:"Model,omitempty"`
+	SKU                string  `json:"SKU,omitempty"`
+	SerialNumber       string  `json:"SerialNumber,omitempty"`
+	PartNumber         string  `json:"PartNumber,omitempty"`
+	UUID               string  `json:"UUID,omitempty"`
+	BiosVersion        string  `json:"BiosVersion,omitempty"`
+	SystemType         string  `json:"SystemType,omitempty"`
+	PowerState         string  `json:"PowerState,omitempty"`
+	Status             *Status `json:"Status,omitempty"`
+	Processors         *Link   `json:"Processors,omitempty"`
+	Memory             *Link   `json:"Memory,omitempty"`
+	EthernetInterfaces *Link   `json:"EthernetInterfaces,omitempty"`
+	Storage            *Link   `json:"Storage,omitempty"`
+	Bios               *Link   `json:"Bios,omitempty"`
+	Links              struct {
+		Chassis   []Link `json:"Chassis,omitempty"`
+		ManagedBy []Link `json:"ManagedBy,omitempty"`
+	} `json:"Links"`
+}
+
+// Chassis is a Redfish Chassis resource
+type Chassis struct {
+	Resource
+
+	ChassisType  string          `json:"ChassisType,omitempty"`
+	Manufacturer string          `json:"Manufacturer,omitempty"`
+	Model        string          `json:"Model,omitempty"`
+	SKU          string          `json:"SKU,omitempty"`
+	SerialNumber string          `json:"SerialNumber,omitempty"`
+	PartNumber   string          `json:"PartNumber,omitempty"`
+	Status       *Status         `json:"Status,omitempty"`
+	Power        *Link           `json:"Power,omitempty"`
+	Oem          json.RawMessage `json:"Oem,omitempty"`
+	Links        struct {
+		ComputerSystems []Link `json:"ComputerSystems,omitempty"`
+		ManagedBy       []Link `json:"ManagedBy,omitempty"`
+	} `json:"Links"`
+}
+
+// supermicroChassisOem holds the Supermicro OEM Chassis properties
+type supermicroChassisOem struct {
+	Supermicro struct {
+		BoardID string `json:"BoardID"`
+	} `json:"Supermicro"`
+}
+
+// Processor is a Redfish Processor resource
+type Processor struct {
+	Resource
+
+	Socket                string  `json:"Socket,omitempty"`
+	ProcessorType         string  `json:"ProcessorType,omitempty"`
+	ProcessorArchitecture string  `json:"ProcessorArchitecture,omitempty"`
+	InstructionSet        string  `json:"InstructionSet,omitempty"`
+	Manufacturer          string  `json:"Manufacturer,omitempty"`
+	Model                 string  `json:"Model,omitempty"`
+	SerialNumber          string  `json:"SerialNumber,omitempty"`
+	PartNumber            string  `json:"PartNumber,omitempty"`
+	MaxSpeedMHz           int64   `json:"MaxSpeedMHz,omitempty"`
+	OperatingSpeedMHz     int64   `json:"OperatingSpeedMHz,omitempty"`
+	TotalCores            int     `json:"TotalCores,omitempty"`
+	TotalThreads          int     `json:"TotalThreads,omitempty"`
+	Status                *Status `json:"Status,omitempty"`
+}
+
+// Memory is a Redfish Memory resource
+type Memory struct {
+	Resource
+
+	DeviceLocator     string  `json:"DeviceLocator,omitempty"`
+	MemoryDeviceType  string  `json:"MemoryDeviceType,omitempty"`
+	BaseModuleType    string  `json:"BaseModuleType,omitempty"`
+	CapacityMiB       int64   `json:"CapacityMiB,omitempty"`
+	OperatingSpeedMhz int64   `json:"OperatingSpeedMhz,omitempty"`
+	Manufacturer      string  `json:"Manufacturer,omitempty"`
+	SerialNumber      string  `json:"SerialNumber,omitempty"`
+	PartNumber        string  `json:"PartNumber,omitempty"`
+	Status            *Status `json:"Status,omitempty"`
+}
+
+// EthernetInterface is a Redfish EthernetInterface resource
+type EthernetInterface struct {
+	Resource
+
+	MACAddress          string  `json:"MACAddress,omitempty"`
+	PermanentMACAddress string  `json:"PermanentMACAddress,omitempty"`
+	SpeedMbps           int64   `json:"SpeedMbps,omitempty"`
+	AutoNeg             bool    `json:"AutoNeg,omitempty"`
+	MTUSize             int     `json:"MTUSize,omitempty"`
+	LinkStatus          string  `json:"LinkStatus,omitempty"`
+	InterfaceEnabled    *bool   `json:"InterfaceEnabled,omitempty"`
+	Status              *Status `json:"Status,omitempty"`
+}
+
+// Storage is a Redfish Storage resource
+type Storage struct {
+	Resource
+
+	StorageControllers []StorageController `json:"StorageControllers,omitempty"`
+	Drives             []Link              `json:"Drives,omitempty"`
+	Volumes            *Link               `json:"Volumes,omitempty"`
+	Status             *Status             `json:"Status,omitempty"`
+}
+
+// StorageController is a controller listed in the Redfish Storage resource
+type StorageController struct {
+	ODataID                      string   `json:"@odata.id,omitempty"`
+	MemberID                     string   `json:"MemberId,omitempty"`
+	Name                         string   `json:"Name,omitempty"`
+	Manufacturer                 string   `json:"Manufacturer,omitempty"`
+	Model                        string   `json:"Model,omitempty"`
+	SerialNumber                 string   `json:"SerialNumber,omitempty"`
+	FirmwareVersion              string   `json:"FirmwareVersion,omitempty"`
+	SpeedGbps                    float64  `json:"SpeedGbps,omitempty"`
+	SupportedControllerProtocols []string `json:"SupportedControllerProtocols,omitempty"`
+	SupportedDeviceProtocols     []string `json:"SupportedDeviceProtocols,omitempty"`
+	SupportedRAIDTypes           []string `json:"SupportedRAIDTypes,omitempty"`
+	Status                       *Status  `json:"Status,omitempty"`
+}
+
+// Drive is a Redfish Drive resource
+type Drive struct {
+	Resource
+
+	Manufacturer       string      `json:"Manufacturer,omitempty"`
+	Model              string      `json:"Model,omitempty"`
+	SerialNumber       string      `json:"SerialNumber,omitempty"`
+	PartNumber         string      `json:"PartNumber,omitempty"`
+	Revision           string      `json:"Revision,omitempty"`
+	CapacityBytes      int64       `json:"CapacityBytes,omitempty"`
+	BlockSizeBytes     int64       `json:"BlockSizeBytes,omitempty"`
+	Protocol           string      `json:"Protocol,omitempty"`
+	MediaType          string      `json:"MediaType,omitempty"`
+	CapableSpeedGbs    float64     `json:"CapableSpeedGbs,omitempty"`
+	NegotiatedSpeedGbs float64     `json:"NegotiatedSpeedGbs,omitempty"`
+	FailurePredicted   *bool       `json:"FailurePredicted,omitempty"`
+	Identifiers        []ident     `json:"Identifiers,omitempty"`
+	Status             *Status     `json:"Status,omitempty"`
+	Links              *DriveLinks `json:"Links,omitempty"`
+}
+
+// DriveLinks are the links of a Redfish Drive resource
+type DriveLinks struct {
+	// Storage is the Storage resource the drive belongs to
+	Storage *Link `json:"Storage,omitempty"`
+}
+
+// ident is a durable name identifying a resource, for example the drive WWN
+type ident struct {
+	DurableName       string `json:"DurableName,omitempty"`
+	DurableNameFormat string `json:"DurableNameFormat,omitempty"`
+}
+
+// Volume is a Redfish Volume resource
+type Volume struct {
+	Resource
+
+	RAIDType      string  `json:"RAIDType,omitempty"`
+	VolumeType    string  `json:"VolumeType,omitempty"`
+	CapacityBytes int64   `json:"CapacityBytes,omitempty"`
+	Status        *Status `json:"Status,omitempty"`
+	Links         struct {
+		Drives []Link `json:"Drives,omitempty"`
+	} `json:"Links"`
+}
+
+// Power is a Redfish Power resource
+type Power struct {
+	Resource
+
+	PowerSupplies []PowerSupply `json:"PowerSupplies,omitempty"`
+}
+
+// PowerSupply is a power supply listed in the Redfish Power resource
+type PowerSupply struct {
+	ODataID            string  `json:"@odata.id,omitempty"`
+	MemberID           string  `json:"MemberId,omitempty"`
+	Name               string  `json:"Name,omitempty"`
+	Manufacturer       string  `json:"Manufacturer,omitempty"`
+	Model              string  `json:"Model,omitempty"`
+	SerialNumber       string  `json:"SerialNumber,omitempty"`
+	PartNumber         string  `json:"PartNumber,omitempty"`
+	FirmwareVersion    string  `json:"FirmwareVersion,omitempty"`
+	PowerCapacityWatts float64 `json:"PowerCapacityWatts,omitempty"`
+	Status             *Status `json:"Status,omitempty"`
+}
+
+// Manager is a Redfish Manager resource
+type Manager struct {
+	Resource
+
+	ManagerType        string  `json:"ManagerType,omitempty"`
+	Manufacturer       string  `json:"Manufacturer,omitempty"`
+	Model              string  `json:"Model,omitempty"`
+	SerialNumber       string  `json:"SerialNumber,omitempty"`
+	FirmwareVersion    string  `json:"FirmwareVersion,omitempty"`
+	UUID               string  `json:"UUID,omitempty"`
+	Status             *Status `json:"Status,omitempty"`
+	EthernetInterfaces *Link   `json:"EthernetInterfaces,omitempty"`
+	Links              struct {
+		ManagerForServers []Link `json:"ManagerForServers,omitempty"`
+		ManagerForChassis []Link `json:"ManagerForChassis,omitempty"`
+	} `json:"Links"`
+}
+
+// UpdateService is a Redfish UpdateService resource
+type UpdateService struct {
+	Resource
+
+	ServiceEnabled    bool  `json:"ServiceEnabled"`
+	FirmwareInventory *Link `json:"FirmwareInventory,omitempty"`
+}
+
+// SoftwareInventory is a Redfish SoftwareInventory resource, the members of the FirmwareInventory collection
+type SoftwareInventory struct {
+	Resource
+
+	Version     string  `json:"Version,omitempty"`
+	SoftwareID  string  `json:"SoftwareId,omitempty"`
+	Updateable  bool    `json:"Updateable"`
+	Status      *Status `json:"Status,omitempty"`
+	RelatedItem []Link  `json:"RelatedItem,omitempty"`
+}
@@ -0,0 +1,91 @@
+package redfish
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	errResourceNotFound = errors.New("redfish resource not found")
+	errInvalidODataID   = errors.New("invalid redfish @odata.id")
+)
+
+// Source returns the saved Redfish resources by their @odata.id, for example "/redfish/v1/Systems/1".
+type Source interface {
+	Get(odataID string) ([]byte, error)
+}
+
+// MapSource is a Source of Redfish resources held in memory, keyed by their @odata.id
+type MapSource map[string][]byte
+
+// Get implements the Source interface
+func (m MapSource) Get(odataID string) ([]byte, error) {
+	data, exists := m[cleanODataID(odataID)]
+	if !exists {
+		return nil, fmt.Errorf("%w : %s", errResourceNotFound, odataID)
+	}
+
+	return data, nil
+}
+
+// DirSource is a Source of Redfish resources saved to a directory.
+//
+// Resources are looked up in the layout the DMTF Redfish-Mockup-Creator writes, where the
+// resource "/redfish/v1/Systems/1" is read from "<dir>/redfish/v1/Systems/1/index.json",
+// and falls back to "<dir>/redfish/v1/Systems/1.json". Resources resolving outside of the directory are rejected.
+type DirSource string
+
+// Get implements the Source interface
+func (d DirSource) Get(odataID string) ([]byte, error) {
+	rel := filepath.Clean(filepath.FromSlash(strings.TrimPrefix(cleanODataID(odataID), "/")))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%w : %s", errInvalidODataID, odataID)
+	}
+
+	for _, path := range []string{
+		filepath.Join(string(d), rel, "index.json"),
+		filepath.Join(string(d), rel+".json"),
+	} {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return data, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w : %s", errResourceNotFound, odataID)
+}
+
+// cleanODataID returns the @odata.id without a trailing slash or fragment
+func cleanODataID(odataID string) string {
+	if idx := strings.Index(odataID, "#"); idx >= 0 {
+		odataID = odataID[:idx]
+	}
+
+	if odataID != "/" {
+		odataID = strings.TrimSuffix(odataID, "/")
+	}
+
+	return odataID
+}
+
+// get decodes the resource with the given @odata.id into v
+func get(src Source, odataID string, v interface{}) error {
+	data, err := src.Get(odataID)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding redfish resource %s: %w", odataID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	// DeviceSchemaVersion is the current version of the serialized Device.
+	//
+	// Bump this when a change to the Device model renames or restructures serialized fields,
+	// and register a Migration from the previous version.
+	DeviceSchemaVersion = 2
+
+	// deviceSchemaVersionKey is the JSON key holding the Device schema version,
+	// payloads without it are considered to be version 1.
+	deviceSchemaVersionKey = "schema_version"
+)
+
+var (
+	errMigrationExists  = errors.New("a migration is already registered for the schema version")
+	errMigrationVersion = errors.New("invalid migration schema version")
+	errMissingMigration = errors.New("no migration registered for the schema version")
+	errSchemaVersion    = errors.New("unsupported Device schema version")
+)
+
+// Migration upgrades a decoded Device JSON document from schema version From to From+1
+type Migration struct {
+	From        int
+	Description string
+	Migrate     func(doc map[string]interface{}) error
+}
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   = map[int]Migration{}
+)
+
+func init() {
+	for _, m := range builtinMigrations {
+		if err := RegisterMigration(m); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterMigration adds a Migration to the registry used when decoding Device JSON.
+func RegisterMigration(m Migration) error {
+	if m.From < 1 || m.From >= DeviceSchemaVersion || m.Migrate == nil {
+		return fmt.Errorf("%w : %d", errMigrationVersion, m.From)
+	}
+
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	if _, exists := migrations[m.From]; exists {
+		return fmt.Errorf("%w : %d", errMigrationExists, m.From)
+	}
+
+	migrations[m.From] = m
+
+	return nil
+}
+
+// Migrations returns the registered migrations ordered by schema version
+func Migrations() []Migration {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	list := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		list = append(list, m)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].From < list[j].From })
+
+	return list
+}
+
+// MigrateDeviceJSON upgrades the given Device JSON document to DeviceSchemaVersion.
+//
+// Documents already at the current version are returned as is.
+func MigrateDeviceJSON(data []byte) ([]byte, error) {
+	doc := map[string]interface{}{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// retain integer precision on values passed through the migrations
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	version, err := documentSchemaVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == DeviceSchemaVersion {
+		return data, nil
+	}
+
+	if version > DeviceSchemaVersion {
+		return nil, fmt.Errorf("%w : %d, latest supported: %d", errSchemaVersion, version, DeviceSchemaVersion)
+	}
+
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	for ; version < DeviceSchemaVersion; version++ {
+		m, exists := migrations[version]
+		if !exists {
+			return nil, fmt.Errorf("%w : %d", errMissingMigration, version)
+		}
+
+		if err := m.Migrate(doc); err != nil {
+			return nil, fmt.Errorf("schema migration from version %d: %w", version, err)
+		}
+	}
+
+	doc[deviceSchemaVersionKey] = DeviceSchemaVersion
+
+	return json.Marshal(doc)
+}
+
+func documentSchemaVersion(doc map[string]interface{}) (int, error) {
+	v, exists := doc[deviceSchemaVersionKey]
+	if !exists || v == nil {
+		return 1, nil
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%w : %v", errSchemaVersion, v)
+	}
+
+	version, err := n.Int64()
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w : %v", errSchemaVersion, v)
+	}
+
+	return int(version), nil
+}
+
+// MarshalJSON implements json.Marshaler, setting the SchemaVersion to DeviceSchemaVersion
+func (d Device) MarshalJSON() ([]byte, error) {
+	type device Device
+
+	d.SchemaVersion = DeviceSchemaVersion
+
+	return json.Marshal(device(d))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, migrating payloads of older schema versions before decoding
+func (d *Device) UnmarshalJSON(data []byte) error {
+	type device Device
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	migrated, err := MigrateDeviceJSON(data)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(migrated, (*device)(d)); err != nil {
+		return err
+	}
+
+	d.SchemaVersion = DeviceSchemaVersion
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the schema version 1 clock_speeed_hz key
+// for CPUs decoded outside of a Device. The version 1 Status Health and State keys are matched
+// by the case insensitive encoding/json field matching.
+func (c *CPU) UnmarshalJSON(data []byte) error {
+	type cpu CPU
+
+	v := struct {
+		*cpu
+		ClockSpeeedHz int64 `json:"clock_speeed_hz,omitempty"`
+	}{cpu: (*cpu)(c)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if c.ClockSpeedHz == 0 {
+		c.ClockSpeedHz = v.ClockSpeeedHz
+	}
+
+	return nil
+}
+
+var builtinMigrations = []Migration{
+	{
+		From:        1,
+		Description: "rename CPU clock_speeed_hz, lower case Status keys, replace VirtualDisk physical_drives with drive_ids",
+		Migrate:     migrateV1,
+	},
+}
+
+func migrateV1(doc map[string]interface{}) error {
+	for _, cpu := range objectList(doc["cpus"]) {
+		renameKey(cpu, "clock_speeed_hz", "clock_speed_hz")
+	}
+
+	walkObjects(doc, func(obj map[string]interface{}) {
+		status, ok := obj["status"].(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		renameKey(status, "Health", "health")
+		renameKey(status, "State", "state")
+	})
+
+	for _, vd := range objectList(doc["virtual_disks"]) {
+		drives, exists := vd["physical_drives"]
+		if !exists {
+			continue
+		}
+
+		delete(vd, "physical_drives")
+
+		if _, exists := vd["drive_ids"]; exists {
+			continue
+		}
+
+		ids := []interface{}{}
+
+		for _, drive := range objectList(drives) {
+			if id, ok := drive["id"].(string); ok && id != "" {
+				ids = append(ids, id)
+			}
+		}
+
+		if len(ids) > 0 {
+			vd["drive_ids"] = ids
+		}
+	}
+
+	return nil
+}
+
+// renameKey moves the value at key old to key new, a value already present at new is retained.
+func renameKey(obj map[string]interface{}, old, new string) {
+	v, exists := obj[old]
+	if !exists {
+		return
+	}
+
+	delete(obj, old)
+
+	if _, exists := obj[new]; !exists {
+		obj[new] = v
+	}
+}
+
+// objectList returns the JSON objects in the given JSON array value
+func objectList(v interface{}) []map[string]interface{} {
+	list, _ := v.([]interface{})
+	objects := make([]map[string]interface{}, 0, len(list))
+
+	for _, item := range list {
+		if obj, ok := item.(map[string]interface{}); ok {
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects
+}
+
+// walkObjects calls fn on the given JSON value and every JSON object nested in it
+func walkObjects(v interface{}, fn func(obj map[string]interface{})) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		fn(t)
+
+		for _, child := range t {
+			walkObjects(child, fn)
+		}
+	case []interface{}:
+		for _, child := range t {
+			walkObjects(child, fn)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestDeviceUnmarshalJSONMigration(t *testing.T) {
+	v1 := `{
+		"serial": "ABC123",
+		"status": {"Health": "OK", "State": "Enabled"},
+		"cpus": [{"id": "CPU.Socket.1", "clock_speeed_hz": 2400000000, "status": {"Health": "Warning"}}],
+		"virtual_disks": [{"id": "Disk.Virtual.0", "physical_drives": [{"id": "Disk.Bay.0"}, {"id": "Disk.Bay.1"}]}]
+	}`
+
+	d := Device{}
+	if err := json.Unmarshal([]byte(v1), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.SchemaVersion != DeviceSchemaVersion {
+		t.Errorf("expected schema version %d, got: %d", DeviceSchemaVersion, d.SchemaVersion)
+	}
+
+	if d.Status.Health != "OK" || d.Status.State != "Enabled" {
+		t.Errorf("expected device status to be migrated, got: %+v", d.Status)
+	}
+
+	if d.CPUs[0].ClockSpeedHz != 2400000000 {
+		t.Errorf("expected CPU clock speed 2400000000, got: %d", d.CPUs[0].ClockSpeedHz)
+	}
+
+	if d.CPUs[0].Status.Health != "Warning" {
+		t.Errorf("expected CPU status to be migrated, got: %+v", d.CPUs[0].Status)
+	}
+
+	if ids := d.VirtualDisks[0].DriveIDs; len(ids) != 2 || ids[1] != "Disk.Bay.1" {
+		t.Errorf("expected virtual disk drive IDs to be migrated, got: %v", ids)
+	}
+
+	// a current version payload round trips
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(b, []byte(`"schema_version":2`)) || !bytes.Contains(b, []byte(`"clock_speed_hz":2400000000`)) {
+		t.Errorf("unexpected marshalled device: %s", b)
+	}
+
+	roundtrip := Device{}
+	if err := json.Unmarshal(b, &roundtrip); err != nil {
+		t.Fatal(err)
+	}
+
+	if cs := Diff(&d, &roundtrip); !cs.Empty() {
+		t.Errorf("expected no changes after round trip, got: %v", cs.Changes)
+	}
+}
+
+func TestDeviceUnmarshalJSONUnsupportedVersion(t *testing.T) {
+	d := Device{}
+
+	if err := json.Unmarshal([]byte(`{"schema_version": 99}`), &d); err == nil {
+		t.Error("expected error for unsupported schema version")
+	}
+}
+
+func TestDeviceJSONSchemaUpToDate(t *testing.T) {
+	schema, err := DeviceJSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := os.ReadFile("device.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(bytes.TrimSpace(current), schema) {
+		t.Error("device.schema.json is out of date, run go generate")
+	}
+}
+
+func TestComponentUnmarshalJSONV1Keys(t *testing.T) {
+	cpus := []*CPU{}
+	if err := json.Unmarshal([]byte(`[{"id": "CPU.Socket.1", "clock_speeed_hz": 2400000000, "status": {"Health": "OK", "State": "Enabled"}}]`), &cpus); err != nil {
+		t.Fatal(err)
+	}
+
+	if cpus[0].ID != "CPU.Socket.1" || cpus[0].ClockSpeedHz != 2400000000 {
+		t.Errorf("expected CPU clock speed 2400000000, got: %+v", cpus[0])
+	}
+
+	if cpus[0].Status == nil || cpus[0].Status.Health != "OK" || cpus[0].Status.State != "Enabled" {
+		t.Errorf("expected CPU status to be decoded, got: %+v", cpus[0].Status)
+	}
+
+	cpu := CPU{}
+	if err := json.Unmarshal([]byte(`{"clock_speed_hz": 3000000000, "clock_speeed_hz": 2400000000}`), &cpu); err != nil {
+		t.Fatal(err)
+	}
+
+	if cpu.ClockSpeedHz != 3000000000 {
+		t.Errorf("expected the clock_speed_hz key to be preferred, got: %d", cpu.ClockSpeedHz)
+	}
+
+	drive := Drive{}
+	if err := json.Unmarshal([]byte(`{"id": "Disk.Bay.0", "status": {"Health": "Critical", "State": "Enabled"}}`), &drive); err != nil {
+		t.Fatal(err)
+	}
+
+	if drive.Status == nil || drive.Status.Health != "Critical" {
+		t.Errorf("expected drive status to be decoded, got: %+v", drive.Status)
+	}
+}
@@ -0,0 +1,428 @@
+// Package smartctl imports the JSON output of `smartctl --json -a` for ATA, SCSI and NVMe devices into a common.Drive.
+package smartctl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+var errDeviceOpen = errors.New("smartctl failed to open the device")
+
+// Device protocols as reported by smartctl
+const (
+	protocolATA  = "ATA"
+	protocolSCSI = "SCSI"
+	protocolNVMe = "NVMe"
+)
+
+// smartctl exit status bits indicating the command line did not parse or the device could not be opened
+const exitStatusOpenFailed = 0x03
+
+// output is the subset of the smartctl JSON output mapped to the Drive
+type output struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String   string `json:"string"`
+			Severity string `json:"severity"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	Device struct {
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		Protocol string `json:"protocol"`
+	} `json:"device"`
+	ModelFamily     string `json:"model_family"`
+	ModelName       string `json:"model_name"`
+	SerialNumber    string `json:"serial_number"`
+	FirmwareVersion string `json:"firmware_version"`
+	WWN             *struct {
+		NAA uint64 `json:"naa"`
+		OUI uint64 `json:"oui"`
+		ID  uint64 `json:"id"`
+	} `json:"wwn"`
+	UserCapacity struct {
+		Bytes int64 `json:"bytes"`
+	} `json:"user_capacity"`
+	LogicalBlockSize int64 `json:"logical_block_size"`
+	RotationRate     *int  `json:"rotation_rate"`
+	InterfaceSpeed   struct {
+		Max     *interfaceSpeed `json:"max"`
+		Current *interfaceSpeed `json:"current"`
+	} `json:"interface_speed"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	ATASmartAttributes struct {
+		Table []ataSmartAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+	ATASmartErrorLog struct {
+		Summary struct {
+			Count int `json:"count"`
+		} `json:"summary"`
+	} `json:"ata_smart_error_log"`
+
+	// SCSI
+	SCSIVendor            string `json:"scsi_vendor"`
+	SCSIProduct           string `json:"scsi_product"`
+	SCSIModelName         string `json:"scsi_model_name"`
+	SCSIRevision          string `json:"scsi_revision"`
+	LogicalUnitID         string `json:"logical_unit_id"`
+	SCSITransportProtocol struct {
+		Name string `json:"name"`
+	} `json:"scsi_transport_protocol"`
+	SCSIGrownDefectList int `json:"scsi_grown_defect_list"`
+	SCSIErrorCounterLog map[string]struct {
+		TotalUncorrectedErrors int `json:"total_uncorrected_errors"`
+	} `json:"scsi_error_counter_log"`
+
+	// NVMe
+	NVMePCIVendor struct {
+		ID int `json:"id"`
+	} `json:"nvme_pci_vendor"`
+	NVMeTotalCapacity int64 `json:"nvme_total_capacity"`
+	NVMeNamespaces    []struct {
+		FormattedLBASize int64 `json:"formatted_lba_size"`
+		EUI64            *struct {
+			OUI   uint64 `json:"oui"`
+			ExtID uint64 `json:"ext_id"`
+		} `json:"eui64"`
+	} `json:"nvme_namespaces"`
+	NVMeSmartHealthInformationLog *nvmeHealthLog `json:"nvme_smart_health_information_log"`
+}
+
+type interfaceSpeed struct {
+	String         string `json:"string"`
+	UnitsPerSecond int64  `json:"units_per_second"`
+	BitsPerUnit    int64  `json:"bits_per_unit"`
+}
+
+// gbps returns the speed in Gbit/s
+func (s *interfaceSpeed) gbps() int64 {
+	if s == nil {
+		return 0
+	}
+
+	return s.UnitsPerSecond * s.BitsPerUnit / 1000000000
+}
+
+type ataSmartAttribute struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Value      int    `json:"value"`
+	Worst      int    `json:"worst"`
+	Thresh     int    `json:"thresh"`
+	WhenFailed string `json:"when_failed"`
+	Flags      struct {
+		Prefailure    bool `json:"prefailure"`
+		UpdatedOnline bool `json:"updated_online"`
+	} `json:"flags"`
+}
+
+type nvmeHealthLog struct {
+	CriticalWarning         int `json:"critical_warning"`
+	AvailableSpare          int `json:"available_spare"`
+	AvailableSpareThreshold int `json:"available_spare_threshold"`
+	PercentageUsed          int `json:"percentage_used"`
+	MediaErrors             int `json:"media_errors"`
+	NumErrLogEntries        int `json:"num_err_log_entries"`
+}
+
+// Parse reads the JSON output of `smartctl --json -a` and returns the Drive it describes
+func Parse(r io.Reader) (*common.Drive, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBytes(data)
+}
+
+// ParseBytes returns the Drive described by the given `smartctl --json -a` output
+func ParseBytes(data []byte) (*common.Drive, error) {
+	out := &output{}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+
+	if out.Smartctl.ExitStatus&exitStatusOpenFailed != 0 {
+		return nil, fmt.Errorf("%w : %s: %s", errDeviceOpen, out.Device.Name, out.errorMessages())
+	}
+
+	drive := &common.Drive{
+		Common: common.Common{
+			Description: out.ModelFamily,
+			LogicalName: out.Device.Name,
+		},
+		ID:             out.Device.Name,
+		CapacityBytes:  out.UserCapacity.Bytes,
+		BlockSizeBytes: out.LogicalBlockSize,
+		SmartStatus:    out.smartStatus(),
+	}
+
+	switch out.Device.Protocol {
+	case protocolATA:
+		out.setATA(drive)
+	case protocolSCSI:
+		out.setSCSI(drive)
+	case protocolNVMe:
+		out.setNVMe(drive)
+	}
+
+	drive.Serial = strings.TrimSpace(out.SerialNumber)
+	drive.ProductName = drive.Model
+
+	if drive.Vendor == "" {
+		drive.Vendor = vendorFromModel(drive.Model)
+	}
+
+	if drive.Vendor == "" {
+		drive.Vendor = vendorFromModel(out.ModelFamily)
+	}
+
+	if msgs := out.errorMessages(); msgs != "" {
+		drive.SmartErrors = append(drive.SmartErrors, msgs)
+	}
+
+	return drive, nil
+}
+
+func (o *output) errorMessages() string {
+	msgs := []string{}
+
+	for _, m := range o.Smartctl.Messages {
+		if m.Severity == "error" {
+			msgs = append(msgs, m.String)
+		}
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (o *output) smartStatus() string {
+	switch {
+	case o.SmartStatus == nil:
+		return common.SmartStatusUnknown
+	case o.SmartStatus.Passed:
+		return common.SmartStatusOK
+	default:
+		return common.SmartStatusFailed
+	}
+}
+
+func firmware(version string) *common.Firmware {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return nil
+	}
+
+	fw := common.NewFirmwareObj()
+	fw.Installed = version
+
+	return fw
+}
+
+// vendorFromModel returns the normalized vendor identified in the given model or model family
+func vendorFromModel(model string) string {
+	if v := common.VendorFromString(model); v != "" {
+		return v
+	}
+
+	if v := common.FormatVendorName(model); v != model {
+		return v
+	}
+
+	return ""
+}
+
+func (o *output) setATA(drive *common.Drive) {
+	drive.Protocol = "SATA"
+	drive.Model = strings.TrimSpace(o.ModelName)
+	drive.Firmware = firmware(o.FirmwareVersion)
+	drive.CapableSpeedGbps = o.InterfaceSpeed.Max.gbps()
+	drive.NegotiatedSpeedGbps = o.InterfaceSpeed.Current.gbps()
+
+	if o.WWN != nil {
+		drive.WWN = fmt.Sprintf("%x%06x%09x", o.WWN.NAA, o.WWN.OUI, o.WWN.ID)
+	}
+
+	// a rotation rate of 0 indicates a solid state device
+	if o.RotationRate != nil {
+		if *o.RotationRate == 0 {
+			drive.Type = common.SlugDriveTypeSATASSD
+		} else {
+			drive.Type = common.SlugDriveTypeSATAHDD
+		}
+	}
+
+	for _, a := range o.ATASmartAttributes.Table {
+		drive.SmartAttributes = append(drive.SmartAttributes, &common.DriveSmartAttributes{
+			Name:            a.Name,
+			NormalizedValue: a.Value,
+			Worst:           a.Worst,
+			Threshold:       a.Thresh,
+			PreFailure:      a.Flags.Prefailure,
+			UpdatedOnline:   a.Flags.UpdatedOnline,
+		})
+
+		if a.WhenFailed != "" {
+			drive.SmartErrors = append(drive.SmartErrors, fmt.Sprintf("attribute %d %s failed: %s", a.ID, a.Name, a.WhenFailed))
+		}
+	}
+
+	if count := o.ATASmartErrorLog.Summary.Count; count > 0 {
+		drive.SmartErrors = append(drive.SmartErrors, fmt.Sprintf("%d errors logged in the ATA error log", count))
+	}
+}
+
+func (o *output) setSCSI(drive *common.Drive) {
+	// the transport protocol is reported as "SAS (SPL-3)"
+	drive.Protocol = "SCSI"
+	if fields := strings.Fields(o.SCSITransportProtocol.Name); len(fields) > 0 {
+		drive.Protocol = fields[0]
+	}
+
+	drive.Model = strings.TrimSpace(o.SCSIProduct)
+	drive.Vendor = vendorFromModel(strings.TrimSpace(o.SCSIVendor))
+	drive.Firmware = firmware(o.SCSIRevision)
+	drive.WWN = strings.TrimPrefix(o.LogicalUnitID, "0x")
+
+	if drive.Model == "" {
+		drive.Model = strings.TrimSpace(o.SCSIModelName)
+	}
+
+	if o.RotationRate != nil && *o.RotationRate > 0 {
+		drive.Metadata = map[string]string{"rotation_rate": fmt.Sprint(*o.RotationRate)}
+	}
+
+	if o.SCSIGrownDefectList > 0 {
+		drive.SmartErrors = append(drive.SmartErrors, fmt.Sprintf("%d elements in the grown defect list", o.SCSIGrownDefectList))
+	}
+
+	for _, op := range []string{"read", "write", "verify"} {
+		if n := o.SCSIErrorCounterLog[op].TotalUncorrectedErrors; n > 0 {
+			drive.SmartErrors = append(drive.SmartErrors, fmt.Sprintf("%d uncorrected %s errors", n, op))
+		}
+	}
+}
+
+func (o *output) setNVMe(drive *common.Drive) {
+	drive.Protocol = "NVMe"
+	drive.Type = common.SlugDriveTypePCIeNVMEeSSD
+	drive.Model = strings.TrimSpace(o.ModelName)
+	drive.Firmware = firmware(o.FirmwareVersion)
+
+	if o.NVMePCIVendor.ID != 0 {
+		drive.PCIVendorID = fmt.Sprintf("%04x", o.NVMePCIVendor.ID)
+	}
+
+	if drive.CapacityBytes == 0 {
+		drive.CapacityBytes = o.NVMeTotalCapacity
+	}
+
+	if len(o.NVMeNamespaces) > 0 {
+		ns := o.NVMeNamespaces[0]
+
+		if drive.BlockSizeBytes == 0 {
+			drive.BlockSizeBytes = ns.FormattedLBASize
+		}
+
+		if ns.EUI64 != nil {
+			drive.WWN = fmt.Sprintf("eui.%06x%010x", ns.EUI64.OUI, ns.EUI64.ExtID)
+		}
+	}
+
+	log := o.NVMeSmartHealthInformationLog
+	if log == nil {
+		return
+	}
+
+	drive.SmartAttributes = []*common.DriveSmartAttributes{
+		{Name: "critical_warning", NormalizedValue: log.CriticalWarning},
+		{Name: "available_spare", NormalizedValue: log.AvailableSpare, Threshold: log.AvailableSpareThreshold, PreFailure: true},
+		{Name: "percentage_used", NormalizedValue: log.PercentageUsed},
+		{Name: "media_errors", NormalizedValue: log.MediaErrors},
+		{Name: "num_err_log_entries", NormalizedValue: log.NumErrLogEntries},
+	}
+
+	if log.CriticalWarning != 0 {
+		drive.SmartErrors = append(drive.SmartErrors, fmt.Sprintf("critical warning 0x%02x", log.CriticalWarning))
+	}
+
+	if log.AvailableSpare < log.AvailableSpareThreshold {
+		drive.SmartErrors = append(drive.SmartErrors,
+			fmt.Sprintf("available spare %d%% below threshold %d%%", log.AvailableSpare, log.AvailableSpareThreshold))
+	}
+
+	if log.MediaErrors > 0 {
+		drive.SmartErrors = append(drive.SmartErrors, fmt.Sprintf("%d media errors", log.MediaErrors))
+	}
+}
+
+// UpdateDevice sets the SMART data and attributes of the given Drive on the matching Device drive,
+// drives are matched by serial and then ID. The Drive is added when no match is found.
+func UpdateDevice(d *common.Device, drive *common.Drive) {
+	for _, existing := range d.Drives {
+		if existing == nil {
+			continue
+		}
+
+		serialMatch := drive.Serial != "" && strings.EqualFold(existing.Serial, drive.Serial)
+		idMatch := existing.Serial == "" && drive.ID != "" && existing.ID == drive.ID
+
+		if serialMatch || idMatch {
+			mergeDrive(existing, drive)
+			return
+		}
+	}
+
+	d.Drives = append(d.Drives, drive)
+}
+
+// mergeDrive sets the SMART data on dst and fills its empty attributes from src
+func mergeDrive(dst, src *common.Drive) {
+	dst.SmartStatus = src.SmartStatus
+	dst.SmartErrors = src.SmartErrors
+	dst.SmartAttributes = src.SmartAttributes
+
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+
+	fill(&dst.Vendor, src.Vendor)
+	fill(&dst.Model, src.Model)
+	fill(&dst.ProductName, src.ProductName)
+	fill(&dst.Serial, src.Serial)
+	fill(&dst.Description, src.Description)
+	fill(&dst.PCIVendorID, src.PCIVendorID)
+	fill(&dst.WWN, src.WWN)
+	fill(&dst.Protocol, src.Protocol)
+	fill(&dst.Type, src.Type)
+
+	if dst.CapacityBytes == 0 {
+		dst.CapacityBytes = src.CapacityBytes
+	}
+
+	if dst.BlockSizeBytes == 0 {
+		dst.BlockSizeBytes = src.BlockSizeBytes
+	}
+
+	if dst.CapableSpeedGbps == 0 {
+		dst.CapableSpeedGbps = src.CapableSpeedGbps
+	}
+
+	if dst.NegotiatedSpeedGbps == 0 {
+		dst.NegotiatedSpeedGbps = src.NegotiatedSpeedGbps
+	}
+
+	if dst.Firmware == nil {
+		dst.Firmware = src.Firmware
+	}
+}
@@ -0,0 +1,177 @@
+package smartctl
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+)
+
+func parseFixture(t *testing.T, name string) *common.Drive {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	drive, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return drive
+}
+
+func TestParse(t *testing.T) {
+	testcases := []struct {
+		fixture     string
+		expected    common.Drive
+		firmware    string
+		attributes  int
+		smartErrors []string
+	}{
+		{
+			fixture: "ata_micron_5200.json",
+			expected: common.Drive{
+				Common: common.Common{
+					Vendor:      common.VendorMicron,
+					Model:       "Micron_5200_MTFDDAK480TDN",
+					ProductName: "Micron_5200_MTFDDAK480TDN",
+					Serial:      "19502A1B2C3D",
+					Description: "Micron 5100 Pro / 5200 SSDs",
+					LogicalName: "/dev/sda",
+				},
+				ID:                  "/dev/sda",
+				Type:                common.SlugDriveTypeSATASSD,
+				WWN:                 "500a0750b5fec33d",
+				Protocol:            "SATA",
+				SmartStatus:         common.SmartStatusOK,
+				CapacityBytes:       480103981056,
+				BlockSizeBytes:      512,
+				CapableSpeedGbps:    6,
+				NegotiatedSpeedGbps: 6,
+			},
+			firmware:   "D1MU020",
+			attributes: 4,
+		},
+		{
+			fixture: "scsi_hgst_huh721212al5200.json",
+			expected: common.Drive{
+				Common: common.Common{
+					Vendor:      common.VendorHGST,
+					Model:       "HUH721212AL5200",
+					ProductName: "HUH721212AL5200",
+					Serial:      "8DGA1B2C",
+					LogicalName: "/dev/sdb",
+					Metadata:    map[string]string{"rotation_rate": "7200"},
+				},
+				ID:             "/dev/sdb",
+				WWN:            "5000cca2a1b2c3d4",
+				Protocol:       "SAS",
+				SmartStatus:    common.SmartStatusOK,
+				CapacityBytes:  12000138625024,
+				BlockSizeBytes: 4096,
+			},
+			firmware:    "A3D0",
+			smartErrors: []string{"12 elements in the grown defect list", "2 uncorrected write errors"},
+		},
+		{
+			fixture: "nvme_samsung_pm983.json",
+			expected: common.Drive{
+				Common: common.Common{
+					Vendor:      common.VendorSamsung,
+					Model:       "SAMSUNG MZ1LB960HAJQ-00007",
+					ProductName: "SAMSUNG MZ1LB960HAJQ-00007",
+					Serial:      "S435NA0N123456",
+					LogicalName: "/dev/nvme0",
+					PCIVendorID: "144d",
+				},
+				ID:             "/dev/nvme0",
+				Type:           common.SlugDriveTypePCIeNVMEeSSD,
+				WWN:            "eui.0025385908d3ef32",
+				Protocol:       "NVMe",
+				SmartStatus:    common.SmartStatusOK,
+				CapacityBytes:  960197124096,
+				BlockSizeBytes: 512,
+			},
+			firmware:   "EDA7602Q",
+			attributes: 5,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			drive := parseFixture(t, tc.fixture)
+
+			if drive.Firmware == nil || drive.Firmware.Installed != tc.firmware {
+				t.Errorf("expected firmware %q, got: %v", tc.firmware, drive.Firmware)
+			}
+
+			if len(drive.SmartAttributes) != tc.attributes {
+				t.Errorf("expected %d SMART attributes, got: %d", tc.attributes, len(drive.SmartAttributes))
+			}
+
+			if !reflect.DeepEqual(drive.SmartErrors, tc.smartErrors) {
+				t.Errorf("expected SMART errors %v, got: %v", tc.smartErrors, drive.SmartErrors)
+			}
+
+			// compare the remaining attributes
+			drive.Firmware, drive.SmartAttributes, drive.SmartErrors = nil, nil, nil
+
+			if !reflect.DeepEqual(*drive, tc.expected) {
+				t.Errorf("expected:\n%+v\ngot:\n%+v", tc.expected, *drive)
+			}
+		})
+	}
+}
+
+func TestParseATASmartAttributes(t *testing.T) {
+	drive := parseFixture(t, "ata_micron_5200.json")
+
+	expected := &common.DriveSmartAttributes{
+		Name:            "Reallocate_NAND_Blk_Cnt",
+		NormalizedValue: 100,
+		Worst:           100,
+		Threshold:       10,
+		PreFailure:      true,
+		UpdatedOnline:   true,
+	}
+
+	if !reflect.DeepEqual(drive.SmartAttributes[1], expected) {
+		t.Errorf("expected %+v, got: %+v", expected, drive.SmartAttributes[1])
+	}
+}
+
+func TestParseOpenFailed(t *testing.T) {
+	f, err := os.Open("testdata/open_failed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	if _, err := Parse(f); err == nil {
+		t.Error("expected error for a device that failed to open")
+	}
+}
+
+func TestUpdateDevice(t *testing.T) {
+	d := common.NewDevice()
+	d.Drives = []*common.Drive{{Common: common.Common{Serial: "19502A1B2C3D"}, ID: "/dev/sda", StorageController: "pci@0000:43:00.0"}}
+
+	UpdateDevice(&d, parseFixture(t, "ata_micron_5200.json"))
+	UpdateDevice(&d, parseFixture(t, "nvme_samsung_pm983.json"))
+
+	if len(d.Drives) != 2 {
+		t.Fatalf("expected 2 drives, got: %d", len(d.Drives))
+	}
+
+	sda := d.Drives[0]
+	if sda.SmartStatus != common.SmartStatusOK || sda.StorageController != "pci@0000:43:00.0" || sda.Type != common.SlugDriveTypeSATASSD {
+		t.Errorf("unexpected merged drive: %+v", sda)
+	}
+}
@@ -0,0 +1,133 @@
+package common
+
+// StorageControllerTree is a StorageController along with its virtual disks and attached drives
+type StorageControllerTree struct {
+	// Controller is nil for the tree holding software RAID virtual disks
+	// and virtual disks referring to a controller not present on the Device.
+	Controller   *StorageController `json:"controller,omitempty"`
+	VirtualDisks []*VirtualDiskTree `json:"virtual_disks,omitempty"`
+	// Drives lists all drives attached to the controller, including those not part of a virtual disk.
+	Drives []*Drive `json:"drives,omitempty"`
+}
+
+// VirtualDiskTree is a VirtualDisk along with its resolved member drives
+type VirtualDiskTree struct {
+	VirtualDisk *VirtualDisk `json:"virtual_disk"`
+	Drives      []*Drive     `json:"drives,omitempty"`
+}
+
+// FindStorageController returns the StorageController with the given ID, or nil if none matches
+func (d *Device) FindStorageController(id string) *StorageController {
+	if id == "" {
+		return nil
+	}
+
+	for _, c := range d.StorageControllers {
+		if c != nil && c.ID == id {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// FindDrive returns the Drive with the given ID, or nil if none matches
+func (d *Device) FindDrive(id string) *Drive {
+	if id == "" {
+		return nil
+	}
+
+	for _, drive := range d.Drives {
+		if drive != nil && drive.ID == id {
+			return drive
+		}
+	}
+
+	return nil
+}
+
+// VirtualDiskDrives returns the member Drives of the given VirtualDisk,
+// drive IDs not present on the Device are skipped.
+func (d *Device) VirtualDiskDrives(vd *VirtualDisk) []*Drive {
+	drives := []*Drive{}
+
+	if vd == nil {
+		return drives
+	}
+
+	for _, id := range vd.DriveIDs {
+		if drive := d.FindDrive(id); drive != nil {
+			drives = append(drives, drive)
+		}
+	}
+
+	return drives
+}
+
+// StorageControllerVirtualDisks returns the VirtualDisks owned by the StorageController with the given ID,
+// an empty ID matches no VirtualDisks as software RAID virtual disks reference no controller.
+func (d *Device) StorageControllerVirtualDisks(id string) []*VirtualDisk {
+	vds := []*VirtualDisk{}
+
+	if id == "" {
+		return vds
+	}
+
+	for _, vd := range d.VirtualDisks {
+		if vd != nil && vd.StorageController == id {
+			vds = append(vds, vd)
+		}
+	}
+
+	return vds
+}
+
+// StorageTree rebuilds the storage controller -> virtual disk -> drive hierarchy from the ID references
+// on the Device VirtualDisks and Drives.
+//
+// A tree is returned for each StorageController in the order they are listed, software RAID virtual
+// disks and virtual disks with an unknown controller are grouped in a trailing tree with a nil Controller.
+func (d *Device) StorageTree() []*StorageControllerTree {
+	trees := []*StorageControllerTree{}
+	known := map[string]bool{}
+
+	for _, c := range d.StorageControllers {
+		if c == nil {
+			continue
+		}
+
+		if c.ID != "" {
+			known[c.ID] = true
+		}
+
+		tree := &StorageControllerTree{Controller: c, Drives: []*Drive{}}
+
+		for _, vd := range d.StorageControllerVirtualDisks(c.ID) {
+			tree.VirtualDisks = append(tree.VirtualDisks, &VirtualDiskTree{VirtualDisk: vd, Drives: d.VirtualDiskDrives(vd)})
+		}
+
+		for _, drive := range d.Drives {
+			if drive != nil && c.ID != "" && drive.StorageController == c.ID {
+				tree.Drives = append(tree.Drives, drive)
+			}
+		}
+
+		trees = append(trees, tree)
+	}
+
+	orphans := &StorageControllerTree{}
+
+	for _, vd := range d.VirtualDisks {
+		if vd == nil || (vd.StorageController != "" && known[vd.StorageController]) {
+			continue
+		}
+
+		orphans.VirtualDisks = append(orphans.VirtualDisks, &VirtualDiskTree{VirtualDisk: vd, Drives: d.VirtualDiskDrives(vd)})
+	}
+
+	if len(orphans.VirtualDisks) > 0 {
+		trees = append(trees, orphans)
+	}
+
+	return trees
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestStorageTree(t *testing.T) {
+	d := NewDevice()
+	d.StorageControllers = []*StorageController{{ID: "RAID.Integrated.1-1"}}
+	d.Drives = []*Drive{
+		{ID: "Disk.Bay.0", StorageController: "RAID.Integrated.1-1"},
+		{ID: "Disk.Bay.1", StorageController: "RAID.Integrated.1-1"},
+		{ID: "Disk.Bay.2", StorageController: "RAID.Integrated.1-1"},
+		{ID: "nvme0n1"},
+		{ID: "nvme1n1"},
+	}
+	d.VirtualDisks = []*VirtualDisk{
+		{
+			ID:                 "Disk.Virtual.0:RAID.Integrated.1-1",
+			RaidType:           "RAID1",
+			RaidImplementation: SlugRAIDImplHardware,
+			StorageController:  "RAID.Integrated.1-1",
+			DriveIDs:           []string{"Disk.Bay.0", "Disk.Bay.1"},
+		},
+		{
+			ID:                 "md0",
+			RaidType:           "RAID1",
+			RaidImplementation: SlugRAIDImplLinuxSoftware,
+			DriveIDs:           []string{"nvme0n1", "nvme1n1", "nvme2n1"},
+		},
+	}
+
+	trees := d.StorageTree()
+	if len(trees) != 2 {
+		t.Fatalf("expected 2 trees, got: %d", len(trees))
+	}
+
+	hw := trees[0]
+	if hw.Controller != d.StorageControllers[0] {
+		t.Errorf("expected controller %v, got: %v", d.StorageControllers[0], hw.Controller)
+	}
+
+	if len(hw.Drives) != 3 {
+		t.Errorf("expected 3 controller drives, got: %d", len(hw.Drives))
+	}
+
+	if len(hw.VirtualDisks) != 1 || len(hw.VirtualDisks[0].Drives) != 2 {
+		t.Fatalf("expected a single virtual disk with 2 drives, got: %v", hw.VirtualDisks)
+	}
+
+	if hw.VirtualDisks[0].Drives[1] != d.Drives[1] {
+		t.Errorf("expected drive %v, got: %v", d.Drives[1], hw.VirtualDisks[0].Drives[1])
+	}
+
+	sw := trees[1]
+	if sw.Controller != nil {
+		t.Errorf("expected nil controller for software RAID, got: %v", sw.Controller)
+	}
+
+	// nvme2n1 is not present on the device and is skipped
+	if len(sw.VirtualDisks) != 1 || len(sw.VirtualDisks[0].Drives) != 2 {
+		t.Errorf("expected a single virtual disk with 2 drives, got: %v", sw.VirtualDisks)
+	}
+}
+
+func TestStorageTreeEmptyControllerID(t *testing.T) {
+	d := NewDevice()
+	d.StorageControllers = []*StorageController{{SupportedRAIDTypes: "None"}}
+	d.Drives = []*Drive{{ID: "sda"}, {ID: "sdb"}}
+	d.VirtualDisks = []*VirtualDisk{
+		{
+			ID:                 "md0",
+			RaidType:           "RAID1",
+			RaidImplementation: SlugRAIDImplLinuxSoftware,
+			DriveIDs:           []string{"sda", "sdb"},
+		},
+	}
+
+	if vds := d.StorageControllerVirtualDisks(""); len(vds) != 0 {
+		t.Errorf("expected no virtual disks for an empty controller ID, got: %v", vds)
+	}
+
+	trees := d.StorageTree()
+	if len(trees) != 2 {
+		t.Fatalf("expected 2 trees, got: %d", len(trees))
+	}
+
+	if len(trees[0].VirtualDisks) != 0 {
+		t.Errorf("expected no virtual disks under the controller without an ID, got: %v", trees[0].VirtualDisks)
+	}
+
+	if trees[1].Controller != nil || len(trees[1].VirtualDisks) != 1 {
+		t.Errorf("expected the software RAID virtual disk in the trailing tree, got: %v", trees[1])
+	}
+}
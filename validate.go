@@ -0,0 +1,283 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+var (
+	errRuleExists  = errors.New("a validation rule is already registered with the name")
+	errRuleInvalid = errors.New("invalid validation rule")
+)
+
+// Violation is a failed validation check on a Device or one of its components
+type Violation struct {
+	Rule      string `json:"rule"`
+	Component string `json:"component"`
+	Identity  string `json:"identity,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Message   string `json:"message"`
+}
+
+func (v Violation) String() string {
+	s := v.Rule + ": " + v.Component
+
+	if v.Identity != "" {
+		s += "[" + v.Identity + "]"
+	}
+
+	if v.Field != "" {
+		s += "." + v.Field
+	}
+
+	return s + ": " + v.Message
+}
+
+// ValidationRule checks a Device and returns the violations found
+type ValidationRule struct {
+	Name        string
+	Description string
+	Check       func(d *Device) []Violation
+}
+
+var (
+	validationRulesMu sync.RWMutex
+	validationRules   = map[string]ValidationRule{}
+)
+
+func init() {
+	for _, r := range builtinValidationRules {
+		if err := RegisterValidationRule(r); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterValidationRule adds a rule to the set evaluated by Validate
+func RegisterValidationRule(r ValidationRule) error {
+	if r.Name == "" || r.Check == nil {
+		return fmt.Errorf("%w : %q", errRuleInvalid, r.Name)
+	}
+
+	validationRulesMu.Lock()
+	defer validationRulesMu.Unlock()
+
+	if _, exists := validationRules[r.Name]; exists {
+		return fmt.Errorf("%w : %s", errRuleExists, r.Name)
+	}
+
+	validationRules[r.Name] = r
+
+	return nil
+}
+
+// UnregisterValidationRule removes the rule with the given name from the set evaluated by Validate
+func UnregisterValidationRule(name string) {
+	validationRulesMu.Lock()
+	defer validationRulesMu.Unlock()
+
+	delete(validationRules, name)
+}
+
+// ValidationRules returns the registered rules ordered by name
+func ValidationRules() []ValidationRule {
+	validationRulesMu.RLock()
+	defer validationRulesMu.RUnlock()
+
+	rules := make([]ValidationRule, 0, len(validationRules))
+	for _, r := range validationRules {
+		rules = append(rules, r)
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	return rules
+}
+
+// Validate checks the Device against the registered rules and returns the violations found
+func Validate(d *Device) []Violation {
+	return ValidateWith(d, ValidationRules()...)
+}
+
+// ValidateWith checks the Device against the given rules only
+func ValidateWith(d *Device, rules ...ValidationRule) []Violation {
+	violations := []Violation{}
+
+	if d == nil {
+		return violations
+	}
+
+	for _, r := range rules {
+		for _, v := range r.Check(d) {
+			v.Rule = r.Name
+			violations = append(violations, v)
+		}
+	}
+
+	return violations
+}
+
+var builtinValidationRules = []ValidationRule{
+	{
+		Name:        "cpu-threads",
+		Description: "CPU Threads must be greater than or equal to Cores",
+		Check:       checkCPUThreads,
+	},
+	{
+		Name:        "memory-size",
+		Description: "populated Memory modules must have a SizeBytes greater than zero",
+		Check:       checkMemorySize,
+	},
+	{
+		Name:        "nic-port-mac",
+		Description: "NICPort MacAddress must be a well formed 48-bit MAC address",
+		Check:       checkNICPortMacAddress,
+	},
+	{
+		Name:        "drive-smart-status",
+		Description: "Drive SmartStatus must be one of the SmartStatus constants",
+		Check:       checkDriveSmartStatus,
+	},
+	{
+		Name:        "vendor-normalized",
+		Description: "Vendor names must be normalized with FormatVendorName",
+		Check:       checkVendorNormalized,
+	},
+}
+
+func checkCPUThreads(d *Device) []Violation {
+	violations := []Violation{}
+
+	for i, cpu := range d.CPUs {
+		if cpu == nil || cpu.Threads == 0 {
+			continue
+		}
+
+		if cpu.Threads < cpu.Cores {
+			violations = append(violations, Violation{
+				Component: SlugCPU,
+				Identity:  componentIdentity(cpu, i),
+				Field:     "Threads",
+				Message:   fmt.Sprintf("threads %d is less than cores %d", cpu.Threads, cpu.Cores),
+			})
+		}
+	}
+
+	return violations
+}
+
+// memoryPopulated returns true when the attributes indicate a module is installed in the slot
+func memoryPopulated(m *Memory) bool {
+	return m.Serial != "" || m.PartNumber != "" || m.Model != "" || m.ProductName != ""
+}
+
+func checkMemorySize(d *Device) []Violation {
+	violations := []Violation{}
+
+	for i, m := range d.Memory {
+		if m == nil || !memoryPopulated(m) {
+			continue
+		}
+
+		if m.SizeBytes <= 0 {
+			violations = append(violations, Violation{
+				Component: SlugPhysicalMem,
+				Identity:  componentIdentity(m, i),
+				Field:     "SizeBytes",
+				Message:   fmt.Sprintf("populated memory module has size %d", m.SizeBytes),
+			})
+		}
+	}
+
+	return violations
+}
+
+func checkNICPortMacAddress(d *Device) []Violation {
+	violations := []Violation{}
+
+	check := func(nicIdentity string, nic *NIC) {
+		for i, port := range nic.NICPorts {
+			if port == nil || port.MacAddress == "" {
+				continue
+			}
+
+			hw, err := net.ParseMAC(port.MacAddress)
+			if err == nil && len(hw) == 6 {
+				continue
+			}
+
+			violations = append(violations, Violation{
+				Component: SlugNICPort,
+				Identity:  nicIdentity + "/" + componentIdentity(port, i),
+				Field:     "MacAddress",
+				Message:   fmt.Sprintf("malformed MAC address %q", port.MacAddress),
+			})
+		}
+	}
+
+	if d.BMC != nil && d.BMC.NIC != nil {
+		check(SlugBMC, d.BMC.NIC)
+	}
+
+	for i, nic := range d.NICs {
+		if nic != nil {
+			check(componentIdentity(nic, i), nic)
+		}
+	}
+
+	return violations
+}
+
+func checkDriveSmartStatus(d *Device) []Violation {
+	violations := []Violation{}
+
+	for i, drive := range d.Drives {
+		if drive == nil {
+			continue
+		}
+
+		switch drive.SmartStatus {
+		case "", SmartStatusOK, SmartStatusFailed, SmartStatusUnknown:
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Component: SlugDrive,
+			Identity:  componentIdentity(drive, i),
+			Field:     "SmartStatus",
+			Message:   fmt.Sprintf("unknown SMART status %q", drive.SmartStatus),
+		})
+	}
+
+	return violations
+}
+
+func checkVendorNormalized(d *Device) []Violation {
+	violations := []Violation{}
+
+	check := func(component, identity, vendor string) {
+		if vendor == "" {
+			return
+		}
+
+		if normalized := FormatVendorName(vendor); normalized != vendor {
+			violations = append(violations, Violation{
+				Component: component,
+				Identity:  identity,
+				Field:     "Vendor",
+				Message:   fmt.Sprintf("vendor %q is not normalized, expected %q", vendor, normalized),
+			})
+		}
+	}
+
+	check(deviceComponent, "", d.Vendor)
+
+	for _, ref := range d.Components() {
+		check(ref.Slug, ref.Identity, ref.Common.Vendor)
+	}
+
+	return violations
+}
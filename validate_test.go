@@ -0,0 +1,80 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	d := NewDevice()
+	d.Vendor = "Dell Inc."
+	d.CPUs = []*CPU{
+		{Common: Common{Vendor: VendorIntel}, Slot: "CPU.Socket.1", Cores: 32, Threads: 64},
+		{Common: Common{Vendor: VendorIntel}, Slot: "CPU.Socket.2", Cores: 32, Threads: 16},
+	}
+	d.Memory = []*Memory{
+		{Common: Common{Serial: "36A1B2C3"}, Slot: "DIMM.A1"},
+		{Slot: "DIMM.A2"},
+	}
+	d.NICs = []*NIC{
+		{
+			Common: Common{Serial: "nic-0"},
+			NICPorts: []*NICPort{
+				{ID: "1", MacAddress: "b8:59:9f:00:00:01"},
+				{ID: "2", MacAddress: "b8:59:9f:00:00"},
+			},
+		},
+	}
+	d.Drives = []*Drive{
+		{Common: Common{Serial: "drive-0"}, SmartStatus: SmartStatusOK},
+		{Common: Common{Serial: "drive-1"}, SmartStatus: "PASSED"},
+	}
+
+	expected := map[string]bool{
+		"vendor-normalized: Device.Vendor: vendor \"Dell Inc.\" is not normalized, expected \"dell\"":   true,
+		"cpu-threads: CPU[slot=CPU.Socket.2].Threads: threads 16 is less than cores 32":                 true,
+		"memory-size: PhysicalMemory[serial=36A1B2C3].SizeBytes: populated memory module has size 0":    true,
+		"nic-port-mac: NICPort[serial=nic-0/id=2].MacAddress: malformed MAC address \"b8:59:9f:00:00\"": true,
+		"drive-smart-status: Drive[serial=drive-1].SmartStatus: unknown SMART status \"PASSED\"":        true,
+	}
+
+	violations := Validate(&d)
+	if len(violations) != len(expected) {
+		t.Fatalf("expected %d violations, got: %v", len(expected), violations)
+	}
+
+	for _, v := range violations {
+		if !expected[v.String()] {
+			t.Errorf("unexpected violation: %s", v)
+		}
+	}
+}
+
+func TestRegisterValidationRule(t *testing.T) {
+	rule := ValidationRule{
+		Name: "test-serial-required",
+		Check: func(d *Device) []Violation {
+			if d.Serial == "" {
+				return []Violation{{Component: deviceComponent, Field: "Serial", Message: "serial is required"}}
+			}
+
+			return nil
+		},
+	}
+
+	if err := RegisterValidationRule(rule); err != nil {
+		t.Fatal(err)
+	}
+
+	defer UnregisterValidationRule(rule.Name)
+
+	if err := RegisterValidationRule(rule); err == nil {
+		t.Error("expected error registering a duplicate rule")
+	}
+
+	d := NewDevice()
+
+	violations := Validate(&d)
+	if len(violations) != 1 || violations[0].Rule != rule.Name {
+		t.Errorf("expected a single %s violation, got: %v", rule.Name, violations)
+	}
+}
@@ -0,0 +1,292 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	errVendorExists  = errors.New("a vendor is already registered with the name")
+	errVendorName    = errors.New("invalid vendor name")
+	errVendorPattern = errors.New("invalid vendor pattern")
+)
+
+// Confidence of a vendor match
+const (
+	// VendorConfidenceExact is returned for a match on the vendor name, an alias or a PCI vendor ID
+	VendorConfidenceExact = 1.0
+	// VendorConfidenceWord is returned for a pattern matched at word boundaries, as "intel" in "Intel Corporation"
+	VendorConfidenceWord = 0.8
+	// VendorConfidencePartial is returned for a pattern matched within a word, as "amd" in "camden"
+	VendorConfidencePartial = 0.5
+)
+
+// VendorPattern is a regular expression identifying a vendor in a lower cased string
+type VendorPattern struct {
+	Pattern string
+	// Priority orders the patterns when several vendors match, the highest priority wins and
+	// patterns with the same priority are ordered by registration.
+	// The builtin patterns use priorities between 10 and 180.
+	Priority int
+
+	re *regexp.Regexp
+}
+
+// Vendor describes how a vendor is identified
+type Vendor struct {
+	// Name is the normalized vendor name returned, for example VendorDell
+	Name string
+	// Aliases are names matched exactly, case insensitively, in addition to the Name
+	Aliases []string
+	// Patterns are matched against manufacturer names by FormatVendorName
+	Patterns []VendorPattern
+	// ProductPatterns are matched against product and model strings by VendorFromString
+	ProductPatterns []VendorPattern
+	// PCIVendorIDs are the 4 digit hexadecimal PCI vendor IDs assigned to the vendor
+	PCIVendorIDs []string
+}
+
+// VendorMatch is the vendor identified in a string and the confidence in the match
+type VendorMatch struct {
+	Vendor     string
+	Confidence float64
+}
+
+// vendorPatternRef is a pattern listed in the registry, in match order
+type vendorPatternRef struct {
+	vendor  string
+	pattern *VendorPattern
+	order   int
+}
+
+var (
+	vendorsMu       sync.RWMutex
+	vendors         = map[string]*Vendor{}
+	vendorAliases   = map[string]string{}
+	vendorPCIIDs    = map[string]string{}
+	vendorPatterns  []vendorPatternRef
+	productPatterns []vendorPatternRef
+	vendorOrder     int
+)
+
+// builtinVendors are the vendors registered on init, the pattern priorities
+// retain the precedence of the original switch statements.
+var builtinVendors = []Vendor{
+	{Name: VendorAMD, Patterns: patterns(180, VendorAMD), PCIVendorIDs: []string{"1022", "1002"}},
+	{Name: VendorAsrockrack, Patterns: patterns(170, VendorAsrockrack), PCIVendorIDs: []string{"1849"}},
+	{Name: VendorDell, Patterns: patterns(160, VendorDell), ProductPatterns: patterns(90, "dell"), PCIVendorIDs: []string{"1028"}},
+	{Name: VendorSupermicro, Patterns: patterns(150, VendorSupermicro), PCIVendorIDs: []string{"15d9"}},
+	{Name: VendorQuanta, Patterns: patterns(140, VendorQuanta), PCIVendorIDs: []string{"152d"}},
+	{Name: VendorGigabyte, Patterns: patterns(130, VendorGigabyte), PCIVendorIDs: []string{"1458"}},
+	{Name: VendorIntel, Patterns: patterns(120, VendorIntel), ProductPatterns: patterns(60, "intel "), PCIVendorIDs: []string{"8086", "8087"}},
+	{Name: VendorPacket, Patterns: patterns(110, VendorPacket)},
+	{Name: VendorHynix, Patterns: patterns(100, VendorHynix), PCIVendorIDs: []string{"1c5c"}},
+	{Name: VendorInfineon, Patterns: patterns(90, VendorInfineon), ProductPatterns: patterns(20, "infineon")},
+	{Name: VendorBroadcom, Patterns: patterns(80, VendorBroadcom), PCIVendorIDs: []string{"14e4"}},
+	{Name: VendorMellanox, Patterns: patterns(70, VendorMellanox), ProductPatterns: patterns(30, "connectx4lx"), PCIVendorIDs: []string{"15b3"}},
+	{Name: VendorHGST, Patterns: patterns(60, VendorHGST), ProductPatterns: patterns(70, "hgst "), PCIVendorIDs: []string{"1c58"}},
+	{Name: VendorToshiba, Patterns: patterns(50, VendorToshiba), ProductPatterns: patterns(40, "toshiba"), PCIVendorIDs: []string{"1179"}},
+	{Name: VendorMicron, Patterns: patterns(40, VendorMicron), ProductPatterns: patterns(50, "micron_", "^mtfd"), PCIVendorIDs: []string{"1344"}},
+	{Name: VendorAmericanMegatrends, Aliases: []string{"ami"}, Patterns: patterns(30, VendorAmericanMegatrends)},
+	{Name: VendorSamsung, Patterns: patterns(20, VendorSamsung), PCIVendorIDs: []string{"144d"}},
+	{Name: VendorMarvell, Patterns: patterns(10, VendorMarvell), ProductPatterns: patterns(10, VendorMarvell, VendorMarvellPciID), PCIVendorIDs: []string{VendorMarvellPciID}},
+	{Name: VendorHPE, Aliases: []string{"hpe"}, PCIVendorIDs: []string{"103c", "1590"}},
+	{Name: VendorLSI, Aliases: []string{"lsi"}, ProductPatterns: patterns(80, "lsi3008-it"), PCIVendorIDs: []string{"1000"}},
+}
+
+// patterns returns the VendorPatterns matching the given literal strings, or regular expressions
+// when prefixed with ^.
+func patterns(priority int, literals ...string) []VendorPattern {
+	p := make([]VendorPattern, 0, len(literals))
+
+	for _, l := range literals {
+		if !strings.HasPrefix(l, "^") {
+			l = regexp.QuoteMeta(l)
+		}
+
+		p = append(p, VendorPattern{Pattern: l, Priority: priority})
+	}
+
+	return p
+}
+
+func init() {
+	for i := range builtinVendors {
+		if err := RegisterVendor(builtinVendors[i]); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterVendor adds a Vendor to the registry used by FormatVendorName, VendorFromString and the Match functions.
+func RegisterVendor(v Vendor) error {
+	v.Name = strings.TrimSpace(strings.ToLower(v.Name))
+	if v.Name == "" {
+		return fmt.Errorf("%w : %q", errVendorName, v.Name)
+	}
+
+	compile := func(list []VendorPattern) ([]VendorPattern, error) {
+		compiled := make([]VendorPattern, 0, len(list))
+
+		for _, p := range list {
+			re, err := regexp.Compile(p.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%w : %s: %s", errVendorPattern, p.Pattern, err)
+			}
+
+			p.re = re
+			compiled = append(compiled, p)
+		}
+
+		return compiled, nil
+	}
+
+	var err error
+
+	if v.Patterns, err = compile(v.Patterns); err != nil {
+		return err
+	}
+
+	if v.ProductPatterns, err = compile(v.ProductPatterns); err != nil {
+		return err
+	}
+
+	vendorsMu.Lock()
+	defer vendorsMu.Unlock()
+
+	if _, exists := vendors[v.Name]; exists {
+		return fmt.Errorf("%w : %s", errVendorExists, v.Name)
+	}
+
+	vendors[v.Name] = &v
+
+	for _, alias := range append([]string{v.Name}, v.Aliases...) {
+		alias = strings.TrimSpace(strings.ToLower(alias))
+		if _, exists := vendorAliases[alias]; !exists {
+			vendorAliases[alias] = v.Name
+		}
+	}
+
+	for _, id := range v.PCIVendorIDs {
+		id = normalizePCIVendorID(id)
+		if _, exists := vendorPCIIDs[id]; !exists {
+			vendorPCIIDs[id] = v.Name
+		}
+	}
+
+	for i := range v.Patterns {
+		vendorPatterns = append(vendorPatterns, vendorPatternRef{vendor: v.Name, pattern: &v.Patterns[i], order: vendorOrder})
+		vendorOrder++
+	}
+
+	for i := range v.ProductPatterns {
+		productPatterns = append(productPatterns, vendorPatternRef{vendor: v.Name, pattern: &v.ProductPatterns[i], order: vendorOrder})
+		vendorOrder++
+	}
+
+	sortPatterns(vendorPatterns)
+	sortPatterns(productPatterns)
+
+	return nil
+}
+
+func sortPatterns(list []vendorPatternRef) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].pattern.Priority != list[j].pattern.Priority {
+			return list[i].pattern.Priority > list[j].pattern.Priority
+		}
+
+		return list[i].order < list[j].order
+	})
+}
+
+// Vendors returns the names of the registered vendors, sorted
+func Vendors() []string {
+	vendorsMu.RLock()
+	defer vendorsMu.RUnlock()
+
+	names := make([]string, 0, len(vendors))
+	for name := range vendors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// MatchVendorName identifies the vendor in the given manufacturer name.
+//
+// The name is matched exactly against the registered vendor names and aliases,
+// and then against the vendor Patterns in order of precedence.
+func MatchVendorName(name string) (VendorMatch, bool) {
+	v := strings.TrimSpace(strings.ToLower(name))
+
+	vendorsMu.RLock()
+	defer vendorsMu.RUnlock()
+
+	if vendor, exists := vendorAliases[v]; exists {
+		return VendorMatch{Vendor: vendor, Confidence: VendorConfidenceExact}, true
+	}
+
+	return matchPatterns(vendorPatterns, v)
+}
+
+// MatchVendorProduct identifies the vendor in the given product or model string using the vendor ProductPatterns
+func MatchVendorProduct(s string) (VendorMatch, bool) {
+	vendorsMu.RLock()
+	defer vendorsMu.RUnlock()
+
+	return matchPatterns(productPatterns, strings.ToLower(s))
+}
+
+// MatchVendorPCIID identifies the vendor assigned the given PCI vendor ID, for example "8086" or "0x8086"
+func MatchVendorPCIID(id string) (VendorMatch, bool) {
+	vendorsMu.RLock()
+	defer vendorsMu.RUnlock()
+
+	if vendor, exists := vendorPCIIDs[normalizePCIVendorID(id)]; exists {
+		return VendorMatch{Vendor: vendor, Confidence: VendorConfidenceExact}, true
+	}
+
+	return VendorMatch{}, false
+}
+
+func matchPatterns(list []vendorPatternRef, s string) (VendorMatch, bool) {
+	for _, ref := range list {
+		loc := ref.pattern.re.FindStringIndex(s)
+		if loc == nil {
+			continue
+		}
+
+		// patterns may include the separator, as "intel " does
+		confidence := VendorConfidencePartial
+		if (wordBoundary(s, loc[0]-1) || wordBoundary(s, loc[0])) && (wordBoundary(s, loc[1]) || wordBoundary(s, loc[1]-1)) {
+			confidence = VendorConfidenceWord
+		}
+
+		return VendorMatch{Vendor: ref.vendor, Confidence: confidence}, true
+	}
+
+	return VendorMatch{}, false
+}
+
+// wordBoundary returns true when the byte at the given index is outside the string or not alphanumeric
+func wordBoundary(s string, idx int) bool {
+	if idx < 0 || idx >= len(s) {
+		return true
+	}
+
+	r := rune(s[idx])
+
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func normalizePCIVendorID(id string) string {
+	return strings.TrimPrefix(strings.TrimSpace(strings.ToLower(id)), "0x")
+}
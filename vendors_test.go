@@ -0,0 +1,226 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// vendorTestStrings are matched by both the registry and the switch statements it replaced
+var vendorTestStrings = []string{
+	"", " ", "Dell Inc.", "DELL", "dell", "Supermicro", "SUPERMICRO", "Super Micro Computer", "ASRockRack", "ASRock Rack",
+	"HP", "HPE", "hpe ", "Hewlett Packard Enterprise", "AMI", "American Megatrends Inc.", "LSI", "LSI Logic / Symbios Logic",
+	"AMD", "Advanced Micro Devices, Inc. [AMD]", "AuthenticAMD", "Intel(R) Corporation", "GenuineIntel", "Intel", "Quanta",
+	"Quanta Cloud Technology Inc.", "GIGABYTE", "Giga Computing", "Packet", "Hynix Semiconductor", "SK Hynix", "Infineon",
+	"Broadcom / LSI", "Broadcom Inc. and subsidiaries", "Mellanox Technologies", "HGST", "TOSHIBA", "Micron Technology",
+	"Samsung", "Marvell Technology Group Ltd.", "To Be Filled By O.E.M.", "NVIDIA Corporation", "Camden", "ami-bios",
+	"PowerEdge R640 Dell", "LSI3008-IT", "HGST HUH721212AL5200", "INTEL SSDSC2KB480G8", "Micron_5200_MTFDDAK480TDN",
+	"MTFDDAK480TDN", "TOSHIBA KPM5XVUG960G", "ConnectX4LX", "Infineon SLB9670", "Marvell 88SE9230", "1b4b:9230",
+	"intel dell", "hgst intel ", "toshiba infineon connectx4lx", "micron_ intel ", "SAMSUNG MZ1LB960HAJQ-00007",
+}
+
+func TestVendorRegistryMatchesLegacy(t *testing.T) {
+	for _, s := range vendorTestStrings {
+		if got, expected := FormatVendorName(s), legacyFormatVendorName(s); got != expected {
+			t.Errorf("FormatVendorName(%q) = %q, expected %q", s, got, expected)
+		}
+
+		if got, expected := VendorFromString(s), legacyVendorFromString(s); got != expected {
+			t.Errorf("VendorFromString(%q) = %q, expected %q", s, got, expected)
+		}
+	}
+}
+
+func TestMatchVendorNameConfidence(t *testing.T) {
+	testcases := []struct {
+		name       string
+		vendor     string
+		confidence float64
+	}{
+		{"HPE", VendorHPE, VendorConfidenceExact},
+		{" Dell ", VendorDell, VendorConfidenceExact},
+		{"Intel(R) Corporation", VendorIntel, VendorConfidenceWord},
+		{"GenuineIntel", VendorIntel, VendorConfidencePartial},
+		{"Camden", VendorAMD, VendorConfidencePartial},
+	}
+
+	for _, tc := range testcases {
+		m, ok := MatchVendorName(tc.name)
+		if !ok || m.Vendor != tc.vendor || m.Confidence != tc.confidence {
+			t.Errorf("MatchVendorName(%q) = %+v, %v, expected %s with confidence %v", tc.name, m, ok, tc.vendor, tc.confidence)
+		}
+	}
+
+	if _, ok := MatchVendorName("NVIDIA Corporation"); ok {
+		t.Error("expected no match for an unregistered vendor")
+	}
+}
+
+func TestMatchVendorPCIID(t *testing.T) {
+	for id, expected := range map[string]string{"8086": VendorIntel, "0x15B3": VendorMellanox, "1b4b": VendorMarvell} {
+		if m, ok := MatchVendorPCIID(id); !ok || m.Vendor != expected {
+			t.Errorf("MatchVendorPCIID(%q) = %+v, expected %s", id, m, expected)
+		}
+	}
+
+	if _, ok := MatchVendorPCIID("ffff"); ok {
+		t.Error("expected no match for an unknown PCI vendor ID")
+	}
+}
+
+func TestRegisterVendor(t *testing.T) {
+	if err := RegisterVendor(Vendor{Name: VendorDell}); !errors.Is(err, errVendorExists) {
+		t.Errorf("expected errVendorExists, got %v", err)
+	}
+
+	if err := RegisterVendor(Vendor{Name: " "}); !errors.Is(err, errVendorName) {
+		t.Errorf("expected errVendorName, got %v", err)
+	}
+
+	if err := RegisterVendor(Vendor{Name: "test-invalid", Patterns: []VendorPattern{{Pattern: "("}}}); !errors.Is(err, errVendorPattern) {
+		t.Errorf("expected errVendorPattern, got %v", err)
+	}
+
+	err := RegisterVendor(Vendor{
+		Name:            "nvidia",
+		Aliases:         []string{"nvidia corporation"},
+		Patterns:        []VendorPattern{{Pattern: `\bnvidia\b`}},
+		ProductPatterns: []VendorPattern{{Pattern: `^nvidia `}},
+		PCIVendorIDs:    []string{"10DE"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FormatVendorName("NVIDIA Corporation"); got != "nvidia" {
+		t.Errorf("expected nvidia, got %s", got)
+	}
+
+	if got := VendorFromString("NVIDIA A100-PCIE-40GB"); got != "nvidia" {
+		t.Errorf("expected nvidia, got %s", got)
+	}
+
+	if m, ok := MatchVendorPCIID("10de"); !ok || m.Vendor != "nvidia" {
+		t.Errorf("expected nvidia, got %+v", m)
+	}
+
+	// vendors registered without a priority do not take precedence over the builtin vendors
+	if err := RegisterVendor(Vendor{Name: "test-corp", Patterns: []VendorPattern{{Pattern: "dell"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FormatVendorName("Dell Inc."); got != VendorDell {
+		t.Errorf("expected dell, got %s", got)
+	}
+
+	if err := RegisterVendor(Vendor{Name: "test-priority", Patterns: []VendorPattern{{Pattern: "dell emc", Priority: 1000}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FormatVendorName("Dell EMC"); got != "test-priority" {
+		t.Errorf("expected test-priority, got %s", got)
+	}
+
+	found := false
+
+	for _, name := range Vendors() {
+		found = found || name == "nvidia"
+	}
+
+	if !found {
+		t.Error("expected nvidia to be listed in the registered vendors")
+	}
+}
+
+// The switch statements replaced by the vendor registry, retained to verify the registry returns the same results.
+
+// legacyFormatVendorName compares the given strings to identify and returned a known
+// vendor name. When a match is not found, the string is returned as is.
+//
+// Note: This method will most likely return incorrect matches if the given
+// vendor string is too short and or not unique enough.
+//
+// nolint:gocyclo // This list is expected to be long.
+func legacyFormatVendorName(name string) string {
+	v := strings.TrimSpace(strings.ToLower(name))
+
+	switch v {
+	case "hp", "hpe":
+		return VendorHPE
+	case "ami":
+		return VendorAmericanMegatrends
+	case "lsi":
+		return VendorLSI
+	case "amd":
+		return VendorAMD
+	}
+
+	switch {
+	case strings.Contains(v, VendorAMD):
+		return VendorAMD
+	case strings.Contains(v, VendorAsrockrack):
+		return VendorAsrockrack
+	case strings.Contains(v, VendorDell):
+		return VendorDell
+	case strings.Contains(v, VendorSupermicro):
+		return VendorSupermicro
+	case strings.Contains(v, VendorQuanta):
+		return VendorQuanta
+	case strings.Contains(v, VendorGigabyte):
+		return VendorGigabyte
+	case strings.Contains(v, VendorIntel):
+		return VendorIntel
+	case strings.Contains(v, VendorPacket):
+		return VendorPacket
+	case strings.Contains(v, VendorHynix):
+		return VendorHynix
+	case strings.Contains(v, VendorInfineon):
+		return VendorInfineon
+	case strings.Contains(v, VendorBroadcom):
+		return VendorBroadcom
+	case strings.Contains(v, VendorMellanox):
+		return VendorMellanox
+	case strings.Contains(v, VendorHGST):
+		return VendorHGST
+	case strings.Contains(v, VendorToshiba):
+		return VendorToshiba
+	case strings.Contains(v, VendorMicron):
+		return VendorMicron
+	case strings.Contains(v, VendorAmericanMegatrends):
+		return VendorAmericanMegatrends
+	case strings.Contains(v, VendorSamsung):
+		return VendorSamsung
+	case strings.Contains(v, VendorMarvell):
+		return VendorMarvell
+	default:
+		return name
+	}
+}
+
+// legacyVendorFromString returns the product vendor name, given a product name/model string
+func legacyVendorFromString(s string) string {
+	s = strings.ToLower(s)
+
+	switch {
+	case strings.Contains(s, "dell"):
+		return VendorDell
+	case strings.Contains(s, "lsi3008-it"):
+		return VendorLSI
+	case strings.Contains(s, "hgst "):
+		return VendorHGST
+	case strings.Contains(s, "intel "):
+		return VendorIntel
+	case strings.Contains(s, "micron_"), strings.HasPrefix(s, "mtfd"):
+		return VendorMicron
+	case strings.Contains(s, "toshiba"):
+		return VendorToshiba
+	case strings.Contains(s, "connectx4lx"):
+		return VendorMellanox
+	case strings.Contains(s, "infineon"):
+		return VendorInfineon
+	case strings.Contains(s, VendorMarvell), strings.Contains(s, VendorMarvellPciID):
+		return VendorMarvell
+	default:
+		return ""
+	}
+}